@@ -0,0 +1,100 @@
+// attest.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// version подставляется при сборке: -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+// Аттестация — in-toto Statement v1 с результатом валидации. Подписывается
+// снаружи, например keyless-потоком Sigstore:
+//
+//	cosign attest-blob --type <predicateType> --predicate ... pod.yaml
+//
+// либо целиком как blob; субъекты — sha256 проверенных манифестов.
+const (
+	statementType           = "https://in-toto.io/Statement/v1"
+	validationPredicateType = "https://github.com/forceofprophet/yandexgolang2/validation/v1"
+)
+
+type inTotoStatement struct {
+	Type          string              `json:"_type"`
+	Subject       []inTotoSubject     `json:"subject"`
+	PredicateType string              `json:"predicateType"`
+	Predicate     validationPredicate `json:"predicate"`
+}
+
+type inTotoSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type validationPredicate struct {
+	Validator   validatorInfo       `json:"validator"`
+	ValidatedAt string              `json:"validatedAt"`
+	Passed      bool                `json:"passed"`
+	Findings    []attestationRecord `json:"findings"`
+}
+
+type validatorInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type attestationRecord struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// attestedFile — проверенный файл вместе с результатом.
+type attestedFile struct {
+	path string
+	data []byte
+	res  *validator.Result
+}
+
+// writeAttestation пишет аттестацию files в out. passed — итог прогона
+// с --fail-on, --max-warnings и порогами конфига: тот же, что у кода
+// выхода, а не только наличие ошибок.
+func writeAttestation(out string, files []attestedFile, passed bool) error {
+	st := inTotoStatement{
+		Type:          statementType,
+		Subject:       []inTotoSubject{},
+		PredicateType: validationPredicateType,
+		Predicate: validationPredicate{
+			Validator:   validatorInfo{Name: "yamlvalid", Version: version},
+			ValidatedAt: time.Now().UTC().Format(time.RFC3339),
+			Passed:      passed,
+			Findings:    []attestationRecord{},
+		},
+	}
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		name := filepath.ToSlash(filepath.Clean(f.path))
+		st.Subject = append(st.Subject, inTotoSubject{
+			Name:   name,
+			Digest: map[string]string{"sha256": hex.EncodeToString(sum[:])},
+		})
+		for _, is := range f.res.Issues {
+			st.Predicate.Findings = append(st.Predicate.Findings, attestationRecord{
+				File: name, Line: is.Line, Severity: is.Severity.String(), Message: is.Message(),
+			})
+		}
+	}
+	buf, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, append(buf, '\n'), 0o644)
+}
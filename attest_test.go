@@ -0,0 +1,110 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// readAttestation пишет аттестацию files с итогом passed и читает её
+// обратно.
+func readAttestation(t *testing.T, passed bool, files ...attestedFile) inTotoStatement {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "att.json")
+	if err := writeAttestation(out, files, passed); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st inTotoStatement
+	if err := json.Unmarshal(raw, &st); err != nil {
+		t.Fatalf("%v:\n%s", err, raw)
+	}
+	return st
+}
+
+func TestAttestationStatement(t *testing.T) {
+	data := []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n")
+	res := &validator.Result{}
+	addIssue(res, validator.SeverityWarning, 3, validator.CodeImageCheckFailed, "nginx:1.25", "timeout")
+	st := readAttestation(t, true, attestedFile{path: "./k8s//pod.yaml", data: data, res: res})
+
+	sum := sha256.Sum256(data)
+	if st.Type != statementType || st.PredicateType != validationPredicateType {
+		t.Errorf("types %q, %q", st.Type, st.PredicateType)
+	}
+	if len(st.Subject) != 1 || st.Subject[0].Name != "k8s/pod.yaml" || st.Subject[0].Digest["sha256"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("subject %+v, want k8s/pod.yaml with sha256 of the manifest", st.Subject)
+	}
+	p := st.Predicate
+	if p.Validator.Name != "yamlvalid" || p.ValidatedAt == "" {
+		t.Errorf("predicate %+v", p)
+	}
+	if !p.Passed || len(p.Findings) != 1 || p.Findings[0].Severity != "warning" || p.Findings[0].Line != 3 {
+		t.Fatalf("passed %v, findings %+v", p.Passed, p.Findings)
+	}
+	if msg := p.Findings[0].Message; msg != "cannot check image 'nginx:1.25': timeout" {
+		t.Errorf("message %q, want rendered template", msg)
+	}
+}
+
+// Несколько файлов — по субъекту на файл.
+func TestAttestationFiles(t *testing.T) {
+	failed := &validator.Result{}
+	addIssue(failed, validator.SeverityError, 0, validator.CodeImageNotFound, "nginx:1.25")
+	st := readAttestation(t, false,
+		attestedFile{path: "a.yaml", data: []byte("a"), res: &validator.Result{}},
+		attestedFile{path: "b.yaml", data: []byte("b"), res: failed},
+	)
+	if len(st.Subject) != 2 || st.Subject[0].Name != "a.yaml" || st.Subject[1].Name != "b.yaml" {
+		t.Errorf("subjects %+v, want a.yaml and b.yaml", st.Subject)
+	}
+	p := st.Predicate
+	if p.Passed || len(p.Findings) != 1 || p.Findings[0].File != "b.yaml" || p.Findings[0].Severity != "error" {
+		t.Errorf("predicate %+v, want failed with one error in b.yaml", p)
+	}
+	// чистый прогон — пустой массив находок, а не null
+	st = readAttestation(t, true, attestedFile{path: "a.yaml", res: &validator.Result{}})
+	if !st.Predicate.Passed || st.Predicate.Findings == nil {
+		t.Errorf("predicate %+v, want passed with empty findings", st.Predicate)
+	}
+}
+
+// passed совпадает с кодом выхода: его валят и предупреждения при
+// --fail-on warning, и превышение --max-warnings или порога конфига.
+func TestAttestationPassed(t *testing.T) {
+	warned := &validator.Result{}
+	addIssue(warned, validator.SeverityWarning, 3, validator.CodeImageCheckFailed, "nginx:1.25", "timeout")
+	addIssue(warned, validator.SeverityWarning, 4, validator.CodeImageCheckFailed, "redis:7", "timeout")
+	tests := []struct {
+		name        string
+		thresholds  map[string]int
+		failOn      validator.Severity
+		maxWarnings int
+		want        bool
+	}{
+		{"warnings allowed", nil, validator.SeverityError, -1, true},
+		{"fail-on warning", nil, validator.SeverityWarning, -1, false},
+		{"max-warnings exceeded", nil, validator.SeverityError, 1, false},
+		{"max-warnings kept", nil, validator.SeverityError, 2, true},
+		{"threshold exceeded", map[string]int{validator.CodeImageCheckFailed: 1}, validator.SeverityError, -1, false},
+	}
+	for _, tt := range tests {
+		v, err := validator.New(validator.Config{Thresholds: tt.thresholds})
+		if err != nil {
+			t.Fatal(err)
+		}
+		failed, _ := runFailed(v, []*validator.Result{warned}, tt.failOn, tt.maxWarnings)
+		st := readAttestation(t, !failed, attestedFile{path: "app.yaml", data: []byte("a"), res: warned})
+		if st.Predicate.Passed != tt.want || len(st.Predicate.Findings) != 2 {
+			t.Errorf("%s: passed %v with %d findings, want %v", tt.name, st.Predicate.Passed, len(st.Predicate.Findings), tt.want)
+		}
+	}
+}
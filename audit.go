@@ -0,0 +1,74 @@
+// audit.go
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/report"
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// auditEntry — подавленная находка и исключение, которое её подавило:
+// кто за него отвечает, почему и до какого числа.
+type auditEntry struct {
+	File        string `json:"file"`
+	Line        int    `json:"line,omitempty"`
+	Rule        string `json:"rule"`
+	RuleName    string `json:"ruleName,omitempty"`
+	Message     string `json:"message"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// Mechanism — чем подавлена: feedback (yamlvalid suppress) или
+	// baseline.
+	Mechanism     string `json:"mechanism"`
+	Justification string `json:"justification"`
+	Owner         string `json:"owner,omitempty"`
+	Since         string `json:"since,omitempty"`
+	Expires       string `json:"expires,omitempty"`
+	// Expired — срок исключения истёк, и оно уже ничего не подавляет.
+	Expired bool `json:"expired,omitempty"`
+}
+
+type auditDocument struct {
+	Version int    `json:"version"`
+	Date    string `json:"date"`
+	// Open — находки, которые остались после подавления.
+	Open       int          `json:"open"`
+	Suppressed []auditEntry `json:"suppressed"`
+	// Expired — просроченные отметки yamlvalid suppress: их пора
+	// пересмотреть.
+	Expired []auditEntry `json:"expired"`
+}
+
+// auditReport — вывод --output audit: вместо находок — отчёт об
+// исключениях для аудиторов. Сами находки не печатаются; файлы отзывов
+// и baseline подставляются после загрузки, а отчёт пишется в Finish,
+// когда фильтры уже отработали.
+type auditReport struct {
+	w        io.Writer
+	feedback *feedback
+	baseline *baseline
+}
+
+func (r *auditReport) Start() error { return nil }
+
+func (r *auditReport) Report(string, validator.Issue) error { return nil }
+
+func (r *auditReport) Finish(s report.Summary) error {
+	doc := auditDocument{
+		Version: 1, Date: time.Now().UTC().Format("2006-01-02"),
+		Open: s.Errors + s.Warnings + s.Infos, Suppressed: []auditEntry{}, Expired: []auditEntry{},
+	}
+	if r.feedback != nil {
+		doc.Suppressed = append(doc.Suppressed, r.feedback.suppressed...)
+		doc.Expired = append(doc.Expired, r.feedback.expired...)
+	}
+	if r.baseline != nil {
+		doc.Suppressed = append(doc.Suppressed, r.baseline.suppressed...)
+	}
+	enc := json.NewEncoder(r.w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
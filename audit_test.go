@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/report"
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Отчёт audit перечисляет подавленные находки с обоснованием и владельцем,
+// а просроченные отметки — отдельно: они уже ничего не подавляют.
+func TestAuditReport(t *testing.T) {
+	latest := validator.Issue{Line: 7, Code: "BPR001", Severity: validator.SeverityWarning, Args: []any{"nginx:latest"}}
+	root := validator.Issue{Line: 9, Code: "BPR002", Severity: validator.SeverityWarning}
+	f := feedbackFile{Version: 1, FalsePositives: []feedbackEntry{
+		{Fingerprint: fingerprint("app.yaml", latest), File: "app.yaml", Rule: "BPR001", Justification: "pinned by digest in CD",
+			Reporter: "ops", Date: "2026-01-10", Expires: "2999-01-01"},
+		{Fingerprint: fingerprint("app.yaml", root), File: "app.yaml", Rule: "BPR002", Justification: "legacy image",
+			Date: "2025-01-10", Expires: "2025-06-01"},
+	}}
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "feedback.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fb, err := loadFeedback(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := fileOutcome{name: "app.yaml", path: "app.yaml", res: &validator.Result{Issues: []validator.Issue{latest, root}}}
+	fb.filter(&out)
+	if len(out.res.Issues) != 1 || out.res.Issues[0].Code != "BPR002" {
+		t.Fatalf("after filter %v, want only BPR002 (its mark expired)", out.res.Issues)
+	}
+
+	var buf bytes.Buffer
+	r := &auditReport{w: &buf, feedback: fb}
+	if err := r.Finish(report.Summary{Warnings: 1}); err != nil {
+		t.Fatal(err)
+	}
+	var doc auditDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("%v:\n%s", err, buf.String())
+	}
+	if doc.Open != 1 || len(doc.Suppressed) != 1 || len(doc.Expired) != 1 {
+		t.Fatalf("audit %+v", doc)
+	}
+	s := doc.Suppressed[0]
+	if s.Rule != "BPR001" || s.Line != 7 || s.Mechanism != "feedback" || s.Owner != "ops" ||
+		s.Justification != "pinned by digest in CD" || s.Since != "2026-01-10" || s.Expires != "2999-01-01" {
+		t.Errorf("suppressed entry %+v", s)
+	}
+	if e := doc.Expired[0]; e.Rule != "BPR002" || !e.Expired || e.Mechanism != "feedback" ||
+		e.Justification != "legacy image" || e.Since != "2025-01-10" || e.Expires != "2025-06-01" {
+		t.Errorf("expired entry %+v", e)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"expired": true`)) {
+		t.Errorf("audit output lacks \"expired\": true:\n%s", buf.String())
+	}
+}
+
+// Без подавлений — пустые массивы, а не null.
+func TestAuditReportEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&auditReport{w: &buf}).Finish(report.Summary{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"suppressed": []`)) || !bytes.Contains(buf.Bytes(), []byte(`"expired": []`)) {
+		t.Errorf("empty audit:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,111 @@
+// baseline.go
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// baselineEntry — находка из baseline. Строки нет намеренно: правка выше
+// по файлу сдвигает строки, и старые находки не должны «оживать».
+// Одинаковые находки в одном файле считаются поштучно.
+type baselineEntry struct {
+	File    string `json:"file"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type baselineFile struct {
+	Version  int             `json:"version"`
+	Findings []baselineEntry `json:"findings"`
+}
+
+// baseline подавляет уже известные находки (--baseline), чтобы на старом
+// репозитории прогон падал только на новых. С --write-baseline текущие
+// находки записываются в файл и тоже подавляются.
+type baseline struct {
+	path     string
+	record   bool
+	left     map[baselineEntry]int
+	recorded []baselineEntry
+	// suppressed — подавленные находки для отчёта audit
+	suppressed []auditEntry
+}
+
+func loadBaseline(path string, record bool) (*baseline, error) {
+	b := &baseline{path: path, record: record, left: map[baselineEntry]int{}}
+	if record {
+		return b, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f baselineFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version != 1 {
+		return nil, errors.New("unsupported baseline version")
+	}
+	for _, e := range f.Findings {
+		// baseline, записанный до нормализации путей, тоже подходит
+		e.File = filepath.ToSlash(filepath.Clean(e.File))
+		b.left[e]++
+	}
+	return b, nil
+}
+
+// filter убирает из ещё не напечатанных находок файла те, что есть
+// в baseline; нечитаемые файлы не подавляются никогда.
+func (b *baseline) filter(out *fileOutcome) {
+	if b == nil || out.failed {
+		return
+	}
+	file := issueFile(out)
+	kept := out.res.Issues[:out.reported]
+	for _, is := range out.res.Issues[out.reported:] {
+		e := baselineEntry{File: file, Rule: is.Code, Message: is.Message()}
+		switch {
+		case b.record:
+			b.recorded = append(b.recorded, e)
+		case b.left[e] > 0:
+			b.left[e]--
+		default:
+			kept = append(kept, is)
+			continue
+		}
+		b.suppressed = append(b.suppressed, auditEntry{
+			File: file, Line: is.Line, Rule: is.Code, RuleName: validator.RuleName(is.Code), Message: is.Message(),
+			Fingerprint: is.Fingerprint, Mechanism: "baseline", Justification: "known finding recorded in " + filepath.Base(b.path),
+		})
+	}
+	out.res.Issues = kept
+}
+
+func (b *baseline) save() error {
+	if b == nil || !b.record {
+		return nil
+	}
+	sort.SliceStable(b.recorded, func(i, j int) bool {
+		x, y := b.recorded[i], b.recorded[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		return x.Rule < y.Rule
+	})
+	f := baselineFile{Version: 1, Findings: b.recorded}
+	if f.Findings == nil {
+		f.Findings = []baselineEntry{}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(b.path, append(data, '\n'), 0o644)
+}
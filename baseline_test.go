@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func baselineOutcome(path string, codes ...string) fileOutcome {
+	res := &validator.Result{}
+	for _, code := range codes {
+		res.Issues = append(res.Issues, validator.Issue{Code: code, Severity: validator.SeverityError})
+	}
+	return fileOutcome{name: filepath.Base(path), path: path, res: res}
+}
+
+// Записанные находки подавляются поштучно, новые остаются.
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	b, err := loadBaseline(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := baselineOutcome("k8s/app.yaml", validator.CodeImageNotFound, validator.CodeImageNotFound)
+	b.filter(&out)
+	if len(out.res.Issues) != 0 {
+		t.Fatalf("recording left %d findings", len(out.res.Issues))
+	}
+	if err := b.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if b, err = loadBaseline(path, false); err != nil {
+		t.Fatal(err)
+	}
+	out = baselineOutcome("k8s/app.yaml", validator.CodeImageNotFound, validator.CodeImageNotFound,
+		validator.CodeImageNotFound, validator.CodeImageVulnerable)
+	b.filter(&out)
+	if got := codesOf(out.res); got != validator.CodeImageNotFound+" "+validator.CodeImageVulnerable {
+		t.Errorf("kept %q", got)
+	}
+	// в другом файле та же находка новая
+	out = baselineOutcome("k8s/db.yaml", validator.CodeImageNotFound)
+	b.filter(&out)
+	if len(out.res.Issues) != 1 {
+		t.Errorf("finding in another file suppressed")
+	}
+}
+
+func codesOf(res *validator.Result) string {
+	s := ""
+	for i, is := range res.Issues {
+		if i > 0 {
+			s += " "
+		}
+		s += is.Code
+	}
+	return s
+}
+
+// ./k8s/x.yaml и k8s/x.yaml — один файл и для записи, и для сверки.
+func TestBaselineCleanPaths(t *testing.T) {
+	for _, paths := range [][2]string{{"k8s/x.yaml", "./k8s/x.yaml"}, {"./k8s/x.yaml", "k8s/x.yaml"}} {
+		path := filepath.Join(t.TempDir(), "baseline.json")
+		b, err := loadBaseline(path, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := baselineOutcome(paths[0], validator.CodeImageNotFound)
+		b.filter(&out)
+		if err := b.save(); err != nil {
+			t.Fatal(err)
+		}
+		if b, err = loadBaseline(path, false); err != nil {
+			t.Fatal(err)
+		}
+		out = baselineOutcome(paths[1], validator.CodeImageNotFound)
+		b.filter(&out)
+		if len(out.res.Issues) != 0 {
+			t.Errorf("recorded as %s, filtered as %s: finding kept", paths[0], paths[1])
+		}
+	}
+
+	// старый baseline с неочищенным путём
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	out := baselineOutcome("k8s/x.yaml", validator.CodeImageNotFound)
+	old, _ := json.Marshal(baselineFile{Version: 1, Findings: []baselineEntry{
+		{File: "./k8s//x.yaml", Rule: validator.CodeImageNotFound, Message: out.res.Issues[0].Message()},
+	}})
+	if err := os.WriteFile(path, old, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadBaseline(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.filter(&out)
+	if len(out.res.Issues) != 0 {
+		t.Errorf("finding of an old baseline entry kept")
+	}
+}
@@ -0,0 +1,92 @@
+// clean.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Подкоманда clean убирает из манифестов поля, которые заполняет
+// API-сервер (status, metadata.uid, resourceVersion, managedFields, ...),
+// и делает из выгрузки kubectl get -o yaml манифест, готовый к apply.
+// Правится исходный текст, а не перекодированное дерево, поэтому
+// комментарии и форматирование остаются как были.
+//
+//	kubectl get deploy web -o yaml | yamlvalid clean > web.yaml
+//	yamlvalid clean -w k8s/*.yaml
+func runClean(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	write := fs.Bool("w", false, "rewrite files in place instead of printing to stdout")
+	backup := fs.Bool("backup", false, "with -w, keep the original of each changed file as file.bak")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid clean [-w] [file | -]...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{stdinArg}
+	}
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 2
+	}
+
+	exitCode := 0
+	for i, path := range paths {
+		name := path
+		if path == stdinArg {
+			name = defaultStdinName
+		}
+		var data []byte
+		if path == stdinArg {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = readInput(path)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		res, err := v.Validate(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot unmarshal file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		// остальные находки clean не касаются: он не валидатор
+		var strip []validator.Issue
+		for _, is := range res.Issues {
+			if is.Code == validator.CodeServerField {
+				strip = append(strip, is)
+			}
+		}
+		out, _ := validator.ApplyFixes(data, strip)
+
+		if *write && path != stdinArg {
+			if len(strip) > 0 {
+				if err := writeFileAtomic(path, out, *backup); err != nil {
+					fmt.Fprintf(stderr, "%s: cannot write file: %v\n", name, err)
+					exitCode = 2
+				}
+			}
+			continue
+		}
+		if i > 0 {
+			io.WriteString(stdout, "---\n")
+		}
+		if _, err := stdout.Write(out); err != nil {
+			fmt.Fprintf(stderr, "%s: cannot write manifests: %v\n", name, err)
+			return 2
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const cleanPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web # комментарий остаётся
+spec:
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:1.0
+`
+
+// kubectl get -o yaml: поля API-сервера
+func dirtyPod() string {
+	return strings.Replace(cleanPod, "  name: web #", "  uid: 6f1c0e3a-0000-4000-8000-000000000000\n  resourceVersion: \"42\"\n  name: web #", 1) +
+		"status:\n  phase: Running\n"
+}
+
+func TestClean(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runClean(nil, strings.NewReader(dirtyPod()+"---\n"+cleanPod), &stdout, &stderr)
+	if code != 0 || stdout.String() != cleanPod+"---\n"+cleanPod {
+		t.Errorf("exit %d, stderr %q, output:\n%s", code, stderr.String(), stdout.String())
+	}
+
+	stdout.Reset()
+	if code := runClean(nil, strings.NewReader("a: ["), &stdout, &stderr); code != 2 {
+		t.Errorf("broken YAML: exit %d, want 2", code)
+	}
+}
+
+func TestCleanWrite(t *testing.T) {
+	dir := t.TempDir()
+	dirty, clean := filepath.Join(dir, "dirty.yaml"), filepath.Join(dir, "clean.yaml")
+	for path, data := range map[string]string{dirty: dirtyPod(), clean: cleanPod} {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runClean([]string{"-w", "-backup", dirty, clean}, nil, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Fatalf("exit %d, stdout %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	for path, want := range map[string]string{dirty: cleanPod, dirty + ".bak": dirtyPod(), clean: cleanPod} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(path), data, err, want)
+		}
+	}
+	// нетронутый файл не переписывается
+	if _, err := os.Stat(clean + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("clean.yaml.bak: %v, want no backup", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Сборка ядра как разделяемой библиотеки для вызова из других языков
+// без запуска процесса на каждый файл:
+//
+//	go build -buildmode=c-shared -o libyamlvalid.so ./cmd/libyamlvalid
+//
+// Рядом появится libyamlvalid.h. Строки на входе — UTF-8 с нулём на конце,
+// результат — JSON {"issues": [...]} либо {"error": "..."}; его нужно
+// освободить через FreeResult. Пример на Python:
+//
+//	lib = ctypes.CDLL("./libyamlvalid.so")
+//	lib.Validate.restype = ctypes.c_void_p
+//	lib.FreeResult.argtypes = [ctypes.c_void_p]
+//	ptr = lib.Validate(manifest.encode())
+//	issues = json.loads(ctypes.string_at(ptr))
+//	lib.FreeResult(ptr)
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"sync"
+	"unsafe"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func main() {} // нужен для -buildmode=c-shared
+
+type response struct {
+	Issues []validator.Issue `json:"issues"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// defaultValidator компилирует правила один раз на процесс.
+var defaultValidator = sync.OnceValues(func() (*validator.Validator, error) {
+	return validator.New(validator.Config{})
+})
+
+// Validate проверяет манифест с правилами по умолчанию.
+//
+//export Validate
+func Validate(doc *C.char) *C.char {
+	v, err := defaultValidator()
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return run(v, doc)
+}
+
+// ValidateWithConfig — то же, но с конфигом в формате .yamlvalid.yaml.
+//
+//export ValidateWithConfig
+func ValidateWithConfig(doc, config *C.char) *C.char {
+	var cfg validator.Config
+	if err := yaml.Unmarshal([]byte(C.GoString(config)), &cfg); err != nil {
+		return encode(response{Error: "invalid config: " + err.Error()})
+	}
+	v, err := validator.New(cfg)
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return run(v, doc)
+}
+
+// FreeResult освобождает строку, которую вернули Validate*.
+//
+//export FreeResult
+func FreeResult(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+func run(v *validator.Validator, doc *C.char) *C.char {
+	res, err := v.Validate([]byte(C.GoString(doc)))
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return encode(response{Issues: res.Issues})
+}
+
+func encode(r response) *C.char {
+	if r.Issues == nil && r.Error == "" {
+		r.Issues = []validator.Issue{}
+	}
+	data, _ := json.Marshal(r)
+	return C.CString(string(data)) // память C, освобождается в FreeResult
+}
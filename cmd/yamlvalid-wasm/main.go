@@ -0,0 +1,56 @@
+//go:build js && wasm
+
+// Сборка ядра в WebAssembly для веб-редактора манифестов:
+//
+//	GOOS=js GOARCH=wasm go build -o yamlvalid.wasm ./cmd/yamlvalid-wasm
+//
+// Регистрирует в globalThis функцию yamlvalidValidate(yaml[, configJSON]),
+// которая возвращает JSON {"issues": [...]} либо {"error": "..."}.
+// Обёртка с удобным validate() — в yamlvalid.js рядом.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func main() {
+	js.Global().Set("yamlvalidValidate", js.FuncOf(validate))
+	select {} // Go-рантайм должен жить, пока JS вызывает функцию
+}
+
+type response struct {
+	Issues []validator.Issue `json:"issues"`
+	Error  string            `json:"error,omitempty"`
+}
+
+func validate(_ js.Value, args []js.Value) any {
+	if len(args) == 0 || args[0].Type() != js.TypeString {
+		return encode(response{Error: "validate expects a YAML string"})
+	}
+	var cfg validator.Config
+	if len(args) > 1 && args[1].Type() == js.TypeString {
+		if err := json.Unmarshal([]byte(args[1].String()), &cfg); err != nil {
+			return encode(response{Error: "invalid config: " + err.Error()})
+		}
+	}
+	v, err := validator.New(cfg)
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	res, err := v.Validate([]byte(args[0].String()))
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return encode(response{Issues: res.Issues})
+}
+
+func encode(r response) string {
+	if r.Issues == nil && r.Error == "" {
+		r.Issues = []validator.Issue{}
+	}
+	data, _ := json.Marshal(r)
+	return string(data)
+}
@@ -0,0 +1,24 @@
+// Обёртка над yamlvalid.wasm. Нужен wasm_exec.js из той же версии Go:
+//   cp "$(go env GOROOT)/lib/wasm/wasm_exec.js" .   (Go < 1.24: misc/wasm)
+//
+//   import { load } from "./yamlvalid.js";
+//   const yamlvalid = await load("yamlvalid.wasm");
+//   const { issues } = yamlvalid.validate(text);
+import "./wasm_exec.js";
+
+export async function load(url) {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance); // не ждём: main блокируется навсегда
+  return {
+    // validate(yamlString[, config]) -> {issues: [...]}; config — объект
+    // в формате .yamlvalid.yaml. Ошибки разбора YAML бросаются исключением.
+    validate(yamlString, config) {
+      const out = JSON.parse(globalThis.yamlvalidValidate(yamlString, config ? JSON.stringify(config) : undefined));
+      if (out.error) {
+        throw new Error(out.error);
+      }
+      return out;
+    },
+  };
+}
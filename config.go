@@ -0,0 +1,206 @@
+// config.go
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile ищется в рабочем каталоге, если --config не задан.
+const defaultConfigFile = ".yamlvalid.yaml"
+
+// loadConfig читает конфиг по явному пути; без пути — .yamlvalid.yaml,
+// если он есть. Отсутствие файла по умолчанию не ошибка.
+func loadConfig(path string) (*validator.Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &validator.Config{}, nil
+		}
+		return nil, err
+	}
+	cfg := &validator.Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadInventory читает инвентарь пулов узлов для --cluster-inventory —
+// тот же формат, что секция cluster конфига.
+func loadInventory(path string) (validator.ClusterInventory, error) {
+	var inv validator.ClusterInventory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return inv, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&inv); err != nil && err != io.EOF {
+		return inv, err
+	}
+	return inv, nil
+}
+
+// gateFlag собирает --feature-gate name[=true|false], можно через запятую
+// и несколько раз.
+type gateFlag map[string]bool
+
+func (g gateFlag) String() string { return "" }
+
+func (g gateFlag) Set(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		name, val, hasVal := strings.Cut(strings.TrimSpace(item), "=")
+		on := true
+		if hasVal {
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return fmt.Errorf("feature gate %s: %v", name, err)
+			}
+			on = b
+		}
+		if !validator.FeatureGateKnown(name) {
+			return fmt.Errorf("unknown feature gate '%s'", name)
+		}
+		g[strings.ToLower(name)] = on
+	}
+	return nil
+}
+
+// packFlag собирает --rule-pack name, можно через запятую и несколько раз.
+type packFlag []string
+
+func (p *packFlag) String() string { return strings.Join(*p, ",") }
+
+func (p *packFlag) Set(s string) error {
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if !validator.RulePackKnown(name) {
+			return fmt.Errorf("unknown rule pack '%s'", name)
+		}
+		*p = append(*p, name)
+	}
+	return nil
+}
+
+// presetFlag — --preset, имя проверяется сразу, чтобы ошибку не
+// приписали файлу конфига.
+type presetFlag string
+
+func (p *presetFlag) String() string { return string(*p) }
+
+func (p *presetFlag) Set(s string) error {
+	if !validator.PresetKnown(s) {
+		return fmt.Errorf("unknown preset '%s' (available: %s)", s, strings.Join(validator.Presets(), ", "))
+	}
+	*p = presetFlag(s)
+	return nil
+}
+
+// apiVersionFlag собирает --api-version, можно через запятую и несколько
+// раз.
+type apiVersionFlag []string
+
+func (a *apiVersionFlag) String() string { return strings.Join(*a, ",") }
+
+func (a *apiVersionFlag) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if !validator.ValidAPIVersion(v) {
+			return fmt.Errorf("apiVersion '%s' must be version or group/version", v)
+		}
+		*a = append(*a, v)
+	}
+	return nil
+}
+
+// filterFlags — флаги фильтров документов.
+type filterFlags struct {
+	selector   selectorFlag
+	kinds      patternFlag
+	namespaces patternFlag
+	names      patternFlag
+}
+
+// patternFlag собирает маски --kind, --namespace и --name, можно через
+// запятую и несколько раз.
+type patternFlag []string
+
+func (p *patternFlag) String() string { return strings.Join(*p, ",") }
+
+func (p *patternFlag) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if err := validator.CheckPattern(v); err != nil {
+			return err
+		}
+		*p = append(*p, v)
+	}
+	return nil
+}
+
+// selectorFlag — --selector и -l; селектор проверяется сразу, чтобы
+// ошибка относилась к флагу, а не к конфигу.
+type selectorFlag string
+
+func (f *selectorFlag) String() string { return string(*f) }
+
+func (f *selectorFlag) Set(s string) error {
+	if err := validator.CheckSelector(s); err != nil {
+		return err
+	}
+	*f = selectorFlag(s)
+	return nil
+}
+
+// applyFlags накладывает флаги поверх конфига: флаги важнее. skipUnknown
+// — nil, если --skip-unknown-kinds не задан.
+func applyFlags(cfg *validator.Config, gates gateFlag, packs packFlag, apiVersions apiVersionFlag, filters filterFlags, skipUnknown *bool) {
+	if len(apiVersions) > 0 {
+		if cfg.Enums == nil {
+			cfg.Enums = map[string][]string{}
+		}
+		cfg.Enums["apiVersion"] = append(cfg.Enums["apiVersion"], apiVersions...)
+	}
+	if len(gates) > 0 && cfg.FeatureGates == nil {
+		cfg.FeatureGates = map[string]bool{}
+	}
+	for name, on := range gates {
+		for key := range cfg.FeatureGates {
+			if strings.EqualFold(key, name) {
+				delete(cfg.FeatureGates, key)
+			}
+		}
+		cfg.FeatureGates[name] = on
+	}
+	cfg.RulePacks = append(cfg.RulePacks, packs...)
+	if filters.selector != "" {
+		cfg.Selector = string(filters.selector)
+	}
+	if len(filters.kinds) > 0 {
+		cfg.Kinds = filters.kinds
+	}
+	if len(filters.namespaces) > 0 {
+		cfg.Namespaces = filters.namespaces
+	}
+	if len(filters.names) > 0 {
+		cfg.Names = filters.names
+	}
+	if skipUnknown != nil {
+		cfg.SkipUnknownKinds = *skipUnknown
+	}
+}
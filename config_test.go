@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func TestGateFlag(t *testing.T) {
+	g := gateFlag{}
+	if err := g.Set("sctp, Sctp=false"); err != nil || g["sctp"] {
+		t.Errorf("Set: %v, gates %v", err, g)
+	}
+	for _, s := range []string{"ipv6", "sctp=maybe"} {
+		if err := g.Set(s); err == nil {
+			t.Errorf("Set(%q) accepted", s)
+		}
+	}
+}
+
+func TestPackFlag(t *testing.T) {
+	var p packFlag
+	if err := p.Set("recommended-labels,strict"); err == nil || !strings.Contains(err.Error(), "unknown rule pack 'strict'") {
+		t.Errorf("error %v, want unknown rule pack", err)
+	}
+}
+
+func TestAPIVersionFlag(t *testing.T) {
+	var a apiVersionFlag
+	if err := a.Set("apps/v1, networking.k8s.io/v1beta1"); err != nil || len(a) != 2 {
+		t.Errorf("Set: %v, versions %v", err, a)
+	}
+	for _, s := range []string{"apps", "Apps/v1", "apps/v1/x", "v1.2"} {
+		if err := a.Set(s); err == nil {
+			t.Errorf("Set(%q) accepted", s)
+		}
+	}
+}
+
+func TestSelectorFlag(t *testing.T) {
+	var f selectorFlag
+	if err := f.Set("app=web,tier notin (cache)"); err != nil || f != "app=web,tier notin (cache)" {
+		t.Errorf("Set: %v, selector %q", err, f)
+	}
+	if err := f.Set("app=web,"); err == nil {
+		t.Error("bad selector accepted")
+	}
+}
+
+func TestPatternFlag(t *testing.T) {
+	var p patternFlag
+	if err := p.Set("Pod, Deploy*"); err != nil || len(p) != 2 || p[1] != "Deploy*" {
+		t.Errorf("Set: %v, patterns %q", err, p)
+	}
+	if err := p.Set("web-[a"); err == nil {
+		t.Error("bad pattern accepted")
+	}
+}
+
+func TestPresetFlag(t *testing.T) {
+	var p presetFlag
+	if err := p.Set("strict"); err != nil || p != "strict" {
+		t.Errorf("Set: %v, preset %q", err, p)
+	}
+	if err := p.Set("paranoid"); err == nil || !strings.Contains(err.Error(), "available: minimal, standard, strict") {
+		t.Errorf("error %v, want list of presets", err)
+	}
+}
+
+// Флаги важнее конфига: гейт из флага заменяет одноимённый в любом регистре.
+func TestApplyFlags(t *testing.T) {
+	cfg := validator.Config{FeatureGates: map[string]bool{"SCTP": true}, RulePacks: []string{"recommended-labels"}}
+	skip := true
+	applyFlags(&cfg, gateFlag{"sctp": false}, packFlag{"recommended-labels"}, apiVersionFlag{"apps/v1"}, filterFlags{selector: "app=web", kinds: patternFlag{"Deploy*"}}, &skip)
+	if len(cfg.FeatureGates) != 1 || cfg.FeatureGates["sctp"] {
+		t.Errorf("feature gates %v, want sctp off", cfg.FeatureGates)
+	}
+	if len(cfg.RulePacks) != 2 || !cfg.SkipUnknownKinds || len(cfg.Enums["apiVersion"]) != 1 || cfg.Selector != "app=web" || len(cfg.Kinds) != 1 || cfg.Names != nil {
+		t.Errorf("config %+v", cfg)
+	}
+}
+
+// --skip-unknown-kinds важнее и конфига, и умолчания режима каталога.
+func TestApplyFlagsSkipUnknown(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		config bool
+		flag   *bool
+		want   bool
+	}{
+		{false, nil, false},
+		{true, nil, true},
+		{true, &off, false},
+		{false, &on, true},
+	}
+	for _, tt := range tests {
+		cfg := validator.Config{SkipUnknownKinds: tt.config}
+		applyFlags(&cfg, nil, nil, nil, filterFlags{}, tt.flag)
+		if cfg.SkipUnknownKinds != tt.want {
+			t.Errorf("config %v, flag %v: SkipUnknownKinds = %v, want %v", tt.config, tt.flag, cfg.SkipUnknownKinds, tt.want)
+		}
+	}
+}
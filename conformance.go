@@ -0,0 +1,398 @@
+// conformance.go
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Подкоманда conformance — сверка с настоящим API-сервером: каждый файл
+// корпуса проверяется валидатором и отправляется в кластер через kubectl
+// apply --dry-run=server. Расхождения решений — ложные срабатывания
+// (валидатор отверг, сервер принял) и пропуски (наоборот) — печатаются,
+// в конце — сводка. Кластер нужен одноразовый: kind или envtest. У
+// envtest нет kubeconfig-контекста, только адрес: с -server решение
+// берётся у API-сервера напрямую, без kubectl.
+//
+//	kind create cluster --name conformance
+//	yamlvalid conformance --context kind-conformance -r corpus/
+//	yamlvalid conformance -server https://127.0.0.1:6443 -certificate-authority ca.crt -r corpus/
+func runConformance(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("conformance", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	recursive := fs.Bool("r", false, "descend into subdirectories of directory arguments")
+	kubectl := fs.String("kubectl", "kubectl", "kubectl `binary` used for server-side dry-run")
+	kubeContext := fs.String("context", "", "kubeconfig `context` of the test cluster (default current context)")
+	timeout := fs.Duration("timeout", 30*time.Second, "give up on the server decision for a file after this long")
+	verbose := fs.Bool("v", false, "print the verdict for every file, not only disagreements")
+	serverURL := fs.String("server", "", "API server `URL` to ask directly instead of kubectl (envtest)")
+	token := fs.String("token", "", "bearer token for -server")
+	caFile := fs.String("certificate-authority", "", "CA certificate `file` of -server")
+	// -server ходит в API-сервер теми же лимитами, что и онлайн-проверки
+	netRPS := fs.Float64("net-rps", 10, "max requests per second to -server (0 = unlimited)")
+	fs.IntVar(&netConcurrency, "net-concurrency", netConcurrency, "max concurrent requests to -server")
+	fs.IntVar(&netRetries, "net-retries", netRetries, "retries with exponential backoff for failed requests to -server")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid conformance [-r] [-context name] [-kubectl binary | -server url] <path-to-yaml | dir | glob>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	filter, err := newPathFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	paths, err := expandInputs(fs.Args(), *recursive, filter)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	netLimiter = newRateLimiter(*netRPS)
+	var server judge
+	if *serverURL != "" {
+		if server, err = newAPIDryRunner(*serverURL, *token, *caFile, *timeout); err != nil {
+			fmt.Fprintf(stderr, "-server: %v\n", err)
+			return 2
+		}
+	} else {
+		if _, err := exec.LookPath(*kubectl); err != nil {
+			fmt.Fprintf(stderr, "conformance needs kubectl and a test cluster: %v\n", err)
+			return 2
+		}
+		server = dryRunner{kubectl: *kubectl, context: *kubeContext, timeout: *timeout}
+	}
+
+	exitCode := 0
+	var sum conformanceSummary
+	for _, path := range paths {
+		data, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", path, err)
+			exitCode = 2
+			continue
+		}
+		ours := validatorVerdict(v, path, data)
+		theirs := server.verdict(data)
+		switch {
+		case theirs.inconclusive:
+			sum.inconclusive++
+			fmt.Fprintf(stdout, "%s: inconclusive: %s\n", path, theirs.reason)
+		case ours.accepted == theirs.accepted:
+			sum.agree++
+			if *verbose {
+				fmt.Fprintf(stdout, "%s: agree (%s)\n", path, acceptance(ours.accepted))
+			}
+		case theirs.accepted:
+			sum.falsePositives++
+			fmt.Fprintf(stdout, "%s: false positive: server accepts, validator rejects: %s\n", path, ours.reason)
+		default:
+			sum.falseNegatives++
+			fmt.Fprintf(stdout, "%s: false negative: validator accepts, server rejects: %s\n", path, theirs.reason)
+		}
+	}
+	fmt.Fprintln(stdout, sum)
+	if sum.falsePositives+sum.falseNegatives > 0 && exitCode == 0 {
+		exitCode = 1
+	}
+	return exitCode
+}
+
+// verdict — решение одной стороны по файлу и причина отказа.
+type verdict struct {
+	accepted bool
+	// inconclusive: сервер не дал решения по манифесту — нет связи,
+	// неймспейса, CRD или прав
+	inconclusive bool
+	reason       string
+}
+
+func acceptance(accepted bool) string {
+	if accepted {
+		return "accepted"
+	}
+	return "rejected"
+}
+
+type conformanceSummary struct {
+	agree, falsePositives, falseNegatives, inconclusive int
+}
+
+func (s conformanceSummary) String() string {
+	total := s.agree + s.falsePositives + s.falseNegatives
+	rate := func(n int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return 100 * float64(n) / float64(total)
+	}
+	return fmt.Sprintf("%d files compared: %d agree, %d false positives (%.1f%%), %d false negatives (%.1f%%), %d inconclusive",
+		total, s.agree, s.falsePositives, rate(s.falsePositives), s.falseNegatives, rate(s.falseNegatives), s.inconclusive)
+}
+
+// validatorVerdict — файл отвергнут, если в нём есть ошибки или он не
+// разбирается; предупреждения сервер тоже пропустил бы.
+func validatorVerdict(v *validator.Validator, path string, data []byte) verdict {
+	res, err := v.ValidateFile(path, data)
+	if err != nil {
+		return verdict{reason: "cannot unmarshal file content: " + err.Error()}
+	}
+	for _, is := range res.Issues {
+		if is.Severity == validator.SeverityError {
+			return verdict{reason: (&validator.IssueError{Issue: is}).Error()}
+		}
+	}
+	return verdict{accepted: true}
+}
+
+// judge — API-сервер, решающий, принять ли манифесты файла.
+type judge interface {
+	verdict(data []byte) verdict
+}
+
+// dryRunner спрашивает решение у API-сервера: apply, а не create, чтобы
+// уже существующие в кластере объекты не давали AlreadyExists.
+type dryRunner struct {
+	kubectl string
+	context string
+	timeout time.Duration
+}
+
+// rejections — начала ответов kubectl, означающие отказ по содержимому
+// манифеста; прочие ошибки — о кластере, а не о манифесте.
+var rejections = []string{
+	"Error from server (Invalid)",
+	"Error from server (BadRequest)",
+	"error: error validating",
+	"error: error parsing",
+	"error: unable to decode",
+}
+
+func (d dryRunner) verdict(data []byte) verdict {
+	args := []string{"apply", "--dry-run=server", "--validate=strict", "-o", "name", "-f", "-"}
+	if d.context != "" {
+		args = append([]string{"--context", d.context}, args...)
+	}
+	ctx := context.Background()
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, d.kubectl, args...)
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || ctx.Err() != nil {
+			return verdict{inconclusive: true, reason: err.Error()}
+		}
+		first, _, _ := strings.Cut(msg, "\n")
+		for _, prefix := range rejections {
+			if strings.HasPrefix(msg, prefix) {
+				return verdict{reason: first}
+			}
+		}
+		return verdict{inconclusive: true, reason: first}
+	}
+	return verdict{accepted: true}
+}
+
+// apiDryRunner спрашивает API-сервер напрямую: каждый документ файла
+// отправляется server-side apply с dryRun=All, ресурс kind'а берётся из
+// discovery, как это делает kubectl.
+type apiDryRunner struct {
+	server string
+	token  string
+	http   *http.Client
+	// resources — ресурс и его неймспейсность по apiVersion и kind
+	resources map[string]apiResource
+}
+
+type apiResource struct {
+	name       string
+	namespaced bool
+}
+
+func newAPIDryRunner(server, token, caFile string, timeout time.Duration) (*apiDryRunner, error) {
+	client := &http.Client{Timeout: timeout}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates", caFile)
+		}
+		client.Transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}
+	}
+	return &apiDryRunner{server: strings.TrimRight(server, "/"), token: token, http: client, resources: map[string]apiResource{}}, nil
+}
+
+// verdict — файл принят, если приняты все его документы; первый отказ
+// или неясный ответ решает за весь файл, как у kubectl apply.
+func (d *apiDryRunner) verdict(data []byte) verdict {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return verdict{accepted: true}
+			}
+			return verdict{reason: "error: error parsing: " + err.Error()}
+		}
+		if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+			continue
+		}
+		if v := d.apply(doc.Content[0]); !v.accepted {
+			return v
+		}
+	}
+}
+
+func (d *apiDryRunner) apply(doc *yaml.Node) verdict {
+	var obj struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string `yaml:"kind"`
+		Metadata   struct {
+			Name      string `yaml:"name"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"metadata"`
+	}
+	if err := doc.Decode(&obj); err != nil || obj.APIVersion == "" || obj.Kind == "" {
+		// без apiVersion и kind сервер не знает, куда отправить объект
+		return verdict{reason: "error: unable to decode: apiVersion and kind are required"}
+	}
+	res, err := d.resource(obj.APIVersion, obj.Kind)
+	if err != nil {
+		return verdict{inconclusive: true, reason: err.Error()}
+	}
+	if obj.Metadata.Name == "" {
+		return verdict{reason: "error: metadata.name is required"}
+	}
+	path := "/api/" + obj.APIVersion
+	if strings.Contains(obj.APIVersion, "/") {
+		path = "/apis/" + obj.APIVersion
+	}
+	if res.namespaced {
+		ns := obj.Metadata.Namespace
+		if ns == "" {
+			ns = "default"
+		}
+		path += "/namespaces/" + url.PathEscape(ns)
+	}
+	path += "/" + res.name + "/" + url.PathEscape(obj.Metadata.Name)
+	body, err := yaml.Marshal(doc)
+	if err != nil {
+		return verdict{inconclusive: true, reason: err.Error()}
+	}
+	q := url.Values{"dryRun": {"All"}, "fieldManager": {"yamlvalid"}, "fieldValidation": {"Strict"}, "force": {"true"}}
+	resp, err := d.do(http.MethodPatch, path+"?"+q.Encode(), "application/apply-patch+yaml", body)
+	if err != nil {
+		return verdict{inconclusive: true, reason: err.Error()}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return verdict{accepted: true}
+	}
+	var status struct {
+		Message string `json:"message"`
+		Reason  string `json:"reason"`
+	}
+	json.NewDecoder(resp.Body).Decode(&status)
+	reason := fmt.Sprintf("Error from server (%s): %s", status.Reason, status.Message)
+	// 400 и 422 — отказ по содержимому; 401, 403, 404 неймспейса и 5xx —
+	// о кластере
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity {
+		return verdict{reason: reason}
+	}
+	return verdict{inconclusive: true, reason: reason}
+}
+
+// resource находит ресурс kind'а через discovery группы; ответ
+// кэшируется на весь прогон.
+func (d *apiDryRunner) resource(apiVersion, kind string) (apiResource, error) {
+	key := apiVersion + "/" + kind
+	if r, ok := d.resources[key]; ok {
+		return r, nil
+	}
+	path := "/api/" + apiVersion
+	if strings.Contains(apiVersion, "/") {
+		path = "/apis/" + apiVersion
+	}
+	resp, err := d.do(http.MethodGet, path, "", nil)
+	if err != nil {
+		return apiResource{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return apiResource{}, fmt.Errorf("server does not serve %s (%s)", apiVersion, resp.Status)
+	}
+	var list struct {
+		Resources []struct {
+			Name       string `json:"name"`
+			Kind       string `json:"kind"`
+			Namespaced bool   `json:"namespaced"`
+		} `json:"resources"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return apiResource{}, fmt.Errorf("discovery of %s: %v", apiVersion, err)
+	}
+	for _, r := range list.Resources {
+		// подресурсы (deployments/scale) того же kind'а не годятся
+		if r.Kind == kind && !strings.Contains(r.Name, "/") {
+			d.resources[key] = apiResource{name: r.Name, namespaced: r.Namespaced}
+			return d.resources[key], nil
+		}
+	}
+	return apiResource{}, fmt.Errorf("server has no resource for kind %s in %s", kind, apiVersion)
+}
+
+func (d *apiDryRunner) do(method, path, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, d.server+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if d.token != "" {
+		req.Header.Set("Authorization", "Bearer "+d.token)
+	}
+	return netSend(d.http, req)
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeKubectl — kubectl, который отвергает манифест с объектом rejected,
+// не находит неймспейс missing и принимает остальное.
+func fakeKubectl(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake kubectl is a shell script")
+	}
+	kubectl := filepath.Join(t.TempDir(), "kubectl")
+	script := `#!/bin/sh
+in=$(cat)
+case "$in" in
+*"name: rejected"*) echo 'Error from server (Invalid): Service "rejected" is invalid: spec.ports[0].port: Required value' >&2; exit 1 ;;
+*"namespace: missing"*) echo 'Error from server (NotFound): namespaces "missing" not found' >&2; exit 1 ;;
+esac
+`
+	if err := os.WriteFile(kubectl, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return kubectl
+}
+
+// fakeAPIServer — API-сервер с discovery для v1 и apps/v1: объект с
+// именем rejected он отвергает, неймспейса missing у него нет, прочее
+// принимает.
+func fakeAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	keepNetLimits(t)
+	discovery := map[string]string{
+		"/api/v1": `{"resources": [
+			{"name": "configmaps", "kind": "ConfigMap", "namespaced": true},
+			{"name": "pods", "kind": "Pod", "namespaced": true},
+			{"name": "pods/status", "kind": "Pod", "namespaced": true},
+			{"name": "services", "kind": "Service", "namespaced": true},
+			{"name": "namespaces", "kind": "Namespace", "namespaced": false}]}`,
+		"/apis/apps/v1": `{"resources": [
+			{"name": "deployments", "kind": "Deployment", "namespaced": true}]}`,
+	}
+	status := func(w http.ResponseWriter, code int, reason, msg string) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]string{"kind": "Status", "reason": reason, "message": msg})
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			status(w, http.StatusUnauthorized, "Unauthorized", "Unauthorized")
+			return
+		}
+		if r.Method == http.MethodGet {
+			body, ok := discovery[r.URL.Path]
+			if !ok {
+				status(w, http.StatusNotFound, "NotFound", "the server could not find the requested resource")
+				return
+			}
+			io.WriteString(w, body)
+			return
+		}
+		q := r.URL.Query()
+		if r.Method != http.MethodPatch || q.Get("dryRun") != "All" || r.Header.Get("Content-Type") != "application/apply-patch+yaml" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			status(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "")
+			return
+		}
+		switch {
+		case strings.Contains(r.URL.Path, "/namespaces/missing/"):
+			status(w, http.StatusNotFound, "NotFound", `namespaces "missing" not found`)
+		case strings.HasSuffix(r.URL.Path, "/rejected"):
+			status(w, http.StatusUnprocessableEntity, "Invalid", `Service "rejected" is invalid: spec.ports[0].targetPort: Invalid value`)
+		default:
+			body, _ := io.ReadAll(r.Body)
+			w.Write(body)
+		}
+	}))
+}
+
+// writeCorpus раскладывает файлы корпуса по каталогу и возвращает его и
+// пустой конфиг, чтобы .yamlvalid.yaml рабочего каталога не влиял.
+func writeCorpus(t *testing.T, files map[string]string) (dir, config string) {
+	t.Helper()
+	dir = t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config = filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(config, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, config
+}
+
+var conformanceCorpus = map[string]string{
+	"agree.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  key: value
+`,
+	"positive.yaml": `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  containers:
+    - name: web
+      image: nginx
+      ports:
+        - containerPort: 70000
+`,
+	"negative.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: rejected
+spec:
+  ports:
+    - name: http
+      port: 80
+`,
+	"namespace.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: missing
+`,
+}
+
+func TestConformance(t *testing.T) {
+	kubectl := fakeKubectl(t)
+	keepNetLimits(t)
+	dir, config := writeCorpus(t, conformanceCorpus)
+
+	var stdout, stderr bytes.Buffer
+	code := runConformance([]string{"-config", config, "-kubectl", kubectl, "-v", dir}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("exit %d, want 1; stderr: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	for _, want := range []string{
+		path("agree.yaml") + ": agree (accepted)",
+		path("positive.yaml") + ": false positive: server accepts, validator rejects: ",
+		path("negative.yaml") + `: false negative: validator accepts, server rejects: Error from server (Invalid): Service "rejected" is invalid`,
+		path("namespace.yaml") + `: inconclusive: Error from server (NotFound): namespaces "missing" not found`,
+		"3 files compared: 1 agree, 1 false positives (33.3%), 1 false negatives (33.3%), 1 inconclusive",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "\n") != 5 {
+		t.Errorf("want 4 verdicts and a summary:\n%s", out)
+	}
+}
+
+func TestConformanceAPIServer(t *testing.T) {
+	srv := fakeAPIServer(t)
+	defer srv.Close()
+	// kind, которого сервер не обслуживает, не даёт ответа
+	files := map[string]string{"crd.yaml": "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n"}
+	for name, content := range conformanceCorpus {
+		files[name] = content
+	}
+	dir, config := writeCorpus(t, files)
+
+	var stdout, stderr bytes.Buffer
+	code := runConformance([]string{"-config", config, "-server", srv.URL, "-token", "secret", "-net-rps", "0", "-v", dir}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("exit %d, want 1; stderr: %s", code, stderr.String())
+	}
+	out := stdout.String()
+	path := func(name string) string { return filepath.Join(dir, name) }
+	for _, want := range []string{
+		path("agree.yaml") + ": agree (accepted)",
+		path("positive.yaml") + ": false positive: server accepts, validator rejects: ",
+		path("negative.yaml") + `: false negative: validator accepts, server rejects: Error from server (Invalid): Service "rejected" is invalid`,
+		path("namespace.yaml") + `: inconclusive: Error from server (NotFound): namespaces "missing" not found`,
+		path("crd.yaml") + ": inconclusive: server does not serve example.com/v1",
+		"3 files compared: 1 agree, 1 false positives (33.3%), 1 false negatives (33.3%), 2 inconclusive",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "\n") != 6 {
+		t.Errorf("want 5 verdicts and a summary:\n%s", out)
+	}
+}
+
+// Без прав сервер не решает ничего: все файлы неясны, расхождений нет.
+func TestConformanceUnauthorized(t *testing.T) {
+	srv := fakeAPIServer(t)
+	defer srv.Close()
+	dir, config := writeCorpus(t, map[string]string{"agree.yaml": conformanceCorpus["agree.yaml"]})
+
+	var stdout, stderr bytes.Buffer
+	code := runConformance([]string{"-config", config, "-server", srv.URL, filepath.Join(dir, "agree.yaml")}, &stdout, &stderr)
+	if code != 0 {
+		t.Errorf("exit %d, want 0", code)
+	}
+	if want := "0 files compared: 0 agree, 0 false positives (0.0%), 0 false negatives (0.0%), 1 inconclusive\n"; !strings.HasSuffix(stdout.String(), want) {
+		t.Errorf("got:\n%s\nwant summary %q", stdout.String(), want)
+	}
+}
+
+// С -server запросы идут через общий лимитер и повторы: 429 от
+// API-сервера повторяется, а не делает файл неясным.
+func TestConformanceRetries(t *testing.T) {
+	api := fakeAPIServer(t)
+	defer api.Close()
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits++; hits%2 == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		api.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	dir, config := writeCorpus(t, map[string]string{"agree.yaml": conformanceCorpus["agree.yaml"]})
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-config", config, "-server", srv.URL, "-token", "secret", "-net-rps", "0", "-net-retries", "1", dir}
+	if code := runConformance(args, &stdout, &stderr); code != 0 {
+		t.Errorf("exit %d, stderr %s", code, stderr.String())
+	}
+	if want := "1 files compared: 1 agree"; !strings.HasPrefix(stdout.String(), want) {
+		t.Errorf("got:\n%s\nwant summary %q", stdout.String(), want)
+	}
+	// discovery и apply — по два запроса
+	if hits != 4 {
+		t.Errorf("%d requests, want 4", hits)
+	}
+	if netRetries != 1 {
+		t.Errorf("-net-retries not applied: %d", netRetries)
+	}
+}
+
+// dryRunner различает отказ по манифесту и сбой кластера по ответу
+// kubectl.
+func TestDryRunnerVerdict(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake kubectl is a shell script")
+	}
+	tests := []struct {
+		name, stderr string
+		exit         int
+		want         verdict
+	}{
+		{"accepted", "", 0, verdict{accepted: true}},
+		{"invalid", "Error from server (Invalid): error when creating \"STDIN\": bad\nsecond line", 1,
+			verdict{reason: `Error from server (Invalid): error when creating "STDIN": bad`}},
+		{"no cluster", "error: You must be logged in to the server (Unauthorized)", 1,
+			verdict{inconclusive: true, reason: "error: You must be logged in to the server (Unauthorized)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubectl := filepath.Join(t.TempDir(), "kubectl")
+			script := fmt.Sprintf("#!/bin/sh\ncat >/dev/null\nprintf '%%s' '%s' >&2\nexit %d\n", tt.stderr, tt.exit)
+			if err := os.WriteFile(kubectl, []byte(script), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			got := dryRunner{kubectl: kubectl}.verdict([]byte("kind: ConfigMap\n"))
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// engines.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Подкоманда engines — проверка соответствия разборщиков YAML: каждый
+// файл корпуса проверяется всеми разборщиками сборки, любое расхождение
+// находок с эталонным yaml.v3 печатается и валит прогон. Имеет смысл в
+// сборке с дополнительным разборщиком; тот же корпус гоняет тест
+// go test -tags goccy ./validator:
+//
+//	go build -tags goccy -o yamlvalid . && ./yamlvalid engines -r validator/testdata/engines
+func runEngines(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("engines", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	recursive := fs.Bool("r", false, "descend into subdirectories of directory arguments")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid engines [-r] [-config file] <path-to-yaml | dir | glob>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if len(validator.Engines()) < 2 {
+		fmt.Fprintf(stderr, "only %s is built in, nothing to compare (build with -tags goccy)\n", strings.Join(validator.Engines(), ", "))
+		return 2
+	}
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	filter, err := newPathFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	paths, err := expandInputs(fs.Args(), *recursive, filter)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	exitCode := 0
+	files, differ := 0, 0
+	for _, path := range paths {
+		data, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", path, err)
+			exitCode = 2
+			continue
+		}
+		files++
+		diffs := v.CompareEngines(path, data)
+		if len(diffs) > 0 {
+			differ++
+		}
+		for _, d := range diffs {
+			fmt.Fprintf(stdout, "%s:%d %s %s\n", path, d.Line, d.Engine, d.Text)
+		}
+	}
+	fmt.Fprintf(stderr, "%d files, %d differ from yaml.v3 (engines: %s)\n", files, differ, strings.Join(validator.Engines(), ", "))
+	if differ > 0 && exitCode == 0 {
+		exitCode = 1
+	}
+	return exitCode
+}
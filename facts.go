@@ -0,0 +1,35 @@
+// facts.go
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// fileFacts — сведения об объекте для --emit-facts вместе с файлом, где
+// он описан.
+type fileFacts struct {
+	File string `json:"file"`
+	validator.Facts
+}
+
+// writeFacts пишет в path сведения обо всех объектах прогона одним
+// JSON-массивом; нечитаемые файлы пропускаются.
+func writeFacts(path string, outcomes []fileOutcome) error {
+	all := []fileFacts{}
+	for _, out := range outcomes {
+		if out.failed {
+			continue
+		}
+		for _, f := range out.res.Facts {
+			all = append(all, fileFacts{File: out.name, Facts: f})
+		}
+	}
+	buf, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(buf, '\n'), 0o644)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Сведения идут с именем файла; нечитаемые файлы пропускаются, пустой
+// прогон — пустой массив.
+func TestWriteFacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "facts.json")
+	if err := writeFacts(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+		t.Errorf("empty run: %q, want []", data)
+	}
+
+	outcomes := []fileOutcome{
+		{name: "k8s/app.yaml", res: &validator.Result{Facts: []validator.Facts{{Document: 1, Line: 1, Kind: "Service", Name: "web"}}}},
+		{name: "bad.yaml", failed: true, res: &validator.Result{Facts: []validator.Facts{{Document: 1}}}},
+	}
+	if err := writeFacts(path, outcomes); err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "file": "k8s/app.yaml",
+    "document": 1,
+    "line": 1,
+    "kind": "Service",
+    "name": "web"
+  }
+]
+`
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("facts:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,270 @@
+// feedback.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// defaultFeedbackFile — файл отзывов по умолчанию для yamlvalid suppress.
+const defaultFeedbackFile = ".yamlvalid-feedback.json"
+
+// feedbackEntry — находка, которую команда отметила как ложное
+// срабатывание, с обоснованием. Файл отзывов одновременно подавляет
+// находки (--feedback-file) и служит отчётом о качестве правил: его
+// собирают с разных репозиториев и смотрят, какие правила ошибаются.
+type feedbackEntry struct {
+	Fingerprint   string `json:"fingerprint"`
+	File          string `json:"file"`
+	Rule          string `json:"rule"`
+	RuleName      string `json:"ruleName,omitempty"`
+	Message       string `json:"message"`
+	Justification string `json:"justification"`
+	Reporter      string `json:"reporter,omitempty"`
+	Date          string `json:"date"`
+	// Expires — дата (YYYY-MM-DD), после которой отметка перестаёт
+	// подавлять находку и её надо пересмотреть.
+	Expires string `json:"expires,omitempty"`
+}
+
+// expired — истёк ли срок отметки на дату today (YYYY-MM-DD).
+func (e feedbackEntry) expired(today string) bool {
+	return e.Expires != "" && e.Expires < today
+}
+
+type feedbackFile struct {
+	Version        int             `json:"version"`
+	FalsePositives []feedbackEntry `json:"falsePositives"`
+}
+
+// fingerprint — отпечаток находки: как и в baseline, без строки, чтобы
+// правка выше по файлу его не меняла.
+func fingerprint(file string, is validator.Issue) string {
+	sum := sha256.Sum256([]byte(file + "\x00" + is.Code + "\x00" + is.Message()))
+	return hex.EncodeToString(sum[:6])
+}
+
+// issueFile — путь файла в отпечатках: как его передали, со
+// слэшами; для stdin — имя из --stdin-filename.
+func issueFile(out *fileOutcome) string {
+	if out.path == stdinArg {
+		return out.name
+	}
+	return filepath.ToSlash(filepath.Clean(out.path))
+}
+
+// feedback — загруженный --feedback-file. Просроченные отметки не
+// подавляют находки, а попадают в expired — для отчёта audit.
+type feedback struct {
+	marked     map[string]feedbackEntry
+	expired    []auditEntry
+	suppressed []auditEntry
+}
+
+func loadFeedback(path string) (*feedback, error) {
+	f, err := readFeedbackFile(path)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().UTC().Format("2006-01-02")
+	fb := &feedback{marked: map[string]feedbackEntry{}}
+	for _, e := range f.FalsePositives {
+		if e.expired(today) {
+			fb.expired = append(fb.expired, auditEntry{
+				File: e.File, Rule: e.Rule, RuleName: e.RuleName, Message: e.Message, Fingerprint: e.Fingerprint,
+				Mechanism: "feedback", Justification: e.Justification, Owner: e.Reporter, Since: e.Date,
+				Expires: e.Expires, Expired: true,
+			})
+			continue
+		}
+		fb.marked[e.Fingerprint] = e
+	}
+	return fb, nil
+}
+
+// readFeedbackFile читает файл отзывов; отсутствующий файл — пустой, его
+// создаст первый yamlvalid suppress.
+func readFeedbackFile(path string) (feedbackFile, error) {
+	f := feedbackFile{Version: 1}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return f, err
+	}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, err
+	}
+	if f.Version != 1 {
+		return f, errors.New("unsupported feedback file version")
+	}
+	return f, nil
+}
+
+// filter убирает отмеченные находки файла, а остальным проставляет
+// отпечаток, чтобы их можно было отметить; нечитаемые файлы не трогает.
+func (fb *feedback) filter(out *fileOutcome) {
+	if fb == nil || out.failed {
+		return
+	}
+	file := issueFile(out)
+	kept := out.res.Issues[:out.reported]
+	for _, is := range out.res.Issues[out.reported:] {
+		is.Fingerprint = fingerprint(file, is)
+		e, ok := fb.marked[is.Fingerprint]
+		if !ok {
+			kept = append(kept, is)
+			continue
+		}
+		fb.suppressed = append(fb.suppressed, auditEntry{
+			File: file, Line: is.Line, Rule: is.Code, RuleName: validator.RuleName(is.Code), Message: is.Message(),
+			Fingerprint: is.Fingerprint, Mechanism: "feedback", Justification: e.Justification,
+			Owner: e.Reporter, Since: e.Date, Expires: e.Expires,
+		})
+	}
+	out.res.Issues = kept
+}
+
+// Подкоманда suppress отмечает находку как ложное срабатывание: находит
+// её по отпечатку среди находок файлов и дописывает в файл отзывов.
+//
+//	yamlvalid --feedback-file .yamlvalid-feedback.json k8s/
+//	yamlvalid suppress -reason "probe path is set by the chart" 3fa4c1d2e9b0 k8s/
+func runSuppress(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("suppress", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	feedbackPath := fs.String("feedback-file", defaultFeedbackFile, "JSON `file` to append the false positive to")
+	reason := fs.String("reason", "", "why the finding is a false positive (required)")
+	owner := fs.String("owner", os.Getenv("USER"), "who answers for the exception in audits")
+	expires := fs.String("expires", "", "`date` (YYYY-MM-DD) after which the finding is reported again")
+	configPath := fs.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid suppress -reason text [-owner name] [-expires YYYY-MM-DD] [-feedback-file file] <fingerprint> [path...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 || strings.TrimSpace(*reason) == "" {
+		fs.Usage()
+		return 2
+	}
+	if *expires != "" {
+		if _, err := time.Parse("2006-01-02", *expires); err != nil {
+			fmt.Fprintf(stderr, "-expires: expected YYYY-MM-DD, got '%s'\n", *expires)
+			return 2
+		}
+	}
+	fp, inputs := fs.Arg(0), fs.Args()[1:]
+	recursive := len(inputs) == 0
+	if recursive {
+		inputs = []string{"."}
+	}
+
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	filter, err := newPathFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	paths, err := expandInputs(inputs, recursive, filter)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	f, err := readFeedbackFile(*feedbackPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load feedback file: %v\n", filepath.Base(*feedbackPath), err)
+		return 2
+	}
+	for _, e := range f.FalsePositives {
+		if e.Fingerprint == fp {
+			fmt.Fprintf(stdout, "%s: already marked as false positive\n", fp)
+			return 0
+		}
+	}
+
+	entry, ok := findFingerprint(v, paths, namedFiles(inputs), fp)
+	if !ok {
+		fmt.Fprintf(stderr, "%s: no finding with this fingerprint (findings across files, such as quotas, cannot be suppressed here)\n", fp)
+		return 1
+	}
+	entry.Justification = strings.TrimSpace(*reason)
+	entry.Reporter = strings.TrimSpace(*owner)
+	entry.Date = time.Now().UTC().Format("2006-01-02")
+	entry.Expires = *expires
+	f.FalsePositives = append(f.FalsePositives, entry)
+	sort.SliceStable(f.FalsePositives, func(i, j int) bool {
+		x, y := f.FalsePositives[i], f.FalsePositives[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		return x.Rule < y.Rule
+	})
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err == nil {
+		err = os.WriteFile(*feedbackPath, append(data, '\n'), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot write feedback file: %v\n", filepath.Base(*feedbackPath), err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "%s: %s %s: marked as false positive\n", entry.File, entry.Rule, entry.Message)
+	return 0
+}
+
+// findFingerprint ищет находку по отпечатку так же, как её посчитал
+// основной прогон: после Config.Rules и маскирования секретов.
+func findFingerprint(v *validator.Validator, paths []string, named map[string]bool, fp string) (feedbackEntry, bool) {
+	for _, path := range paths {
+		data, err := readInput(path)
+		if err != nil {
+			continue
+		}
+		rulePath := path
+		if abs, err := filepath.Abs(path); err == nil && !isURL(path) {
+			rulePath = abs
+		}
+		validate := v.ValidateScanned
+		if named[path] {
+			validate = v.ValidateFile
+		}
+		res, err := validate(rulePath, data)
+		if err != nil {
+			continue
+		}
+		v.Adjust(res)
+		res.Redact()
+		file := issueFile(&fileOutcome{name: path, path: path})
+		for _, is := range res.Issues {
+			if fingerprint(file, is) == fp {
+				return feedbackEntry{
+					Fingerprint: fp, File: file, Rule: is.Code, RuleName: validator.RuleName(is.Code),
+					Message: is.Message(),
+				}, true
+			}
+		}
+	}
+	return feedbackEntry{}, false
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+const plan9Pod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+spec:
+  os: {name: plan9}
+  containers:
+    - name: web
+      image: registry.bigbrother.io/web:1.0
+`
+
+// Отпечаток не зависит от строки, но зависит от файла и текста.
+func TestFingerprint(t *testing.T) {
+	is := validator.Issue{Line: 6, Code: "POD007", Args: []any{"plan9"}}
+	fp := fingerprint("k8s/app.yaml", is)
+	if len(fp) != 12 {
+		t.Errorf("fingerprint %q, want 12 hex digits", fp)
+	}
+	moved := is
+	moved.Line = 40
+	if fingerprint("k8s/app.yaml", moved) != fp {
+		t.Error("fingerprint depends on the line")
+	}
+	other := is
+	other.Args = []any{"darwin"}
+	if fingerprint("k8s/app.yaml", other) == fp || fingerprint("k8s/db.yaml", is) == fp {
+		t.Error("different findings share a fingerprint")
+	}
+	if got := issueFile(&fileOutcome{name: "app.yaml", path: "./k8s//app.yaml"}); got != "k8s/app.yaml" {
+		t.Errorf("issueFile = %q", got)
+	}
+	if got := issueFile(&fileOutcome{name: "pod.yaml", path: stdinArg}); got != "pod.yaml" {
+		t.Errorf("stdin issueFile = %q", got)
+	}
+}
+
+func TestSuppress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(path, []byte(plan9Pod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, config := writeCorpus(t, nil)
+	feedbackPath := filepath.Join(dir, "feedback.json")
+	file := filepath.ToSlash(path)
+	fp := fingerprint(file, validator.Issue{Code: "POD007", Args: []any{"plan9"}})
+
+	suppress := func(args ...string) (int, string) {
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"-config", config, "-feedback-file", feedbackPath}, args...)
+		code := runSuppress(args, &stdout, &stderr)
+		return code, stdout.String() + stderr.String()
+	}
+	if code, out := suppress(fp, path); code != 2 || !strings.Contains(out, "usage:") {
+		t.Errorf("without -reason: exit %d, %q", code, out)
+	}
+	if code, out := suppress("-reason", "x", "000000000000", path); code != 1 || !strings.Contains(out, "no finding with this fingerprint") {
+		t.Errorf("unknown fingerprint: exit %d, %q", code, out)
+	}
+	if code, out := suppress("-reason", " plan9 nodes exist ", fp, path); code != 0 || !strings.Contains(out, "POD007 os has unsupported value 'plan9': marked as false positive") {
+		t.Fatalf("suppress: exit %d, %q", code, out)
+	}
+	if code, out := suppress("-reason", "again", fp, path); code != 0 || !strings.Contains(out, "already marked") {
+		t.Errorf("second suppress: exit %d, %q", code, out)
+	}
+
+	data, err := os.ReadFile(feedbackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f feedbackFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.FalsePositives) != 1 {
+		t.Fatalf("entries %+v", f.FalsePositives)
+	}
+	e := f.FalsePositives[0]
+	if e.Fingerprint != fp || e.File != file || e.RuleName != "os-unsupported" || e.Justification != "plan9 nodes exist" || e.Date == "" {
+		t.Errorf("entry %+v", e)
+	}
+
+	// основной прогон с --feedback-file отмеченную находку не выводит,
+	// остальным проставляет отпечаток
+	fb, err := loadFeedback(feedbackPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := fileOutcome{name: "app.yaml", path: path, res: &validator.Result{Issues: []validator.Issue{
+		{Line: 6, Code: "POD007", Args: []any{"plan9"}},
+		{Line: 9, Code: "POD007", Args: []any{"darwin"}},
+	}}}
+	fb.filter(&out)
+	if len(out.res.Issues) != 1 || out.res.Issues[0].Fingerprint == "" {
+		t.Errorf("filtered issues %+v", out.res.Issues)
+	}
+}
@@ -0,0 +1,97 @@
+// fix.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// fixFile применяет правки к файлу на диске и убирает исправленные
+// находки из res; возвращает, сколько их было.
+func fixFile(path string, data []byte, res *validator.Result, backup bool) (int, error) {
+	out, fixed := validator.ApplyFixes(data, res.Issues)
+	kept := res.Issues[:0]
+	count := 0
+	for i, is := range res.Issues {
+		if fixed[i] {
+			count++
+			continue
+		}
+		kept = append(kept, is)
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	if err := writeFileAtomic(path, out, backup); err != nil {
+		return 0, err
+	}
+	res.Issues = kept
+	return count, nil
+}
+
+// writeFileAtomic заменяет файл целиком: пишет временный файл в том же
+// каталоге (rename атомарен только в пределах одной ФС, в том числе на
+// NFS), сбрасывает его на диск и переименовывает поверх. Редактор или
+// параллельный прогон видят либо старый файл, либо новый, но не
+// половину. Права и, где можно, владелец берутся у исходного файла;
+// backup — сохранить исходник рядом как path.bak.
+func writeFileAtomic(path string, data []byte, backup bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if backup {
+		orig, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := replaceFile(path+".bak", orig, info); err != nil {
+			return err
+		}
+	}
+	return replaceFile(path, data, info)
+}
+
+func replaceFile(path string, data []byte, like os.FileInfo) (err error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+base+".yamlvalid-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(like.Mode().Perm()); err != nil {
+		return err
+	}
+	if err = preserveOwner(tmp, like); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("cannot replace %s: %w", path, err)
+	}
+	// сам rename тоже должен пережить сбой питания
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+func preserveOwner(*os.File, os.FileInfo) error { return nil }
@@ -0,0 +1,23 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// preserveOwner отдаёт временный файл владельцу исходного. Сменить
+// владельца может только root; обычному пользователю это и не нужно —
+// он правит свои файлы, — поэтому EPERM не ошибка.
+func preserveOwner(f *os.File, like os.FileInfo) error {
+	st, ok := like.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if err := f.Chown(int(st.Uid), int(st.Gid)); err != nil && !errors.Is(err, syscall.EPERM) {
+		return err
+	}
+	return nil
+}
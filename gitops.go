@@ -0,0 +1,97 @@
+// gitops.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/report"
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Режим gitops-plugin — generate-команда config management plugin'а
+// Argo CD и шаг после сборки в Flux-пайплайнах: манифесты приходят на
+// stdin, при успехе без изменений уходят на stdout (это и есть результат
+// generate), а находки печатаются в stderr, который Argo CD показывает в
+// статусе приложения. Ненулевой код выхода останавливает синхронизацию.
+//
+//	apiVersion: argoproj.io/v1alpha1
+//	kind: ConfigManagementPlugin
+//	metadata:
+//	  name: yamlvalid
+//	spec:
+//	  generate:
+//	    command: [sh, -c]
+//	    args: ["kustomize build . | yamlvalid gitops-plugin"]
+//
+// Настройки — только через окружение, флагов у plugin'а нет. Параметры
+// приложения Argo CD передаёт с префиксом ARGOCD_ENV_, поэтому ищем оба
+// варианта.
+const (
+	envPluginConfig   = "YAMLVALID_CONFIG"    // путь к конфигу
+	envPluginFailWarn = "YAMLVALID_FAIL_WARN" // true — предупреждения тоже валят синхронизацию
+	argoEnvPrefix     = "ARGOCD_ENV_"
+	argoAppName       = "ARGOCD_APP_NAME"
+)
+
+func pluginEnv(name string) string {
+	if v, ok := os.LookupEnv(argoEnvPrefix + name); ok {
+		return v
+	}
+	return os.Getenv(name)
+}
+
+// runGitopsPlugin возвращает код выхода: 0 — манифесты выданы, 1 — есть
+// ошибки валидации, 2 — не прочитан конфиг или вход.
+func runGitopsPlugin(stdin io.Reader, stdout, stderr io.Writer) int {
+	// в сообщениях — имя приложения: в логах контроллера их много
+	name := os.Getenv(argoAppName)
+	if name == "" {
+		name = "<stdin>"
+	}
+
+	configPath := pluginEnv(envPluginConfig)
+	cfg, err := loadConfig(configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(configPath), err)
+		return 2
+	}
+
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", name, err)
+		return 2
+	}
+	res, err := v.Validate(data)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot unmarshal file content: %v\n", name, err)
+		return 2
+	}
+
+	// весь набор приложения приходит одним потоком: проверки по набору
+	// работают так же, как для нескольких файлов в CLI
+	outcomes := []fileOutcome{{name: name, data: data, res: res}}
+	checkQuotas(outcomes, nil)
+	checkReferences(outcomes)
+	v.Adjust(res)
+	res.Redact()
+
+	if err := report.Write(report.NewText(stderr), []string{name}, []*validator.Result{res}); err != nil {
+		return 2
+	}
+	failWarn := strings.EqualFold(pluginEnv(envPluginFailWarn), "true")
+	if res.Failed() || (failWarn && res.FailedAt(validator.SeverityWarning)) {
+		return 1
+	}
+	if _, err := stdout.Write(data); err != nil {
+		fmt.Fprintf(stderr, "%s: cannot write manifests: %v\n", name, err)
+		return 2
+	}
+	return 0
+}
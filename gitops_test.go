@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitopsPlugin(t *testing.T) {
+	cfg := filepath.Join(t.TempDir(), "yamlvalid.yaml")
+	if err := os.WriteFile(cfg, []byte("skipUnknownKinds: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		valid = "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n" +
+			"    - name: web\n      image: registry.bigbrother.io/web:1.0\n" +
+			"      resources:\n        requests: {cpu: 1, memory: 64Mi}\n        limits: {cpu: 1, memory: 64Mi}\n"
+		widget = "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\n"
+	)
+	tests := []struct {
+		name     string
+		env      map[string]string
+		stdin    string
+		code     int
+		stderr   string // подстрока; "" — stderr пустой
+		passThru bool   // манифесты выданы на stdout
+	}{
+		{"valid", nil, valid, 0, "", true},
+		{"error", nil, strings.Replace(valid, "kind: Pod", "kind: Pod\nextra: [", 1), 2, "<stdin>: cannot unmarshal file content", false},
+		{"invalid", map[string]string{"ARGOCD_APP_NAME": "shop"}, strings.Replace(valid, "spec:\n", "spec:\n  os: {name: plan9}\n", 1), 1, "shop:6 os has unsupported value 'plan9'", false},
+		{"warning", nil, widget, 0, "<stdin>:2 warning: kind 'Widget' has no validator, skipped", true},
+		{"fail on warning", map[string]string{"YAMLVALID_FAIL_WARN": "true"}, widget, 1, "warning:", false},
+		// параметры приложения Argo CD приходят с префиксом ARGOCD_ENV_
+		{"argo env prefix", map[string]string{"ARGOCD_ENV_YAMLVALID_FAIL_WARN": "TRUE", "YAMLVALID_FAIL_WARN": "false"}, widget, 1, "warning:", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("YAMLVALID_CONFIG", cfg)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			var stdout, stderr bytes.Buffer
+			code := runGitopsPlugin(strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d; stderr:\n%s", code, tt.code, &stderr)
+			}
+			if tt.stderr == "" && stderr.Len() != 0 || !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr %q, want %q", &stderr, tt.stderr)
+			}
+			if got := stdout.String(); tt.passThru && got != tt.stdin || !tt.passThru && got != "" {
+				t.Errorf("stdout %q, passthrough %v", got, tt.passThru)
+			}
+		})
+	}
+}
+
+func TestGitopsPluginBadConfig(t *testing.T) {
+	t.Setenv("YAMLVALID_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	var stdout, stderr bytes.Buffer
+	if code := runGitopsPlugin(strings.NewReader(""), &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "cannot load config") {
+		t.Errorf("exit code %d, stderr %q; want 2 and config error", code, &stderr)
+	}
+}
@@ -2,4 +2,20 @@ module github.com/forceofprophet/yandexgolang2
 
 go 1.22.12
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/goccy/go-yaml v1.18.0
+	github.com/hashicorp/hcl/v2 v2.22.0
+	github.com/zclconf/go-cty v1.13.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/agext/levenshtein v1.2.1 // indirect
+	github.com/apparentlymart/go-textseg/v13 v13.0.0 // indirect
+	github.com/apparentlymart/go-textseg/v15 v15.0.0 // indirect
+	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
+	golang.org/x/mod v0.8.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.11.0 // indirect
+	golang.org/x/tools v0.6.0 // indirect
+)
@@ -0,0 +1,61 @@
+// guard.go
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// errTimeout — проверка файла не уложилась в --file-timeout.
+var errTimeout = errors.New("validation timed out")
+
+// panicError — паника внутри проверки одного файла.
+type panicError struct{ value any }
+
+func (e panicError) Error() string { return fmt.Sprintf("panic: %v", e.value) }
+
+// guard запускает проверку одного файла так, чтобы паника или зависание
+// на нём стали находкой этого файла, а не обрывали весь прогон. Горутину
+// по таймауту прервать нельзя: она дорабатывает в фоне, но её результат
+// уже никому не нужен.
+func guard(name string, timeout time.Duration, fn func() (*validator.Result, error)) (*validator.Result, error) {
+	type outcome struct {
+		res *validator.Result
+		err error
+	}
+	run := func() (o outcome) {
+		defer func() {
+			if v := recover(); v != nil {
+				// стек — для баг-репорта; в stdout только находка. Паника
+				// правила приходит из рабочей горутины со своим стеком
+				stack := debug.Stack()
+				if wp, ok := v.(validator.WorkerPanic); ok {
+					v, stack = wp.Value, wp.Stack
+				}
+				fmt.Fprintf(os.Stderr, "%s: panic during validation: %v\n%s", name, v, stack)
+				o = outcome{err: panicError{v}}
+			}
+		}()
+		res, err := fn()
+		return outcome{res, err}
+	}
+	if timeout <= 0 {
+		o := run()
+		return o.res, o.err
+	}
+	done := make(chan outcome, 1)
+	go func() { done <- run() }()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.res, o.err
+	case <-timer.C:
+		return nil, errTimeout
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/report"
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func TestGuardPanic(t *testing.T) {
+	tests := map[string]any{
+		"in caller":        "boom",
+		"from rule worker": validator.WorkerPanic{Value: "boom", Stack: []byte("stack")},
+	}
+	for name, value := range tests {
+		for _, timeout := range []time.Duration{0, time.Second} {
+			_, err := guard("app.yaml", timeout, func() (*validator.Result, error) { panic(value) })
+			var pe panicError
+			if !errors.As(err, &pe) || pe.value != "boom" {
+				t.Errorf("%s, timeout %v: err = %v, want panic 'boom'", name, timeout, err)
+			}
+		}
+	}
+}
+
+func TestGuardTimeout(t *testing.T) {
+	_, err := guard("app.yaml", 10*time.Millisecond, func() (*validator.Result, error) {
+		time.Sleep(time.Second)
+		return &validator.Result{}, nil
+	})
+	if !errors.Is(err, errTimeout) {
+		t.Errorf("err = %v, want timeout", err)
+	}
+}
+
+// Сбой проверки — одна ошибка без строки, файл помечен непроверенным
+// (код выхода 2).
+func TestOutcomeFail(t *testing.T) {
+	tests := []struct {
+		code string
+		arg  string
+		want string
+	}{
+		{validator.CodeValidationTimeout, "2s", "app.yaml: validation timed out after 2s, skipped"},
+		{validator.CodeValidationPanic, "boom", "app.yaml: internal error during validation: boom"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		out := fileOutcome{name: "app.yaml"}
+		out.fail(tt.code, tt.arg)
+		out.flush(report.NewText(&buf))
+		if !out.failed || buf.String() != tt.want+"\n" {
+			t.Errorf("%s: failed=%v, output %q, want %q", tt.code, out.failed, buf.String(), tt.want)
+		}
+	}
+}
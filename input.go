@@ -0,0 +1,303 @@
+// input.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// parseArgs разбирает флаги вперемешку с путями: yamlvalid ./k8s --recursive.
+// Пакет flag останавливается на первом позиционном аргументе, поэтому
+// продолжаем разбор после него; всё, что после --, — только пути.
+func parseArgs(args []string) []string {
+	var tail []string
+	for i, a := range args {
+		if a == "--" {
+			args, tail = args[:i], args[i+1:]
+			break
+		}
+	}
+	var paths []string
+	for {
+		flag.CommandLine.Parse(args) // ExitOnError
+		rest := flag.Args()
+		if len(rest) == 0 {
+			break
+		}
+		paths = append(paths, rest[0])
+		args = rest[1:]
+	}
+	return append(paths, tail...)
+}
+
+// stdinArg — путь «-»: манифесты со стандартного ввода, например
+// helm template . | yamlvalid -.
+const (
+	stdinArg         = "-"
+	defaultStdinName = "<stdin>"
+)
+
+// stdinIsPipe — stdin перенаправлен из пайпа или файла, а не терминал:
+// тогда yamlvalid без аргументов читает его, а не печатает usage.
+func stdinIsPipe() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
+// maxFileSize — предел размера входа (--max-file-size): узлы yaml.v3
+// занимают в памяти в десятки раз больше исходного текста, и один
+// гигантский дамп не должен уронить проверку всего каталога.
+var maxFileSize int64 = 64 << 20
+
+func readInput(path string) ([]byte, error) {
+	var r io.Reader = os.Stdin
+	switch {
+	case isURL(path):
+		body, err := fetchURL(path)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		r = body
+	case path != stdinArg:
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	if maxFileSize <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err == nil && int64(len(data)) > maxFileSize {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxFileSize)
+	}
+	return data, err
+}
+
+// isInputFile — файлы, которые берём при обходе каталогов и по маскам:
+// YAML и Terraform с вложенными манифестами; явно указанный файл
+// проверяется при любом расширении.
+func isInputFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml" || ext == ".tf"
+}
+
+// expandInputs раскрывает каталоги и маски (*.yaml, k8s/**/*.yml) в список
+// файлов в лексическом порядке, без повторов. Каталог без --recursive —
+// только файлы верхнего уровня. Найденные так файлы проходят через filter;
+// явно указанные файлы проверяются всегда.
+func expandInputs(args []string, recursive bool, filter pathFilter) ([]string, error) {
+	var out []string
+	seen := map[string]bool{}
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			out = append(out, p)
+		}
+	}
+	for _, arg := range args {
+		var found []string
+		var err error
+		if arg == stdinArg || isURL(arg) {
+			add(arg)
+			continue
+		}
+		if hasGlobMeta(arg) {
+			found, err = globFiles(arg)
+		} else if info, statErr := os.Stat(arg); statErr == nil && info.IsDir() {
+			found, err = walkDir(arg, recursive)
+		} else {
+			add(arg) // обычный файл; ошибку чтения сообщим при проверке
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, fmt.Errorf("%s: no YAML or Terraform files found", arg)
+		}
+		for _, p := range found {
+			if filter.allows(p) {
+				add(p)
+			}
+		}
+	}
+	return out, nil
+}
+
+// namedFiles — аргументы, которые называют файл прямо, а не каталог или
+// маску. Такой файл пользователь выбрал сам, и он проверяется целиком,
+// даже если не похож на манифест.
+func namedFiles(args []string) map[string]bool {
+	named := map[string]bool{}
+	for _, arg := range args {
+		if arg == stdinArg || isURL(arg) {
+			named[arg] = true
+			continue
+		}
+		if hasGlobMeta(arg) {
+			continue
+		}
+		if info, err := os.Stat(arg); err != nil || !info.IsDir() {
+			named[arg] = true
+		}
+	}
+	return named
+}
+
+// hasDirectory — есть ли среди аргументов каталог: тогда прогон идёт в
+// режиме каталога.
+func hasDirectory(args []string) bool {
+	for _, arg := range args {
+		if arg == stdinArg || isURL(arg) || hasGlobMeta(arg) {
+			continue
+		}
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// pathFilter — маски include и exclude из конфига. Маска без / сверяется
+// с именем файла (*.tf), со / — с путём целиком, ** — любое число
+// каталогов (**/charts/**).
+type pathFilter struct {
+	include, exclude [][]string
+}
+
+func newPathFilter(include, exclude []string) (pathFilter, error) {
+	var f pathFilter
+	for _, list := range []struct {
+		src []string
+		dst *[][]string
+	}{{include, &f.include}, {exclude, &f.exclude}} {
+		for _, p := range list.src {
+			segs := strings.Split(filepath.ToSlash(p), "/")
+			for _, seg := range segs {
+				if _, err := path.Match(seg, ""); err != nil {
+					return f, fmt.Errorf("%s: bad pattern", p)
+				}
+			}
+			*list.dst = append(*list.dst, segs)
+		}
+	}
+	return f, nil
+}
+
+func (f pathFilter) allows(p string) bool {
+	if len(f.include) > 0 && !matchAny(f.include, p) {
+		return false
+	}
+	return !matchAny(f.exclude, p)
+}
+
+func matchAny(patterns [][]string, p string) bool {
+	name := strings.Split(filepath.ToSlash(filepath.Clean(p)), "/")
+	for _, pat := range patterns {
+		if len(pat) == 1 {
+			if ok, _ := path.Match(pat[0], name[len(name)-1]); ok {
+				return true
+			}
+			continue
+		}
+		if globMatch(pat, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasGlobMeta(p string) bool { return strings.ContainsAny(p, "*?[") }
+
+// walkDir собирает входные файлы каталога, пропуская скрытые подкаталоги
+// вроде .git.
+func walkDir(dir string, recursive bool) ([]string, error) {
+	var out []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != dir && (!recursive || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isInputFile(p) {
+			out = append(out, p)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// globFiles — маска в стиле path.Match, где ** — любое число каталогов.
+// Обходим только неизменную часть пути до первого сегмента с маской.
+func globFiles(pattern string) ([]string, error) {
+	segs := strings.Split(filepath.ToSlash(pattern), "/")
+	base := 0
+	for base < len(segs)-1 && !hasGlobMeta(segs[base]) {
+		base++
+	}
+	root := strings.Join(segs[:base], "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+	for _, seg := range segs[base:] {
+		if _, err := path.Match(seg, ""); err != nil {
+			return nil, fmt.Errorf("%s: bad pattern", pattern)
+		}
+	}
+	var out []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(filepath.FromSlash(root), p)
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isInputFile(p) && globMatch(segs[base:], strings.Split(filepath.ToSlash(rel), "/")) {
+			out = append(out, p)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func globMatch(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if globMatch(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return globMatch(pattern[1:], name[1:])
+}
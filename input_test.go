@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// tree создаёт файлы files (пути через /) в новом временном каталоге.
+func tree(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestExpandInputs(t *testing.T) {
+	dir := tree(t, "b.yaml", "a.yml", "main.tf", "notes.txt", "apps/web.yaml", "apps/db/pvc.YAML", ".git/config.yaml")
+	rel := func(paths []string) []string {
+		var out []string
+		for _, p := range paths {
+			r, _ := filepath.Rel(dir, p)
+			out = append(out, filepath.ToSlash(r))
+		}
+		return out
+	}
+	tests := []struct {
+		name      string
+		args      []string
+		recursive bool
+		exclude   []string
+		want      []string
+	}{
+		{"directory", []string{dir}, false, nil, []string{"a.yml", "b.yaml", "main.tf"}},
+		{"recursive", []string{dir}, true, nil, []string{"a.yml", "apps/db/pvc.YAML", "apps/web.yaml", "b.yaml", "main.tf"}},
+		{"glob", []string{filepath.Join(dir, "*.yaml")}, false, nil, []string{"b.yaml"}},
+		{"glob any depth", []string{filepath.Join(dir, "apps", "**", "*.yaml")}, false, nil, []string{"apps/web.yaml"}},
+		// явный файл берётся при любом расширении, повторы отбрасываются
+		{"explicit", []string{filepath.Join(dir, "notes.txt"), dir, filepath.Join(dir, "b.yaml")}, false, nil, []string{"notes.txt", "a.yml", "b.yaml", "main.tf"}},
+		// exclude отсеивает найденные в каталоге, но не явно указанные
+		{"exclude", []string{dir, filepath.Join(dir, "main.tf")}, true, []string{"*.tf", "**/db/**"}, []string{"a.yml", "apps/web.yaml", "b.yaml", "main.tf"}},
+	}
+	for _, tt := range tests {
+		filter, err := newPathFilter(nil, tt.exclude)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := expandInputs(tt.args, tt.recursive, filter)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(rel(got), tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, rel(got), tt.want)
+		}
+	}
+
+	for _, arg := range []string{filepath.Join(dir, "*.json"), filepath.Join(dir, "[a-.yaml")} {
+		if _, err := expandInputs([]string{arg}, false, pathFilter{}); err == nil {
+			t.Errorf("%s: no error", arg)
+		}
+	}
+}
+
+func TestPathFilter(t *testing.T) {
+	f, err := newPathFilter([]string{"k8s/**"}, []string{"*.tf", "**/charts/**"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p, want := range map[string]bool{
+		"k8s/app.yaml":            true,
+		"./k8s/apps/web.yaml":     true,
+		"k8s/main.tf":             false,
+		"k8s/charts/web/pod.yaml": false,
+		"docs/pod.yaml":           false,
+	} {
+		if got := f.allows(p); got != want {
+			t.Errorf("allows(%q) = %v, want %v", p, got, want)
+		}
+	}
+	if _, err := newPathFilter(nil, []string{"k8s/[a-"}); err == nil {
+		t.Error("bad pattern accepted")
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.yaml", "a.yaml", true},
+		{"*.yaml", "x/a.yaml", false},
+		{"**/*.yaml", "a.yaml", true},
+		{"**/*.yaml", "x/y/a.yaml", true},
+		{"k8s/**/base/*.yml", "k8s/prod/eu/base/app.yml", true},
+		{"k8s/**/base/*.yml", "k8s/prod/app.yml", false},
+	}
+	for _, tt := range tests {
+		if got := globMatch(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/")); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+// «-» — стандартный ввод: expandInputs его не раскрывает, readInput
+// читает os.Stdin.
+func TestStdinInput(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("kind: Pod\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Seek(0, 0)
+	saved := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = saved }()
+
+	paths, err := expandInputs([]string{stdinArg, stdinArg}, false, pathFilter{})
+	if err != nil || !reflect.DeepEqual(paths, []string{stdinArg}) {
+		t.Fatalf("expandInputs: %q, %v", paths, err)
+	}
+	data, err := readInput(stdinArg)
+	if err != nil || string(data) != "kind: Pod\n" {
+		t.Errorf("readInput: %q, %v", data, err)
+	}
+	// обычный файл, не терминал — тоже «пайп»
+	if !stdinIsPipe() {
+		t.Error("stdinIsPipe() = false for a regular file")
+	}
+}
+
+// Флаги можно писать после путей; после -- всё — пути.
+func TestParseArgs(t *testing.T) {
+	saved := flag.CommandLine
+	defer func() { flag.CommandLine = saved }()
+	flag.CommandLine = flag.NewFlagSet("yamlvalid", flag.ContinueOnError)
+	recursive := flag.Bool("recursive", false, "")
+
+	got := parseArgs([]string{"./k8s", "--recursive", "a.yaml", "--", "--b.yaml"})
+	if want := []string{"./k8s", "a.yaml", "--b.yaml"}; !reflect.DeepEqual(got, want) || !*recursive {
+		t.Errorf("paths %q, recursive %v; want %q and true", got, *recursive, want)
+	}
+}
+
+func TestNamedFiles(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	named := namedFiles([]string{dir, file, filepath.Join(dir, "*.yaml"), stdinArg, "missing.yaml"})
+	for arg, want := range map[string]bool{
+		dir:                          false,
+		file:                         true,
+		filepath.Join(dir, "*.yaml"): false,
+		stdinArg:                     true,
+		"missing.yaml":               true,
+	} {
+		if named[arg] != want {
+			t.Errorf("namedFiles[%s] = %v, want %v", arg, named[arg], want)
+		}
+	}
+}
+
+// Без --verbose заметки info не печатаются, как и до их появления.
+func TestDropInfo(t *testing.T) {
+	out := fileOutcome{res: &validator.Result{Issues: []validator.Issue{
+		{Code: "DOC004", Severity: validator.SeverityInfo},
+		{Code: "OBJ002", Severity: validator.SeverityError},
+		{Code: "DOC003", Severity: validator.SeverityInfo},
+	}}}
+	out.dropInfo()
+	if len(out.res.Issues) != 1 || out.res.Issues[0].Code != "OBJ002" {
+		t.Errorf("issues after dropInfo: %v", out.res.Issues)
+	}
+}
+
+func TestHasDirectory(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{file}, false},
+		{[]string{file, dir}, true},
+		{[]string{filepath.Join(dir, "*.yaml"), stdinArg}, false},
+	}
+	for _, tt := range tests {
+		if got := hasDirectory(tt.args); got != tt.want {
+			t.Errorf("hasDirectory(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// inventory.go
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Подкоманда inventory — список образов, на которые ссылаются манифесты,
+// в формате SBOM для конвейера учёта поставок. Образы берутся из того же
+// разбора, что и при проверке (Result.Facts), а не собственным парсером.
+//
+//	yamlvalid inventory k8s/ --format cyclonedx > images.cdx.json
+func runInventory(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("inventory", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	format := fs.String("format", "cyclonedx", "output `format`: cyclonedx")
+	recursive := fs.Bool("r", true, "descend into subdirectories of directory arguments")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid inventory [-format cyclonedx] [-r=false] <path-to-yaml | dir | glob | ->...")
+		fs.PrintDefaults()
+	}
+	// флаги допускаются и после путей: inventory k8s/ --format cyclonedx
+	var inputs []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		inputs = append(inputs, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(inputs) == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *format != "cyclonedx" {
+		fmt.Fprintf(stderr, "unknown inventory format '%s' (known: cyclonedx)\n", *format)
+		return 2
+	}
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	filter, err := newPathFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+	paths, err := expandInputs(inputs, *recursive, filter)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	exitCode := 0
+	// образ → файлы, где он встречается
+	images := map[string][]string{}
+	for _, p := range paths {
+		name := p
+		if p == stdinArg {
+			name = defaultStdinName
+		}
+		data, err := readInput(p)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		var res *validator.Result
+		if isTerraformFile(p) {
+			res, err = validateTerraform(v, name, data)
+		} else {
+			res, err = v.ValidateFile("", data)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot unmarshal file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		for _, f := range res.Facts {
+			for _, img := range f.Images {
+				if files := images[img]; len(files) == 0 || files[len(files)-1] != name {
+					images[img] = append(files, name)
+				}
+			}
+		}
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(cycloneDXBOM(images)); err != nil {
+		fmt.Fprintf(stderr, "cannot write inventory: %v\n", err)
+		return 2
+	}
+	return exitCode
+}
+
+// ---------- CycloneDX ----------
+
+// cdxBOM — документ CycloneDX 1.5 в JSON. serialNumber и timestamp не
+// пишутся: одинаковые манифесты дают одинаковый SBOM, и его можно
+// сравнивать diff'ом.
+type cdxBOM struct {
+	BOMFormat   string         `json:"bomFormat"`
+	SpecVersion string         `json:"specVersion"`
+	Version     int            `json:"version"`
+	Metadata    cdxMetadata    `json:"metadata"`
+	Components  []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Tools struct {
+		Components []cdxComponent `json:"components"`
+	} `json:"tools"`
+}
+
+type cdxComponent struct {
+	Type       string        `json:"type"`
+	BOMRef     string        `json:"bom-ref,omitempty"`
+	Name       string        `json:"name"`
+	Version    string        `json:"version,omitempty"`
+	Hashes     []cdxHash     `json:"hashes,omitempty"`
+	PURL       string        `json:"purl,omitempty"`
+	Properties []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cdxHashAlgs — алгоритмы дайджестов OCI в именах CycloneDX.
+var cdxHashAlgs = map[string]string{"sha256": "SHA-256", "sha384": "SHA-384", "sha512": "SHA-512"}
+
+func cycloneDXBOM(images map[string][]string) cdxBOM {
+	bom := cdxBOM{BOMFormat: "CycloneDX", SpecVersion: "1.5", Version: 1, Components: []cdxComponent{}}
+	bom.Metadata.Tools.Components = []cdxComponent{{Type: "application", Name: "yamlvalid", Version: version}}
+	refs := make([]string, 0, len(images))
+	for ref := range images {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	for _, ref := range refs {
+		name, tag, digest := splitImage(ref)
+		r := parseImageRef(name)
+		repo := r.host + "/" + r.repo
+		c := cdxComponent{Type: "container", BOMRef: ref, Name: repo, Version: tag}
+		// purl по спецификации pkg:oci: версия — дайджест, тег — квалификатор
+		q := url.Values{"repository_url": {repo}}
+		if tag != "" {
+			q.Set("tag", tag)
+		}
+		purl := "pkg:oci/" + path.Base(r.repo)
+		if digest != "" {
+			purl += "@" + strings.ReplaceAll(digest, ":", "%3A")
+			if c.Version == "" {
+				c.Version = digest
+			}
+			if alg, hex, ok := strings.Cut(digest, ":"); ok && cdxHashAlgs[alg] != "" {
+				c.Hashes = []cdxHash{{Alg: cdxHashAlgs[alg], Content: hex}}
+			}
+		}
+		c.PURL = purl + "?" + q.Encode()
+		for _, file := range images[ref] {
+			c.Properties = append(c.Properties, cdxProperty{Name: "yamlvalid:file", Value: file})
+		}
+		bom.Components = append(bom.Components, c)
+	}
+	return bom
+}
+
+// splitImage делит ссылку на имя, тег и дайджест; тег и дайджест могут
+// быть оба: nginx:1.25@sha256:...
+func splitImage(ref string) (name, tag, digest string) {
+	name = ref
+	if i := strings.IndexByte(name, '@'); i >= 0 {
+		name, digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndexByte(name, ':'); i > strings.LastIndexByte(name, '/') {
+		name, tag = name[:i], name[i+1:]
+	}
+	return name, tag, digest
+}
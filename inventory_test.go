@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct{ ref, name, tag, digest string }{
+		{"nginx", "nginx", "", ""},
+		{"nginx:1.25", "nginx", "1.25", ""},
+		{"registry:5000/web", "registry:5000/web", "", ""},
+		{"registry:5000/web:1.0@sha256:abc", "registry:5000/web", "1.0", "sha256:abc"},
+		{"web@sha256:abc", "web", "", "sha256:abc"},
+	}
+	for _, tt := range tests {
+		name, tag, digest := splitImage(tt.ref)
+		if name != tt.name || tag != tt.tag || digest != tt.digest {
+			t.Errorf("splitImage(%q) = %q %q %q", tt.ref, name, tag, digest)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	dir := t.TempDir()
+	pod := "apiVersion: v1\nkind: Pod\nmetadata: {name: %s}\nspec:\n  containers:\n    - {name: web, image: %s}\n"
+	files := map[string]string{
+		"a.yaml": fmt.Sprintf(pod, "a", "registry.bigbrother.io/team/web:1.0@sha256:0123") + "---\n" + fmt.Sprintf(pod, "b", "nginx:1.25"),
+		"b.yaml": fmt.Sprintf(pod, "c", "nginx:1.25"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runInventory([]string{dir, "--format", "cyclonedx"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d, stderr %q", code, stderr.String())
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(stdout.Bytes(), &bom); err != nil {
+		t.Fatal(err)
+	}
+	if bom.BOMFormat != "CycloneDX" || len(bom.Components) != 2 {
+		t.Fatalf("bom %+v", bom)
+	}
+	nginx, web := bom.Components[0], bom.Components[1]
+	if nginx.Name != "docker.io/library/nginx" || nginx.Version != "1.25" || len(nginx.Properties) != 2 ||
+		nginx.PURL != "pkg:oci/nginx?repository_url=docker.io%2Flibrary%2Fnginx&tag=1.25" {
+		t.Errorf("nginx %+v", nginx)
+	}
+	if web.PURL != "pkg:oci/web@sha256%3A0123?repository_url=registry.bigbrother.io%2Fteam%2Fweb&tag=1.0" ||
+		len(web.Hashes) != 1 || web.Hashes[0] != (cdxHash{Alg: "SHA-256", Content: "0123"}) {
+		t.Errorf("web %+v", web)
+	}
+
+	if code := runInventory([]string{dir, "-format", "spdx"}, &stdout, &stderr); code != 2 {
+		t.Errorf("unknown format: exit %d, want 2", code)
+	}
+}
@@ -0,0 +1,163 @@
+// kubectl.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// kubectlPlugin — бинарник установлен под именем kubectl-validate где-то
+// в PATH, и kubectl вызывает его на kubectl validate -f ...:
+//
+//	go build -o ~/bin/kubectl-validate .
+//	kubectl validate -f k8s/ -R
+func kubectlPlugin() bool {
+	name := strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe")
+	return name == "kubectl-validate"
+}
+
+// kubectlOptions — флаги kubectl, у которых нет своего аналога: они
+// выбирают контекст кластера для проверок с инвентарём и квотами.
+type kubectlOptions struct {
+	kubeconfig string
+	context    string
+}
+
+// kubectlArgs переводит аргументы в стиле kubectl в свои: -f/--filename
+// (файл, каталог, маска, URL или -) — позиционные пути, -R — --recursive,
+// -o — --output, -l — --selector, -n — --namespace. Остальные флаги
+// проходят как есть.
+func kubectlArgs(args []string) ([]string, kubectlOptions, error) {
+	var out []string
+	var opts kubectlOptions
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		name, value, hasValue := a, "", false
+		if eq := strings.IndexByte(a, '='); eq > 0 && strings.HasPrefix(a, "-") {
+			name, value, hasValue = a[:eq], a[eq+1:], true
+		}
+		if len(a) > 2 && a[0] == '-' && strings.ContainsAny(a[1:2], "foln") && flag.Lookup(strings.TrimPrefix(name, "-")) == nil {
+			// слитная короткая форма: -fdeploy.yaml, -f=deploy.yaml,
+			// -lapp=web; -fix и -output=json — свои флаги в стиле Go
+			name, value, hasValue = a[:2], strings.TrimPrefix(a[2:], "="), true
+		}
+		switch name {
+		case "-f", "--filename", "-o", "--output", "-l", "--selector", "-n", "--namespace", "--context", "--kubeconfig":
+		case "-R", "--recursive":
+			out = append(out, "--recursive")
+			continue
+		default:
+			out = append(out, a)
+			continue
+		}
+		if !hasValue {
+			if i+1 == len(args) {
+				return nil, opts, fmt.Errorf("flag needs an argument: %s", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "-f", "--filename":
+			out = append(out, value)
+		case "-o", "--output":
+			out = append(out, "--output="+value)
+		case "-l", "--selector":
+			out = append(out, "--selector="+value)
+		case "-n", "--namespace":
+			out = append(out, "--namespace="+value)
+		case "--context":
+			opts.context = value
+		case "--kubeconfig":
+			opts.kubeconfig = value
+		}
+	}
+	return out, opts, nil
+}
+
+// currentContext — контекст из --context или current-context первого
+// kubeconfig, где он задан: --kubeconfig, $KUBECONFIG (список через
+// разделитель PATH) или ~/.kube/config. Без kubeconfig — пусто.
+func (o kubectlOptions) currentContext() string {
+	if o.context != "" {
+		return o.context
+	}
+	var files []string
+	switch {
+	case o.kubeconfig != "":
+		files = []string{o.kubeconfig}
+	case os.Getenv("KUBECONFIG") != "":
+		files = filepath.SplitList(os.Getenv("KUBECONFIG"))
+	default:
+		if home, err := os.UserHomeDir(); err == nil {
+			files = []string{filepath.Join(home, ".kube", "config")}
+		}
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var kc struct {
+			CurrentContext string `yaml:"current-context"`
+		}
+		if yaml.Unmarshal(data, &kc) == nil && kc.CurrentContext != "" {
+			return kc.CurrentContext
+		}
+	}
+	return ""
+}
+
+// contextDir — файлы кластера по умолчанию для контекста kubeconfig:
+// .yamlvalid/<context>/cluster-inventory.yaml и namespace-budgets.yaml.
+// Явные --cluster-inventory и --namespace-budgets важнее.
+const contextDir = ".yamlvalid"
+
+// contextFile — путь к файлу контекста, если он есть.
+func contextFile(context, name string) string {
+	if context == "" || strings.ContainsAny(context, `/\`) {
+		return ""
+	}
+	p := filepath.Join(contextDir, context, name)
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+	return p
+}
+
+// isURL — вход по http(s), как у kubectl -f https://...
+func isURL(p string) bool {
+	return strings.HasPrefix(p, "http://") || strings.HasPrefix(p, "https://")
+}
+
+var fetchClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchURL скачивает манифест через общий лимитер и повторы (--net-*);
+// тело ограничено тем же --max-file-size.
+func fetchURL(url string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := netSend(fetchClient, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestKubectlArgs(t *testing.T) {
+	saved := flag.CommandLine
+	defer func() { flag.CommandLine = saved }()
+	flag.CommandLine = flag.NewFlagSet("yamlvalid", flag.ContinueOnError)
+	flag.Bool("fix", false, "")
+	flag.String("output", "", "")
+	flag.String("name", "", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+		opts kubectlOptions
+	}{
+		{"filename", []string{"-f", "a.yaml", "--filename", "k8s/", "--filename=b.yaml", "-fc.yaml", "-f=d.yaml"},
+			[]string{"a.yaml", "k8s/", "b.yaml", "c.yaml", "d.yaml"}, kubectlOptions{}},
+		{"recursive", []string{"-R", "-f", "k8s", "--recursive"}, []string{"--recursive", "k8s", "--recursive"}, kubectlOptions{}},
+		{"output", []string{"-o", "json", "--output=sarif", "-ojunit"},
+			[]string{"--output=json", "--output=sarif", "--output=junit"}, kubectlOptions{}},
+		{"selector", []string{"-l", "app=web", "--selector=tier in (db,cache)", "-lapp!=web"},
+			[]string{"--selector=app=web", "--selector=tier in (db,cache)", "--selector=app!=web"}, kubectlOptions{}},
+		{"namespace", []string{"-n", "prod", "--namespace=stage-*", "-nqa"},
+			[]string{"--namespace=prod", "--namespace=stage-*", "--namespace=qa"}, kubectlOptions{}},
+		// свои флаги в стиле Go не путаются со слитной короткой формой
+		{"own flags", []string{"-fix", "-output=json", "-name=web", "-f", "a.yaml"}, []string{"-fix", "-output=json", "-name=web", "a.yaml"}, kubectlOptions{}},
+		{"cluster", []string{"--context", "prod", "--kubeconfig=/tmp/kc", "-f", "-"},
+			[]string{"-"}, kubectlOptions{kubeconfig: "/tmp/kc", context: "prod"}},
+		// свои флаги и всё после -- проходят как есть
+		{"passthrough", []string{"--fail-on=warning", "-f", "a.yaml", "--", "-f"},
+			[]string{"--fail-on=warning", "a.yaml", "--", "-f"}, kubectlOptions{}},
+	}
+	for _, tt := range tests {
+		got, opts, err := kubectlArgs(tt.args)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) || opts != tt.opts {
+			t.Errorf("%s: %q %+v, want %q %+v", tt.name, got, opts, tt.want, tt.opts)
+		}
+	}
+	if _, _, err := kubectlArgs([]string{"-R", "-f"}); err == nil {
+		t.Error("-f without value accepted")
+	}
+}
+
+// Контекст: --context, затем current-context из --kubeconfig или
+// $KUBECONFIG; файлы кластера ищутся в .yamlvalid/<context>/.
+func TestKubectlContext(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfig, []byte("apiVersion: v1\ncurrent-context: staging\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", filepath.Join(dir, "missing")+string(filepath.ListSeparator)+kubeconfig)
+	tests := []struct {
+		opts kubectlOptions
+		want string
+	}{
+		{kubectlOptions{context: "prod", kubeconfig: kubeconfig}, "prod"},
+		{kubectlOptions{kubeconfig: kubeconfig}, "staging"},
+		{kubectlOptions{}, "staging"},
+		{kubectlOptions{kubeconfig: filepath.Join(dir, "missing")}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.currentContext(); got != tt.want {
+			t.Errorf("%+v: context %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+
+	wd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(wd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	inventory := filepath.Join(contextDir, "staging", "cluster-inventory.yaml")
+	if err := os.MkdirAll(filepath.Dir(inventory), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(inventory, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct{ context, name, want string }{
+		{"staging", "cluster-inventory.yaml", inventory},
+		{"staging", "namespace-budgets.yaml", ""},
+		{"prod", "cluster-inventory.yaml", ""},
+		{"../staging", "cluster-inventory.yaml", ""},
+		{"", "cluster-inventory.yaml", ""},
+	} {
+		if got := contextFile(tt.context, tt.name); got != tt.want {
+			t.Errorf("contextFile(%q, %q) = %q, want %q", tt.context, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchURL(t *testing.T) {
+	keepNetLimits(t)
+	netRetries, netLimiter = 2, newRateLimiter(0)
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pod.yaml":
+			io.WriteString(w, "kind: Pod\n")
+		case "/busy.yaml":
+			// первые два запроса — 429, третий проходит, как у реестров
+			if hits++; hits <= 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			io.WriteString(w, "kind: Service\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for path, want := range map[string]string{"/pod.yaml": "kind: Pod\n", "/busy.yaml": "kind: Service\n"} {
+		body, err := fetchURL(srv.URL + path)
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		data, _ := io.ReadAll(body)
+		body.Close()
+		if string(data) != want {
+			t.Errorf("%s: body %q, want %q", path, data, want)
+		}
+	}
+	if hits != 3 {
+		t.Errorf("busy.yaml fetched in %d requests, want 3", hits)
+	}
+	if _, err := fetchURL(srv.URL + "/missing.yaml"); err == nil {
+		t.Error("404 accepted")
+	}
+}
@@ -0,0 +1,85 @@
+// lockfile.go
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// defaultLockfile — файл дайджестов по умолчанию для --frozen.
+const defaultLockfile = ".yamlvalid.lock"
+
+type lockFile struct {
+	Version int               `json:"version"`
+	Files   map[string]string `json:"files"`
+}
+
+// lockfile — дайджесты сгенерированных манифестов. Генератор записывает
+// их с --write-lockfile, а CI с --frozen падает на файлах, которые с тех
+// пор поправили руками или добавили в обход генератора.
+type lockfile struct {
+	path    string
+	record  bool
+	digests map[string]string
+}
+
+func loadLockfile(path string, record bool) (*lockfile, error) {
+	l := &lockfile{path: path, record: record, digests: map[string]string{}}
+	if record {
+		return l, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f lockFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	if f.Version != 1 {
+		return nil, errors.New("unsupported lockfile version")
+	}
+	for file, digest := range f.Files {
+		l.digests[file] = digest
+	}
+	return l, nil
+}
+
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// check записывает дайджест файла или сверяет его с записанным; stdin
+// и нечитаемые файлы не учитываются.
+func (l *lockfile) check(out *fileOutcome) {
+	if l == nil || out.path == stdinArg || out.data == nil {
+		return
+	}
+	file, sum := issueFile(out), digest(out.data)
+	switch got, ok := l.digests[file]; {
+	case l.record:
+		l.digests[file] = sum
+	case out.failed:
+	case !ok:
+		addIssue(out.res, validator.SeverityError, 0, validator.CodeNotLocked)
+	case got != sum:
+		addIssue(out.res, validator.SeverityError, 0, validator.CodeDigestMismatch, sum[:len("sha256:")+12])
+	}
+}
+
+func (l *lockfile) save() error {
+	if l == nil || !l.record {
+		return nil
+	}
+	data, err := json.MarshalIndent(lockFile{Version: 1, Files: l.digests}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(l.path, append(data, '\n'), 0o644)
+}
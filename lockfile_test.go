@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// --write-lockfile записывает дайджесты, --frozen сверяет с ними.
+func TestLockfileRoundTrip(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), defaultLockfile)
+	outcome := func(path, data string) *fileOutcome {
+		return &fileOutcome{name: path, path: path, data: []byte(data), res: &validator.Result{}}
+	}
+	rec, err := loadLockfile(lock, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.check(outcome("k8s/app.yaml", "kind: ConfigMap\n"))
+	if err := rec.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	frozen, err := loadLockfile(lock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, path, data, want string
+	}{
+		{"unchanged", "k8s/app.yaml", "kind: ConfigMap\n", ""},
+		{"cleaned path", "k8s/../k8s/app.yaml", "kind: ConfigMap\n", ""},
+		{"edited", "k8s/app.yaml", "kind: Secret\n", validator.CodeDigestMismatch},
+		{"not locked", "k8s/new.yaml", "kind: ConfigMap\n", validator.CodeNotLocked},
+	}
+	for _, tt := range tests {
+		out := outcome(tt.path, tt.data)
+		frozen.check(out)
+		got := ""
+		for _, is := range out.res.Issues {
+			got += is.Code
+		}
+		if got != tt.want {
+			t.Errorf("%s: codes %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLockfileVersion(t *testing.T) {
+	lock := filepath.Join(t.TempDir(), defaultLockfile)
+	if err := os.WriteFile(lock, []byte(`{"version": 2, "files": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadLockfile(lock, false); err == nil {
+		t.Error("lockfile version 2 accepted")
+	}
+}
+
+// --preset minimal не должен выключать --frozen: правка руками
+// по-прежнему валит прогон.
+func TestFrozenWithMinimalPreset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.ToSlash(filepath.Join(dir, "app.yaml"))
+	lock := filepath.Join(dir, defaultLockfile)
+	if err := os.WriteFile(lock, []byte(`{"version": 1, "files": {"`+path+`": "sha256:0000"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	locked, err := loadLockfile(lock, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := validator.New(validator.Config{Preset: "minimal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n")
+	res, err := v.ValidateFile(path, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := fileOutcome{name: path, path: path, data: data, res: res}
+	locked.check(&out)
+	v.Adjust(out.res)
+	if !out.res.FailedAt(validator.SeverityError) {
+		t.Fatalf("drifted file passed with --preset minimal: %v", out.res.Issues)
+	}
+	if got := out.res.Issues[len(out.res.Issues)-1].Code; got != validator.CodeDigestMismatch {
+		t.Errorf("last finding %s, want %s", got, validator.CodeDigestMismatch)
+	}
+}
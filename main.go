@@ -3,453 +3,548 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
+	"time"
 
-	yaml "gopkg.in/yaml.v3"
+	"github.com/forceofprophet/yandexgolang2/report"
+	"github.com/forceofprophet/yandexgolang2/validator"
 )
 
-type vError struct {
-	line int
-	msg  string
+// addIssue — находки онлайн-проверок, которых нет в ядре.
+func addIssue(res *validator.Result, sev validator.Severity, line int, code string, args ...any) {
+	res.Issues = append(res.Issues, validator.Issue{Line: line, Code: code, Severity: sev, Args: args})
 }
 
-type errBag struct {
-	file string
-	list []vError
-}
-
-func (e *errBag) add(line int, msg string) { e.list = append(e.list, vError{line: line, msg: msg}) }
-
-func (e *errBag) printAndExit() {
-	if len(e.list) == 0 {
-		return
-	}
-	// печатаем в STDOUT — так ожидают автотесты
-	for _, er := range e.list {
-		if er.line > 0 {
-			fmt.Fprintf(os.Stdout, "%s:%d %s\n", e.file, er.line, er.msg)
-		} else {
-			fmt.Fprintf(os.Stdout, "%s: %s\n", e.file, er.msg)
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "gitops-plugin":
+			os.Exit(runGitopsPlugin(os.Stdin, os.Stdout, os.Stderr))
+		case "clean":
+			os.Exit(runClean(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+		case "inventory":
+			os.Exit(runInventory(os.Args[2:], os.Stdout, os.Stderr))
+		case "pin-images":
+			os.Exit(runPinImages(os.Args[2:], os.Stdout, os.Stderr))
+		case "render-pod":
+			os.Exit(runRenderPod(os.Args[2:], os.Stdout, os.Stderr))
+		case "engines":
+			os.Exit(runEngines(os.Args[2:], os.Stdout, os.Stderr))
+		case "conformance":
+			os.Exit(runConformance(os.Args[2:], os.Stdout, os.Stderr))
+		case "suppress":
+			os.Exit(runSuppress(os.Args[2:], os.Stdout, os.Stderr))
 		}
 	}
-	os.Exit(1)
-}
 
-func main() {
-	if len(os.Args) != 2 {
-		fmt.Fprintln(os.Stderr, "usage: yamlvalid <path-to-yaml>")
+	configPath := flag.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	gateFlags := gateFlag{}
+	flag.Var(gateFlags, "feature-gate", "enable optional cluster feature checks: name[=true|false] (known: sctp)")
+	var packFlags packFlag
+	flag.Var(&packFlags, "rule-pack", "enable an opt-in rule pack (known: recommended-labels, podman, best-practices)")
+	var preset presetFlag
+	flag.Var(&preset, "preset", "enable rule groups by `name`: "+strings.Join(validator.Presets(), ", ")+" (default all checks; rules in the config override it)")
+	var apiVersions apiVersionFlag
+	flag.Var(&apiVersions, "api-version", "accept another apiVersion, e.g. apps/v1 (repeatable, comma-separated; adds to enums.apiVersion)")
+	inventoryPath := flag.String("cluster-inventory", "", "YAML `file` with cluster node pools, labels and taints for toleration checks and storage classes for PVC checks")
+	budgetsPath := flag.String("namespace-budgets", "", "YAML `file` with per-namespace request quotas (requests.cpu, requests.memory)")
+	var filters filterFlags
+	flag.Var(&filters.selector, "selector", "validate only documents whose labels match a kubectl-style `selector`, e.g. app=web,tier!=cache")
+	flag.Var(&filters.selector, "l", "shorthand for --selector")
+	flag.Var(&filters.kinds, "kind", "validate only documents of these kinds, e.g. Pod,Deployment (masks allowed, repeatable)")
+	flag.Var(&filters.namespaces, "namespace", "validate only documents in namespaces matching these masks, e.g. prod-* (unset namespace is default)")
+	flag.Var(&filters.names, "name", "validate only documents whose metadata.name matches these masks, e.g. web-*")
+	skipUnknown := flag.Bool("skip-unknown-kinds", false, "skip documents of kinds without a validator with a warning instead of failing (default when a directory is given; =false turns it off)")
+	engine := flag.String("yaml-engine", "", "YAML `parser`: "+strings.Join(validator.Engines(), ", ")+" (default "+validator.DefaultEngine()+")")
+	attestPath := flag.String("attest", "", "write an in-toto attestation of the validation result to `file`")
+	checkImages := flag.Bool("check-image-exists", false, "query registries (online) to verify every referenced image exists")
+	var scanReports stringList
+	flag.Var(&scanReports, "image-scan-results", "JSON `file` with scanner results for one image; fail on critical CVEs (repeatable)")
+	netRPS := flag.Float64("net-rps", 10, "max requests per second for online checks (0 = unlimited)")
+	flag.IntVar(&netConcurrency, "net-concurrency", netConcurrency, "max concurrent requests for online checks")
+	flag.IntVar(&netRetries, "net-retries", netRetries, "retries with exponential backoff for failed online requests")
+	recursive := flag.Bool("recursive", false, "descend into subdirectories of directory arguments")
+	output := flag.String("output", "", "output `format`: "+strings.Join(report.Formats(), ", ")+", audit (suppressed findings and their exceptions) (default pretty on a terminal, text otherwise)")
+	noColor := flag.Bool("no-color", false, "disable colors in pretty output")
+	showSource := flag.Bool("show-source", false, "print the offending source line with a caret under each finding (text and pretty output)")
+	fileTimeout := flag.Duration("file-timeout", 30*time.Second, "give up on a file whose validation takes longer (0 = no limit); the rest of the batch continues")
+	flag.Int64Var(&maxFileSize, "max-file-size", maxFileSize, "reject input files larger than this many `bytes` (0 = no limit)")
+	failOn := flag.String("fail-on", "error", "lowest `severity` that fails the run: error, warning or info")
+	maxWarnings := flag.Int("max-warnings", -1, "fail the run if there are more than `N` warnings in total (-1 = no limit); per-rule limits are set by thresholds in the config")
+	baselinePath := flag.String("baseline", "", "JSON `file` with known findings to suppress; only new findings are reported")
+	writeBaseline := flag.Bool("write-baseline", false, "record all current findings into the --baseline file instead of reporting them")
+	frozen := flag.Bool("frozen", false, "fail on files whose content differs from the digests in --lockfile (generated manifests edited by hand)")
+	lockfilePath := flag.String("lockfile", defaultLockfile, "JSON `file` with digests of generated manifests for --frozen")
+	writeLockfile := flag.Bool("write-lockfile", false, "record digests of all validated files into --lockfile")
+	feedbackPath := flag.String("feedback-file", "", "JSON `file` of false positives marked with yamlvalid suppress: they are not reported, other findings show their fingerprint")
+	emitNormalized := flag.String("emit-normalized", "", "write each validated document as canonical JSON (sorted keys, resolved anchors, fixed enum casing) into `dir`")
+	emitFacts := flag.String("emit-facts", "", "write images, ports, volumes, labels and resource totals of every validated object as JSON to `file`")
+	showDefaultsMode := flag.Bool("show-defaults", false, "print the documents with the defaults the API server would fill in (protocol, imagePullPolicy, restartPolicy, probe timings, ...) instead of validating them")
+	fix := flag.Bool("fix", false, "apply automatic fixes in place (written atomically, permissions kept)")
+	backup := flag.Bool("backup", false, "with --fix, keep the original of each fixed file as file.bak")
+	stream := flag.Bool("stream", false, "report each file's findings as soon as it is validated (set-wide checks follow at the end)")
+	noRedact := flag.Bool("no-redact", false, "print Secret data and secret-like env values in messages and source lines as is instead of ***")
+	verbose := flag.Bool("verbose", false, "also report info-level findings: skipped binary and non-manifest files, empty files and documents")
+	stdinName := flag.String("stdin-filename", defaultStdinName, "file `name` for input read from stdin (-), used in messages and file name rules")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: yamlvalid [flags] <path-to-yaml | dir | glob | ->...")
+		fmt.Fprintln(os.Stderr, "       yamlvalid gitops-plugin   (manifests on stdin, see YAMLVALID_* env)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid clean [-w] [file | -]...   (strip server-populated fields)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid inventory [-format cyclonedx] <path>...   (SBOM of referenced images)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid pin-images [-check] [file | -]...   (pin image tags to registry digests)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid render-pod [file | -]...   (print the defaulted pods of workloads)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid engines [-r] <path>...   (compare YAML parsers of this build)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid conformance [-context name] <path>...   (compare decisions with a test API server)")
+		fmt.Fprintln(os.Stderr, "       yamlvalid suppress -reason text [-expires date] <fingerprint> [path...]   (mark a false positive in the feedback file)")
+		fmt.Fprintln(os.Stderr, "       kubectl validate -f <file | dir | url | ->... [-R] [-l selector] [--context name]   (installed as kubectl-validate)")
+		flag.PrintDefaults()
+	}
+	argv := os.Args[1:]
+	var kubectl kubectlOptions
+	if kubectlPlugin() {
+		var err error
+		if argv, kubectl, err = kubectlArgs(argv); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	args := parseArgs(argv)
+	if len(args) == 0 && stdinIsPipe() {
+		args = []string{stdinArg}
+	}
+	if len(args) == 0 {
+		flag.Usage()
 		os.Exit(2)
 	}
-	path := os.Args[1]
-	data, err := os.ReadFile(path)
+
+	failSeverity, err := validator.ParseSeverity(*failOn)
 	if err != nil {
-		fmt.Fprintf(os.Stdout, "%s: cannot read file content: %v\n", filepath.Base(path), err)
+		fmt.Fprintf(os.Stderr, "--fail-on: %v\n", err)
 		os.Exit(2)
 	}
 
-	var root yaml.Node
-	if err := yaml.Unmarshal(data, &root); err != nil {
-		fmt.Fprintf(os.Stdout, "%s: cannot unmarshal file content: %v\n", filepath.Base(path), err)
-		os.Exit(2)
+	if *engine != "" {
+		if err := validator.CheckEngine(*engine); err != nil {
+			fmt.Fprintf(os.Stderr, "--yaml-engine: %v\n", err)
+			os.Exit(2)
+		}
 	}
 
-	bag := &errBag{file: filepath.Base(path)}
-	for _, doc := range root.Content {
-		validateTopLevel(doc, bag)
+	if *output == "" {
+		*output = defaultOutput()
+	}
+	// цвета выключают --no-color и переменная NO_COLOR (no-color.org)
+	color := !*noColor && os.Getenv("NO_COLOR") == ""
+	// содержимое файлов для --show-source; заполняется по мере чтения
+	contents := map[string][]byte{}
+	opts := report.Options{Color: color, Tool: report.Tool{Name: "yamlvalid", Version: version, RuleSet: validator.RuleSetVersion()}}
+	if *showSource {
+		opts.Source = func(file string) []byte { return contents[file] }
+	}
+	var rep report.Reporter
+	var audit *auditReport
+	if *output == "audit" {
+		audit = &auditReport{w: os.Stdout}
+		rep = audit
+	} else if rep, err = report.New(*output, os.Stdout, opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
 	}
-	bag.printAndExit()
-}
 
-// ---------- helpers over yaml.Node ----------
+	// в режиме плагина файлы кластера берутся по контексту kubeconfig
+	if kubectlPlugin() {
+		context := kubectl.currentContext()
+		if *inventoryPath == "" {
+			*inventoryPath = contextFile(context, "cluster-inventory.yaml")
+		}
+		if *budgetsPath == "" {
+			*budgetsPath = contextFile(context, "namespace-budgets.yaml")
+		}
+	}
 
-func getMap(doc *yaml.Node) (map[string]*yaml.Node, *yaml.Node) {
-	if doc.Kind != yaml.MappingNode {
-		return nil, doc
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		// в каталоге смешанного репозитория неизвестные kind'ы по
+		// умолчанию пропускаются; явный --skip-unknown-kinds важнее
+		if hasDirectory(args) {
+			cfg.SkipUnknownKinds = true
+		}
+		var skipUnknownSet *bool
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "skip-unknown-kinds" {
+				skipUnknownSet = skipUnknown
+			}
+		})
+		applyFlags(cfg, gateFlags, packFlags, apiVersions, filters, skipUnknownSet)
+		if *engine != "" {
+			cfg.Engine = *engine
+		}
+		if preset != "" {
+			cfg.Preset = string(preset)
+		}
+		if *inventoryPath != "" {
+			if cfg.Cluster, err = loadInventory(*inventoryPath); err != nil {
+				fmt.Fprintf(os.Stdout, "%s: cannot load cluster inventory: %v\n", filepath.Base(*inventoryPath), err)
+				os.Exit(2)
+			}
+		}
+		v, err = validator.New(*cfg)
 	}
-	m := make(map[string]*yaml.Node)
-	for i := 0; i < len(doc.Content); i += 2 {
-		k := doc.Content[i]
-		v := doc.Content[i+1]
-		m[k.Value] = v
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "%s: cannot load config: %v\n", configName(*configPath), err)
+		os.Exit(2)
 	}
-	return m, doc
-}
 
-func child(doc *yaml.Node, key string) (*yaml.Node, bool) {
-	if doc.Kind != yaml.MappingNode {
-		return nil, false
+	netLimiter = newRateLimiter(*netRPS)
+
+	vulns, err := loadScanReports(scanReports)
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "cannot load image scan results: %v\n", err)
+		os.Exit(2)
 	}
-	for i := 0; i < len(doc.Content); i += 2 {
-		if doc.Content[i].Value == key {
-			return doc.Content[i+1], true
+
+	var budgets []validator.Quota
+	if *budgetsPath != "" {
+		if budgets, err = loadBudgets(*budgetsPath); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot load namespace budgets: %v\n", filepath.Base(*budgetsPath), err)
+			os.Exit(2)
 		}
 	}
-	return nil, false
-}
-
-func isScalarString(n *yaml.Node) bool { return n.Kind == yaml.ScalarNode && (n.Tag == "!!str" || n.Tag == "") }
-func isScalarInt(n *yaml.Node) bool    { return n.Kind == yaml.ScalarNode && n.Tag == "!!int" }
 
-// ---------- validators ----------
+	var known *baseline
+	switch {
+	case *baselinePath != "":
+		if known, err = loadBaseline(*baselinePath, *writeBaseline); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot load baseline: %v\n", filepath.Base(*baselinePath), err)
+			os.Exit(2)
+		}
+	case *writeBaseline:
+		fmt.Fprintln(os.Stderr, "--write-baseline requires --baseline file")
+		os.Exit(2)
+	}
 
-func validateTopLevel(doc *yaml.Node, bag *errBag) {
-	m, node := getMap(doc)
-	if m == nil {
-		bag.add(node.Line, "root must be object")
-		return
+	var locked *lockfile
+	switch {
+	case *frozen && *writeLockfile:
+		fmt.Fprintln(os.Stderr, "--frozen and --write-lockfile cannot be used together")
+		os.Exit(2)
+	case *frozen, *writeLockfile:
+		if locked, err = loadLockfile(*lockfilePath, *writeLockfile); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot load lockfile: %v\n", filepath.Base(*lockfilePath), err)
+			os.Exit(2)
+		}
 	}
 
-	// apiVersion
-	api, ok := m["apiVersion"]
-	if !ok {
-		bag.add(0, "apiVersion is required")
-	} else {
-		if !isScalarString(api) {
-			bag.add(api.Line, "apiVersion must be string")
-		} else if api.Value != "v1" {
-			bag.add(api.Line, fmt.Sprintf("apiVersion has unsupported value '%s'", api.Value))
+	var normalized *normalizedWriter
+	if *emitNormalized != "" {
+		if normalized, err = newNormalizedWriter(*emitNormalized); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot create output directory: %v\n", *emitNormalized, err)
+			os.Exit(2)
 		}
 	}
 
-	// kind
-	kind, ok := m["kind"]
-	if !ok {
-		bag.add(0, "kind is required")
-	} else {
-		if !isScalarString(kind) {
-			bag.add(kind.Line, "kind must be string")
-		} else if kind.Value != "Pod" {
-			bag.add(kind.Line, fmt.Sprintf("kind has unsupported value '%s'", kind.Value))
+	// отчёт audit без --feedback-file читает файл отзывов по умолчанию,
+	// иначе отметки yamlvalid suppress, в том числе просроченные, в него
+	// не попадут
+	if *feedbackPath == "" && audit != nil {
+		*feedbackPath = defaultFeedbackFile
+	}
+	var marked *feedback
+	if *feedbackPath != "" {
+		if marked, err = loadFeedback(*feedbackPath); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot load feedback file: %v\n", filepath.Base(*feedbackPath), err)
+			os.Exit(2)
 		}
 	}
+	if audit != nil {
+		audit.feedback, audit.baseline = marked, known
+	}
 
-	// metadata
-	meta, ok := m["metadata"]
-	if !ok {
-		bag.add(0, "metadata is required")
-	} else {
-		validateObjectMeta(meta, bag)
+	filter, err := newPathFilter(cfg.Include, cfg.Exclude)
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "%s: cannot load config: %v\n", configName(*configPath), err)
+		os.Exit(2)
 	}
+	paths, err := expandInputs(args, *recursive, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stdout, "%v\n", err)
+		os.Exit(2)
+	}
+	named := namedFiles(args)
+	// базовое имя — только для одного явно указанного файла в текстовом
+	// выводе, как ждут автотесты; иначе путь целиком, чтобы различать
+	// одноимённые файлы, а CI-форматы могли привязать находку к файлу
+	single := *output == "text" && len(args) == 1 && len(paths) == 1 && paths[0] == args[0]
 
-	// spec
-	spec, ok := m["spec"]
-	if !ok {
-		bag.add(0, "spec is required")
-	} else {
-		validatePodSpec(spec, bag)
+	if *showDefaultsMode {
+		os.Exit(showDefaults(v, paths, *stdinName, os.Stdout, os.Stderr))
 	}
-}
 
-func validateObjectMeta(n *yaml.Node, bag *errBag) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "metadata must be object")
-		return
-	}
-
-	// name (required, non-empty)
-	name, ok := m["name"]
-	if !ok {
-		bag.add(0, "name is required")
-	} else if !isScalarString(name) {
-		bag.add(name.Line, "name must be string")
-	} else if strings.TrimSpace(name.Value) == "" {
-		// пустая строка — считаем как отсутствие обязательного поля
-		bag.add(name.Line, "name is required")
-	}
-
-	// namespace (optional)
-	if ns, ok := m["namespace"]; ok {
-		if !isScalarString(ns) {
-			bag.add(ns.Line, "namespace must be string")
-		}
-	}
-
-	// labels (optional)
-	if labels, ok := m["labels"]; ok {
-		if labels.Kind != yaml.MappingNode {
-			bag.add(labels.Line, "labels must be object")
-		} else {
-			for i := 0; i < len(labels.Content); i += 2 {
-				k := labels.Content[i]
-				v := labels.Content[i+1]
-				if !isScalarString(k) || !isScalarString(v) {
-					bag.add(v.Line, "labels must be object")
-					break
-				}
+	if err := rep.Start(); err != nil {
+		outputFailed(err)
+	}
+	// по умолчанию сначала проверяем все файлы, потом печатаем, чтобы
+	// находки по набору (квоты, ссылки) стояли рядом с остальными; с
+	// --stream находки файла уходят в вывод сразу, а по набору — в конце
+	outcomes := make([]fileOutcome, 0, len(paths))
+	done := func(out fileOutcome) {
+		if !out.failed {
+			v.Adjust(out.res)
+		}
+		if err := normalized.write(v, &out); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: cannot write normalized documents: %v\n", out.name, err)
+		}
+		if *fix && !out.failed && out.path != stdinArg {
+			n, err := fixFile(out.path, out.data, out.res, *backup)
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "%s: cannot apply fixes: %v\n", out.name, err)
+			case n > 0:
+				fmt.Fprintf(os.Stderr, "%s: fixes applied: %d\n", out.name, n)
 			}
 		}
-	}
-}
-
-func validatePodSpec(n *yaml.Node, bag *errBag) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "spec must be object")
-		return
-	}
-
-	// os (optional)
-	if osn, ok := m["os"]; ok {
-		validatePodOS(osn, bag)
-	}
-
-	// containers (required)
-	cont, ok := m["containers"]
-	if !ok {
-		bag.add(0, "containers is required")
-	} else {
-		if cont.Kind != yaml.SequenceNode {
-			bag.add(cont.Line, "containers must be array")
-		} else if len(cont.Content) == 0 {
-			bag.add(cont.Line, "containers must be non-empty array")
-		} else {
-			seen := map[string]struct{}{}
-			for _, c := range cont.Content {
-				name := validateContainer(c, bag)
-				if name != "" {
-					if _, dup := seen[name]; dup {
-						bag.add(c.Line, fmt.Sprintf("name has invalid format '%s'", name))
-					}
-					seen[name] = struct{}{}
-				}
+		// секреты маскируются после --fix: правка пишет настоящие значения
+		if !*noRedact && !out.failed {
+			out.res.Redact()
+			contents[out.name] = out.res.RedactSource(out.data)
+		}
+		locked.check(&out)
+		if *stream {
+			if !*verbose {
+				out.dropInfo()
 			}
+			marked.filter(&out)
+			known.filter(&out)
+			out.flush(rep)
 		}
-	}
-}
+		outcomes = append(outcomes, out)
+	}
+	for _, path := range paths {
+		// rulePath — путь для правил fileName и layout; у stdin он есть,
+		// только если его задали через --stdin-filename
+		name, rulePath := path, path
+		switch {
+		case path == stdinArg:
+			name, rulePath = *stdinName, ""
+			if *stdinName != defaultStdinName {
+				rulePath = *stdinName
+			}
+		case isURL(path):
+			rulePath = ""
+		case single:
+			name = filepath.Base(path)
+		}
+		out := fileOutcome{name: name, path: path}
+		data, err := readInput(path)
+		if err != nil {
+			out.fail(validator.CodeReadFailed, err.Error())
+			done(out)
+			continue
+		}
+		out.data = data
+		contents[name] = data
 
-// Поддерживаем:
-// 1) os: "linux"|"windows"
-// 2) os: { name: "linux"|"windows" }
-func validatePodOS(n *yaml.Node, bag *errBag) {
-	switch n.Kind {
-	case yaml.ScalarNode:
-		if !isScalarString(n) {
-			bag.add(n.Line, "os must be string")
-			return
-		}
-		val := strings.ToLower(n.Value)
-		if val != "linux" && val != "windows" {
-			bag.add(n.Line, fmt.Sprintf("os has unsupported value '%s'", n.Value))
-		}
-	case yaml.MappingNode:
-		osName, ok := child(n, "name")
-		if !ok {
-			bag.add(0, "os.name is required")
-			return
-		}
-		if !isScalarString(osName) {
-			bag.add(osName.Line, "name must be string")
-			return
-		}
-		val := strings.ToLower(osName.Value)
-		if val != "linux" && val != "windows" {
-			bag.add(osName.Line, fmt.Sprintf("os has unsupported value '%s'", osName.Value))
-		}
-	default:
-		bag.add(n.Line, "os must be string")
-	}
-}
+		// правилам раскладки нужен полный путь: шаблоны сверяются с его концом
+		if rulePath != "" {
+			if abs, err := filepath.Abs(rulePath); err == nil {
+				rulePath = abs
+			}
+		}
+		out.res, err = guard(name, *fileTimeout, func() (*validator.Result, error) {
+			if isTerraformFile(path) {
+				return validateTerraform(v, name, data)
+			}
+			if named[path] {
+				return v.ValidateFile(rulePath, data)
+			}
+			return v.ValidateScanned(rulePath, data)
+		})
+		var pe panicError
+		switch {
+		case errors.As(err, &pe):
+			out.fail(validator.CodeValidationPanic, fmt.Sprint(pe.value))
+		case errors.Is(err, errTimeout):
+			out.fail(validator.CodeValidationTimeout, fileTimeout.String())
+		case err != nil:
+			out.fail(validator.CodeParseFailed, err.Error())
+		}
+		if out.failed {
+			done(out)
+			continue
+		}
 
-var reSnake = regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`)
-var reImage = regexp.MustCompile(`^registry\.bigbrother\.io\/[^:]+:[A-Za-z0-9._-]+$`)
-
-func validateContainer(n *yaml.Node, bag *errBag) (nameOut string) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "container must be object")
-		return ""
-	}
-
-	// name
-	name, ok := m["name"]
-	if !ok {
-		bag.add(0, "name is required")
-	} else {
-		if !isScalarString(name) {
-			bag.add(name.Line, "name must be string")
-		} else if strings.TrimSpace(name.Value) == "" {
-			// пустое имя — трактуем как отсутствие обязательного поля (ожидание автотеста)
-			bag.add(name.Line, "name is required")
-		} else if !reSnake.MatchString(name.Value) {
-			bag.add(name.Line, fmt.Sprintf("name has invalid format '%s'", name.Value))
-		}
-		nameOut = name.Value
-	}
-
-	// image
-	img, ok := m["image"]
-	if !ok {
-		bag.add(0, "image is required")
-	} else if !isScalarString(img) {
-		bag.add(img.Line, "image must be string")
-	} else if !reImage.MatchString(img.Value) {
-		bag.add(img.Line, fmt.Sprintf("image has invalid format '%s'", img.Value))
-	}
-
-	// ports
-	if ports, ok := m["ports"]; ok {
-		if ports.Kind != yaml.SequenceNode {
-			bag.add(ports.Line, "ports must be array")
-		} else {
-			for _, p := range ports.Content {
-				validateContainerPort(p, bag)
+		if *checkImages {
+			checkImagesExist(out.res)
+		}
+		checkImageVulns(out.res, vulns)
+		done(out)
+	}
+	checkQuotas(outcomes, budgets)
+	checkReferences(outcomes)
+
+	// код выхода — худший по всем файлам: 2 (не прочитан или не разобран)
+	// важнее 1 (есть ошибки валидации)
+	exitCode := 0
+	var attested []attestedFile
+	var summary report.Summary
+	var results []*validator.Result
+	for i := range outcomes {
+		out := &outcomes[i]
+		if !out.failed {
+			v.Adjust(out.res)
+			// находки по набору могли процитировать значения из файла
+			if !*noRedact {
+				out.res.Redact()
 			}
 		}
+		if !*verbose {
+			out.dropInfo()
+		}
+		marked.filter(out)
+		known.filter(out)
+		out.flush(rep)
+		summary.Add(out.name, out.res)
+		if out.failed {
+			exitCode = 2
+			continue
+		}
+		results = append(results, out.res)
+		attested = append(attested, attestedFile{path: out.name, data: out.data, res: out.res})
 	}
-
-	// probes
-	if rp, ok := m["readinessProbe"]; ok {
-		validateProbe(rp, bag, "readinessProbe")
+	failed, breaches := runFailed(v, results, failSeverity, *maxWarnings)
+	for _, b := range breaches {
+		fmt.Fprintln(os.Stderr, b)
 	}
-	if lp, ok := m["livenessProbe"]; ok {
-		validateProbe(lp, bag, "livenessProbe")
+	if failed && exitCode == 0 {
+		exitCode = 1
 	}
 
-	// resources
-	res, ok := m["resources"]
-	if !ok {
-		bag.add(0, "resources is required")
-	} else {
-		validateResourceRequirements(res, bag)
+	if err := rep.Finish(summary); err != nil {
+		outputFailed(err)
 	}
-
-	return nameOut
-}
-
-func validateContainerPort(n *yaml.Node, bag *errBag) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "ports item must be object")
-		return
-	}
-
-	// containerPort
-	cp, ok := m["containerPort"]
-	if !ok {
-		bag.add(0, "containerPort is required")
-	} else {
-		if !isScalarInt(cp) {
-			bag.add(cp.Line, "containerPort must be int")
-		} else {
-			val, err := toInt(cp.Value)
-			if err != nil || val < 1 || val > 65535 {
-				bag.add(cp.Line, "containerPort value out of range")
-			}
+	if err := known.save(); err != nil {
+		fmt.Fprintf(os.Stdout, "%s: cannot write baseline: %v\n", filepath.Base(*baselinePath), err)
+		os.Exit(2)
+	}
+	if err := locked.save(); err != nil {
+		fmt.Fprintf(os.Stdout, "%s: cannot write lockfile: %v\n", filepath.Base(*lockfilePath), err)
+		os.Exit(2)
+	}
+	if *emitFacts != "" {
+		if err := writeFacts(*emitFacts, outcomes); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot write facts: %v\n", filepath.Base(*emitFacts), err)
+			os.Exit(2)
 		}
 	}
 
-	// protocol
-	if proto, ok := m["protocol"]; ok {
-		if !isScalarString(proto) {
-			bag.add(proto.Line, "protocol must be string")
-		} else if proto.Value != "TCP" && proto.Value != "UDP" {
-			bag.add(proto.Line, fmt.Sprintf("protocol has unsupported value '%s'", proto.Value))
+	// аттестуем только полный набор: непрочитанный файл в ней бы потерялся
+	if *attestPath != "" && exitCode != 2 {
+		if err := writeAttestation(*attestPath, attested, exitCode == 0); err != nil {
+			fmt.Fprintf(os.Stdout, "%s: cannot write attestation: %v\n", filepath.Base(*attestPath), err)
+			os.Exit(2)
 		}
 	}
+	os.Exit(exitCode)
 }
 
-func validateProbe(n *yaml.Node, bag *errBag, field string) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, field+" must be object")
-		return
-	}
-	get, ok := m["httpGet"]
-	if !ok {
-		bag.add(0, "httpGet is required")
-		return
-	}
-	validateHTTPGet(get, bag)
+// fileOutcome — проверенный файл; failed — его не удалось прочитать или
+// разобрать, тогда в res одна находка с причиной.
+type fileOutcome struct {
+	name   string
+	path   string
+	data   []byte
+	res    *validator.Result
+	failed bool
+	// reported — сколько находок из res уже отдано репортеру
+	reported int
 }
 
-func validateHTTPGet(n *yaml.Node, bag *errBag) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "httpGet must be object")
-		return
+// flush отдаёт репортеру находки, которых он ещё не видел: с --stream
+// часть находок файла печатается до проверок по набору.
+func (o *fileOutcome) flush(rep report.Reporter) {
+	for _, is := range o.res.Issues[o.reported:] {
+		if err := rep.Report(o.name, is); err != nil {
+			outputFailed(err)
+		}
 	}
+	o.reported = len(o.res.Issues)
+}
 
-	// path
-	p, ok := m["path"]
-	if !ok {
-		bag.add(0, "path is required")
-	} else if !isScalarString(p) {
-		bag.add(p.Line, "path must be string")
-	} else if !strings.HasPrefix(p.Value, "/") {
-		bag.add(p.Line, fmt.Sprintf("path has invalid format '%s'", p.Value))
+// dropInfo убирает из ещё не напечатанных находок заметки уровня info:
+// без --verbose вывод тот же, что и до появления заметок.
+func (o *fileOutcome) dropInfo() {
+	kept := o.res.Issues[:o.reported]
+	for _, is := range o.res.Issues[o.reported:] {
+		if is.Severity != validator.SeverityInfo {
+			kept = append(kept, is)
+		}
 	}
+	o.res.Issues = kept
+}
 
-	// port
-	pt, ok := m["port"]
-	if !ok {
-		bag.add(0, "port is required")
-	} else if !isScalarInt(pt) {
-		bag.add(pt.Line, "port must be int")
-	} else {
-		val, err := toInt(pt.Value)
-		if err != nil || val < 1 || val > 65535 {
-			bag.add(pt.Line, "port value out of range")
+// runFailed решает, валят ли прогон находки проверенных файлов: есть
+// находка не ниже failOn или нарушен порог — thresholds конфига либо
+// --max-warnings (maxWarnings < 0 — без предела). Пороги считаются по
+// всему набору, после baseline и отзывов; нарушения возвращаются для
+// печати.
+func runFailed(v *validator.Validator, results []*validator.Result, failOn validator.Severity, maxWarnings int) (bool, []validator.Breach) {
+	failed := false
+	warnings := 0
+	for _, res := range results {
+		if res.FailedAt(failOn) {
+			failed = true
+		}
+		for _, is := range res.Issues {
+			if is.Severity == validator.SeverityWarning {
+				warnings++
+			}
 		}
 	}
+	breaches := v.Breaches(results)
+	if maxWarnings >= 0 && warnings > maxWarnings {
+		breaches = append(breaches, validator.Breach{Rule: "--max-warnings", Max: maxWarnings, Count: warnings})
+	}
+	return failed || len(breaches) > 0, breaches
 }
 
-var reMem = regexp.MustCompile(`^\d+(Ki|Mi|Gi)$`)
+func outputFailed(err error) {
+	fmt.Fprintf(os.Stderr, "cannot write output: %v\n", err)
+	os.Exit(2)
+}
 
-func validateResourceRequirements(n *yaml.Node, bag *errBag) {
-	m, node := getMap(n)
-	if m == nil {
-		bag.add(node.Line, "resources must be object")
-		return
-	}
-	if lim, ok := m["limits"]; ok {
-		validateResourceMap(lim, bag, "limits")
-	}
-	if req, ok := m["requests"]; ok {
-		validateResourceMap(req, bag, "requests")
-	}
+func (o *fileOutcome) fail(code string, args ...any) {
+	o.failed = true
+	o.res = &validator.Result{}
+	addIssue(o.res, validator.SeverityError, 0, code, args...)
 }
 
-func validateResourceMap(n *yaml.Node, bag *errBag, field string) {
-	if n.Kind != yaml.MappingNode {
-		bag.add(n.Line, field+" must be object")
-		return
-	}
-	for i := 0; i < len(n.Content); i += 2 {
-		k := n.Content[i]
-		v := n.Content[i+1]
-		if !isScalarString(k) {
-			bag.add(v.Line, field+" must be object")
-			continue
-		}
-		switch k.Value {
-		case "cpu":
-			if !isScalarInt(v) {
-				bag.add(v.Line, "cpu must be int")
-			}
-		case "memory":
-			if !isScalarString(v) {
-				bag.add(v.Line, "memory must be string")
-			} else if !reMem.MatchString(v.Value) {
-				bag.add(v.Line, fmt.Sprintf("memory has invalid format '%s'", v.Value))
-			}
-		default:
-			// лишние ключи игнорируем
-		}
+// defaultOutput — pretty в терминале, text во всех остальных случаях:
+// автотесты и скрипты читают stdout через пайп и ждут плоские строки.
+func defaultOutput() string {
+	if info, err := os.Stdout.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		return "pretty"
 	}
+	return "text"
 }
 
-// --------- small utils ----------
+// stringList — повторяемый строковый флаг.
+type stringList []string
 
-func toInt(s string) (int, error) {
-	var x int
-	_, err := fmt.Sscanf(s, "%d", &x)
-	if err != nil {
-		return 0, errors.New("not int")
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func configName(path string) string {
+	if path == "" {
+		return defaultConfigFile
 	}
-	return x, nil
+	return filepath.Base(path)
 }
@@ -0,0 +1,63 @@
+// normalized.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// normalizedWriter пишет документы в канонический JSON для
+// --emit-normalized: по файлу на объект, kind-namespace-name.json, чтобы
+// дерево вывода можно было сравнить diff'ом между ревизиями.
+type normalizedWriter struct {
+	dir  string
+	seen map[string]int
+}
+
+func newNormalizedWriter(dir string) (*normalizedWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &normalizedWriter{dir: dir, seen: map[string]int{}}, nil
+}
+
+// write нормализует документы файла; нечитаемые и Terraform-файлы
+// пропускаются.
+func (w *normalizedWriter) write(v *validator.Validator, out *fileOutcome) error {
+	if w == nil || out.failed || isTerraformFile(out.path) {
+		return nil
+	}
+	docs, err := v.Normalize(out.data, out.res.Issues)
+	if err != nil {
+		return err
+	}
+	for i, doc := range docs {
+		if err := os.WriteFile(filepath.Join(w.dir, w.name(out, i, doc)), doc.JSON, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name — имя файла объекта; без kind или name — по файлу и номеру
+// документа. Повтор того же объекта получает суффикс, а не затирает его.
+func (w *normalizedWriter) name(out *fileOutcome, i int, doc validator.NormalizedDocument) string {
+	base := fmt.Sprintf("%s-%d", strings.TrimSuffix(filepath.Base(out.name), filepath.Ext(out.name)), i+1)
+	if doc.Kind != "" && doc.Name != "" {
+		parts := []string{strings.ToLower(doc.Kind)}
+		if doc.Namespace != "" {
+			parts = append(parts, doc.Namespace)
+		}
+		base = strings.Join(append(parts, doc.Name), "-")
+	}
+	base = strings.ReplaceAll(base, string(filepath.Separator), "_")
+	w.seen[base]++
+	if n := w.seen[base]; n > 1 {
+		base = fmt.Sprintf("%s.%d", base, n)
+	}
+	return base + ".json"
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func TestNormalizedWriter(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	w, err := newNormalizedWriter(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("kind: Service\nmetadata: {name: web, namespace: prod}\n---\nkind: Service\nmetadata: {name: web, namespace: prod}\n---\nkind: List\n")
+	out := &fileOutcome{name: "k8s/app.yaml", path: "k8s/app.yaml", data: data, res: &validator.Result{}}
+	if err := w.write(v, out); err != nil {
+		t.Fatal(err)
+	}
+	// битые файлы не пишутся
+	if err := w.write(v, &fileOutcome{name: "bad.yaml", data: []byte("a: ["), failed: true}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{"app-3.json", "service-prod-web.2.json", "service-prod-web.json"}
+	if len(got) != len(want) {
+		t.Fatalf("files %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("files %q, want %q", got, want)
+			break
+		}
+	}
+}
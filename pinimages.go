@@ -0,0 +1,174 @@
+// pinimages.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Подкоманда pin-images закрепляет образы по дайджесту: тег каждого
+// образа разрешается в реестре, и ссылка переписывается в
+// name:tag@sha256:... прямо в исходном тексте — комментарии и
+// форматирование остаются. Тег сохраняется, чтобы по нему можно было
+// обновить закрепление. С -check файлы не меняются, а устаревшие и
+// незакреплённые образы печатаются как находки.
+//
+//	yamlvalid pin-images --resolver docker-config k8s/*.yaml
+//	yamlvalid pin-images --check k8s/*.yaml
+func runPinImages(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("pin-images", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "path to config file (default "+defaultConfigFile+" if present)")
+	resolver := fs.String("resolver", "docker-config", "how to reach registries: docker-config (credentials from ~/.docker/config.json or $DOCKER_CONFIG)")
+	check := fs.Bool("check", false, "only verify that every image is pinned to the digest its tag currently points to")
+	backup := fs.Bool("backup", false, "keep the original of each rewritten file as file.bak")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid pin-images [-resolver docker-config] [-check] [-config file] [file | -]...")
+		fs.PrintDefaults()
+	}
+	var paths []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		paths = append(paths, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(paths) == 0 {
+		paths = []string{stdinArg}
+	}
+	if *resolver != "docker-config" {
+		fmt.Fprintf(stderr, "unknown resolver '%s' (known: docker-config)\n", *resolver)
+		return 2
+	}
+	// образы берутся из проверки, поэтому закрепляются только те, что
+	// проходят политику образов конфига
+	cfg, err := loadConfig(*configPath)
+	var v *validator.Validator
+	if err == nil {
+		v, err = validator.New(*cfg)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: cannot load config: %v\n", configName(*configPath), err)
+		return 2
+	}
+
+	client := newRegistryClient()
+	// тег разрешается один раз на прогон, даже если образ во многих файлах
+	digests := map[string]string{}
+	resolve := func(ref string) (string, error) {
+		if d, ok := digests[ref]; ok {
+			return d, nil
+		}
+		d, err := client.resolveDigest(ref)
+		if err == nil {
+			digests[ref] = d
+		}
+		return d, err
+	}
+
+	exitCode := 0
+	for _, path := range paths {
+		name := path
+		if path == stdinArg {
+			name = defaultStdinName
+		}
+		if isTerraformFile(path) {
+			continue
+		}
+		data, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		res, err := v.ValidateFile("", data)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot unmarshal file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		var edits []validator.Issue
+		for _, im := range res.Images {
+			repo, tag, digest := splitImage(im.Ref)
+			if tag == "" && digest != "" {
+				continue // закреплён без тега: сверять не с чем
+			}
+			if tag == "" {
+				tag = "latest"
+			}
+			current, err := resolve(repo + ":" + tag)
+			if err != nil {
+				fmt.Fprintf(stderr, "%s:%d cannot resolve %s: %v\n", name, im.Line, im.Ref, err)
+				exitCode = 2
+				continue
+			}
+			if digest == current {
+				continue
+			}
+			if *check {
+				if digest == "" {
+					fmt.Fprintf(stdout, "%s:%d image %s is not pinned (%s)\n", name, im.Line, im.Ref, current)
+				} else {
+					fmt.Fprintf(stdout, "%s:%d image %s is pinned to an outdated digest (%s)\n", name, im.Line, im.Ref, current)
+				}
+				if exitCode == 0 {
+					exitCode = 1
+				}
+				continue
+			}
+			if fix := imageFix(data, im, repo+":"+tag+"@"+current); fix != nil {
+				edits = append(edits, validator.Issue{Line: im.Line, Fix: fix})
+			}
+		}
+		if *check {
+			continue
+		}
+		out, _ := validator.ApplyFixes(data, edits)
+		if path == stdinArg {
+			if _, err := stdout.Write(out); err != nil {
+				fmt.Fprintf(stderr, "%s: cannot write manifests: %v\n", name, err)
+				return 2
+			}
+			continue
+		}
+		if bytes.Equal(out, data) {
+			continue
+		}
+		if err := writeFileAtomic(path, out, *backup); err != nil {
+			fmt.Fprintf(stderr, "%s: cannot write file: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		fmt.Fprintf(stderr, "%s: images pinned: %d\n", name, len(edits))
+	}
+	return exitCode
+}
+
+// imageFix — правка, заменяющая ссылку im на pinned в том же стиле
+// кавычек; nil, если текст на месте узла не удалось узнать.
+func imageFix(data []byte, im validator.Image, pinned string) *validator.Fix {
+	lines := strings.Split(string(data), "\n")
+	if im.Line < 1 || im.Line > len(lines) || im.Column < 1 {
+		return nil
+	}
+	line := []rune(lines[im.Line-1])
+	if im.Column > len(line) {
+		return nil
+	}
+	rest := string(line[im.Column-1:])
+	for _, q := range []string{`"`, `'`, ""} {
+		if strings.HasPrefix(rest, q+im.Ref+q) {
+			return &validator.Fix{Line: im.Line, Column: im.Column, Old: q + im.Ref + q, New: q + pinned + q}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func TestPinImages(t *testing.T) {
+	host := fakeRegistry(t)
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(config, []byte("images:\n  registries: ['"+host+"']\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	image := host + "/team/app:1.0"
+	pinned := image + "@sha256:" + strings.Repeat("a", 64)
+	pod := "apiVersion: v1\nkind: Pod\nmetadata: {name: web}\nspec:\n  containers:\n" +
+		"    - name: web\n      image: \"" + image + "\" # комментарий остаётся\n" +
+		"    - name: sidecar\n      image: " + image + "\n"
+	path := filepath.Join(dir, "pod.yaml")
+	if err := os.WriteFile(path, []byte(pod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := runPinImages([]string{"-check", "-config", config, path}, &stdout, &stderr); code != 1 ||
+		strings.Count(stdout.String(), "is not pinned") != 2 {
+		t.Errorf("check unpinned: exit %d, stdout %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != pod {
+		t.Errorf("-check changed the file:\n%s", data)
+	}
+
+	stdout.Reset()
+	if code := runPinImages([]string{path, "-config", config}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d, stderr %q", code, stderr.String())
+	}
+	want := strings.ReplaceAll(pod, "image: "+image, "image: "+pinned)
+	want = strings.Replace(want, "\""+image+"\"", "\""+pinned+"\"", 1)
+	if data, _ := os.ReadFile(path); string(data) != want {
+		t.Errorf("pinned file:\n%s\nwant:\n%s", data, want)
+	}
+
+	stdout.Reset()
+	if code := runPinImages([]string{"-check", "-config", config, path}, &stdout, &stderr); code != 0 || stdout.Len() != 0 {
+		t.Errorf("check pinned: exit %d, stdout %q", code, stdout.String())
+	}
+}
+
+// Правка сохраняет кавычки ссылки.
+func TestImageFix(t *testing.T) {
+	data := []byte("image: 'nginx:1.25'\n")
+	fix := imageFix(data, validator.Image{Line: 1, Column: 8, Ref: "nginx:1.25"}, "nginx:1.25@sha256:ab")
+	if fix == nil || fix.Old != "'nginx:1.25'" || fix.New != "'nginx:1.25@sha256:ab'" {
+		t.Errorf("fix %+v", fix)
+	}
+	if fix := imageFix(data, validator.Image{Line: 1, Column: 3, Ref: "nginx:1.25"}, "x"); fix != nil {
+		t.Errorf("fix at wrong column %+v", fix)
+	}
+}
@@ -0,0 +1,127 @@
+// quota.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// loadBudgets читает бюджеты неймспейсов в формате spec.hard у
+// ResourceQuota:
+//
+//	team-a:
+//	  requests.cpu: "8"
+//	  requests.memory: 16Gi
+func loadBudgets(path string) ([]validator.Quota, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]map[string]string
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&raw); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(raw))
+	for ns := range raw {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+	out := make([]validator.Quota, 0, len(raw))
+	for _, ns := range names {
+		hard := raw[ns]
+		q, err := validator.QuotaFromHard(ns, func(key string) (string, bool) {
+			v, ok := hard[key]
+			return v, ok
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ns, err)
+		}
+		out = append(out, q)
+	}
+	return out, nil
+}
+
+// checkQuotas складывает requests подов по неймспейсам через все файлы
+// прогона и предупреждает на том поде, с которого сумма превысила квоту.
+// Квоты — из файла бюджетов и из ResourceQuota в наборе; при нескольких
+// на один неймспейс действует самая строгая, как в kube-apiserver.
+func checkQuotas(outcomes []fileOutcome, budgets []validator.Quota) {
+	limits := map[string]validator.Quota{}
+	tighten := func(q validator.Quota) {
+		cur, ok := limits[q.Namespace]
+		if !ok {
+			limits[q.Namespace] = q
+			return
+		}
+		cur.CPU = tighter(cur.CPU, q.CPU)
+		cur.Memory = tighter(cur.Memory, q.Memory)
+		limits[q.Namespace] = cur
+	}
+	for _, q := range budgets {
+		tighten(q)
+	}
+	for _, out := range outcomes {
+		if out.res != nil {
+			for _, q := range out.res.Quotas {
+				tighten(q)
+			}
+		}
+	}
+	if len(limits) == 0 {
+		return
+	}
+
+	type usage struct {
+		cpu, memory              float64
+		cpuReported, memReported bool
+	}
+	used := map[string]*usage{}
+	for _, out := range outcomes {
+		if out.failed {
+			continue
+		}
+		for _, w := range out.res.Workloads {
+			q, ok := limits[w.Namespace]
+			if !ok {
+				continue
+			}
+			u := used[w.Namespace]
+			if u == nil {
+				u = &usage{}
+				used[w.Namespace] = u
+			}
+			u.cpu += w.CPU
+			u.memory += w.Memory
+			if q.CPU > 0 && u.cpu > q.CPU && !u.cpuReported {
+				u.cpuReported = true
+				addIssue(out.res, validator.SeverityWarning, w.Line, validator.CodeQuotaExceeded,
+					w.Namespace, "cpu", formatCPU(u.cpu), formatCPU(q.CPU))
+			}
+			if q.Memory > 0 && u.memory > q.Memory && !u.memReported {
+				u.memReported = true
+				addIssue(out.res, validator.SeverityWarning, w.Line, validator.CodeQuotaExceeded,
+					w.Namespace, "memory", formatMemory(u.memory), formatMemory(q.Memory))
+			}
+		}
+	}
+}
+
+// tighter — меньший из лимитов, где 0 значит «без лимита».
+func tighter(a, b float64) float64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func formatCPU(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+func formatMemory(v float64) string {
+	return strconv.FormatFloat(v/(1<<20), 'f', -1, 64) + "Mi"
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func quotaPod(ns, cpu, memory string) string {
+	return "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n  namespace: " + ns + "\nspec:\n  containers:\n" +
+		"    - name: web\n      image: registry.bigbrother.io/web:1.0\n      resources:\n" +
+		"        requests: {cpu: " + cpu + ", memory: " + memory + "}\n        limits: {cpu: " + cpu + ", memory: " + memory + "}\n"
+}
+
+// Квота складывается через все файлы: находка — на том поде, с которого
+// сумма вышла за бюджет, и только одна на ресурс.
+func TestCheckQuotas(t *testing.T) {
+	budgets := filepath.Join(t.TempDir(), "budgets.yaml")
+	if err := os.WriteFile(budgets, []byte("shop:\n  requests.cpu: \"3\"\n  requests.memory: 1Gi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	quotas, err := loadBudgets(budgets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{
+		quotaPod("shop", "2", "512Mi"),
+		quotaPod("shop", "2", "256Mi"),
+		quotaPod("shop", "500m", "64Mi"),
+		quotaPod("other", "8", "8Gi"),
+		// ResourceQuota в наборе строже бюджета по памяти
+		"apiVersion: v1\nkind: ResourceQuota\nmetadata:\n  name: q\n  namespace: shop\nspec:\n  hard: {requests.memory: 600Mi}\n",
+	}
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outcomes := make([]fileOutcome, len(files))
+	for i, data := range files {
+		res, err := v.Validate([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outcomes[i] = fileOutcome{res: res}
+	}
+	checkQuotas(outcomes, quotas)
+
+	want := []string{
+		"",
+		"namespace 'shop' requests cpu 4, exceeding quota of 3\n" +
+			"namespace 'shop' requests memory 768Mi, exceeding quota of 600Mi\n",
+		"", "", "",
+	}
+	for i, out := range outcomes {
+		got := ""
+		for _, is := range out.res.Issues {
+			if is.Code == validator.CodeQuotaExceeded {
+				got += is.Message() + "\n"
+			}
+		}
+		if got != want[i] {
+			t.Errorf("file %d: QTA001 findings\n%s\nwant\n%s", i, got, want[i])
+		}
+	}
+}
+
+func TestLoadBudgetsInvalid(t *testing.T) {
+	budgets := filepath.Join(t.TempDir(), "budgets.yaml")
+	if err := os.WriteFile(budgets, []byte("shop:\n  requests.cpu: lots\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadBudgets(budgets); err == nil || !strings.Contains(err.Error(), "shop: requests.cpu") {
+		t.Errorf("error %v, want invalid requests.cpu of shop", err)
+	}
+}
@@ -0,0 +1,26 @@
+// references.go
+package main
+
+import "github.com/forceofprophet/yandexgolang2/validator"
+
+// checkReferences разрешает ссылки из аннотаций (annotationRules с
+// references: true) по объектам всех файлов прогона: зависимость обычно
+// лежит в соседнем файле.
+func checkReferences(outcomes []fileOutcome) {
+	objects := map[validator.ObjectRef]bool{}
+	for _, out := range outcomes {
+		for _, o := range out.res.Objects {
+			objects[o] = true
+		}
+	}
+	for _, out := range outcomes {
+		if out.failed {
+			continue
+		}
+		for _, ref := range out.res.References {
+			if !objects[ref.Target] {
+				addIssue(out.res, ref.Severity, ref.Line, validator.CodeReferenceMissing, ref.Annotation, ref.Target.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Ссылка разрешается по объектам всех файлов прогона, а не только своего.
+func TestCheckReferences(t *testing.T) {
+	v, err := validator.New(validator.Config{AnnotationRules: []validator.AnnotationRule{{Key: "team.io/depends-on", References: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []string{
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n  annotations:\n    team.io/depends-on: Service/db, Service/cache\n",
+		"apiVersion: v1\nkind: Service\nmetadata:\n  name: db\nspec:\n  ports: [{port: 5432}]\n",
+	}
+	outcomes := make([]fileOutcome, len(files))
+	for i, data := range files {
+		res, err := v.Validate([]byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		outcomes[i] = fileOutcome{res: res}
+	}
+	checkReferences(outcomes)
+
+	var got []string
+	for _, is := range outcomes[0].res.Issues {
+		if is.Code == validator.CodeReferenceMissing {
+			got = append(got, is.Message())
+		}
+	}
+	want := "annotation team.io/depends-on refers to Service/default/cache, which is not in the validated set"
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("ANN004 findings %q, want [%q]", got, want)
+	}
+	if len(outcomes[1].res.Issues) != 0 {
+		t.Errorf("unexpected findings in second file: %v", outcomes[1].res.Issues)
+	}
+}
@@ -0,0 +1,390 @@
+// registry.go
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// ---------- image existence (--check-image-exists) ----------
+
+// checkImagesExist делает HEAD на manifest каждого образа. Отсутствующий
+// образ — ошибка; недоступный реестр — только предупреждение, чтобы сеть
+// не ломала прогон.
+func checkImagesExist(res *validator.Result) {
+	client := newRegistryClient()
+	var refs []string
+	checked := map[string]error{}
+	for _, im := range res.Images {
+		if _, seen := checked[im.Ref]; !seen {
+			checked[im.Ref] = nil
+			refs = append(refs, im.Ref)
+		}
+	}
+
+	// параллельно, но не больше netConcurrency запросов к реестрам разом
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, max(netConcurrency, 1))
+	for _, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(ref string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := client.manifestExists(ref)
+			mu.Lock()
+			checked[ref] = err
+			mu.Unlock()
+		}(ref)
+	}
+	wg.Wait()
+
+	for _, im := range res.Images {
+		switch err := checked[im.Ref]; {
+		case err == nil:
+		case errors.Is(err, errManifestNotFound):
+			addIssue(res, validator.SeverityError, im.Line, validator.CodeImageNotFound, im.Ref)
+		default:
+			addIssue(res, validator.SeverityWarning, im.Line, validator.CodeImageCheckFailed, im.Ref, err.Error())
+		}
+	}
+}
+
+var errManifestNotFound = errors.New("manifest not found")
+
+// imageRef — разобранная ссылка host/repo:tag или host/repo@digest.
+type imageRef struct {
+	host, repo, reference string
+}
+
+func parseImageRef(ref string) imageRef {
+	var r imageRef
+	name := ref
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, r.reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, r.reference = name[:i], name[i+1:]
+	}
+	if r.reference == "" {
+		r.reference = "latest"
+	}
+	// как в docker: первый сегмент — хост, если похож на хост
+	if host, rest, ok := strings.Cut(name, "/"); ok && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		r.host, r.repo = host, rest
+	} else {
+		r.host, r.repo = "docker.io", name
+		if !strings.Contains(name, "/") {
+			r.repo = "library/" + name
+		}
+	}
+	return r
+}
+
+type registryClient struct {
+	http  *http.Client
+	creds dockerCreds
+}
+
+func newRegistryClient() *registryClient {
+	return &registryClient{
+		http:  &http.Client{Timeout: 15 * time.Second},
+		creds: loadDockerCreds(),
+	}
+}
+
+var manifestAccept = strings.Join([]string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+func (c *registryClient) manifestExists(ref string) error {
+	_, err := c.headManifest(parseImageRef(ref))
+	return err
+}
+
+// resolveDigest — дайджест manifest, на который сейчас указывает тег
+// ссылки (заголовок Docker-Content-Digest).
+func (c *registryClient) resolveDigest(ref string) (string, error) {
+	resp, err := c.headManifest(parseImageRef(ref))
+	if err != nil {
+		return "", err
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", errors.New("registry did not report a manifest digest")
+	}
+	return digest, nil
+}
+
+// headManifest возвращает ответ HEAD на manifest, проходя
+// Basic/Bearer-авторизацию по заголовку WWW-Authenticate.
+func (c *registryClient) headManifest(r imageRef) (*http.Response, error) {
+	apiHost := r.host
+	if apiHost == "docker.io" {
+		apiHost = "registry-1.docker.io"
+	}
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", apiHost, r.repo, r.reference)
+
+	resp, err := c.do(u, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		auth, err := c.authorize(r, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return nil, err
+		}
+		if resp, err = c.do(u, auth); err != nil {
+			return nil, err
+		}
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		return nil, errManifestNotFound
+	default:
+		return nil, fmt.Errorf("registry responded %s", resp.Status)
+	}
+}
+
+func (c *registryClient) do(u, auth string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodHead, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", manifestAccept)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := netSend(c.http, req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+func (c *registryClient) authorize(r imageRef, challenge string) (string, error) {
+	user, secret, hasCreds := c.creds.lookup(r.host)
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCreds {
+			return "", errors.New("registry requires credentials")
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+secret)), nil
+	case "bearer":
+		q := url.Values{}
+		if params["service"] != "" {
+			q.Set("service", params["service"])
+		}
+		q.Set("scope", "repository:"+r.repo+":pull")
+		req, err := http.NewRequest(http.MethodGet, params["realm"]+"?"+q.Encode(), nil)
+		if err != nil {
+			return "", err
+		}
+		if hasCreds {
+			req.SetBasicAuth(user, secret)
+		}
+		resp, err := netSend(c.http, req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("token endpoint responded %s", resp.Status)
+		}
+		var tok struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+			return "", err
+		}
+		if tok.Token == "" {
+			tok.Token = tok.AccessToken
+		}
+		return "Bearer " + tok.Token, nil
+	default:
+		return "", fmt.Errorf("unsupported auth challenge '%s'", challenge)
+	}
+}
+
+// parseChallenge разбирает `Bearer realm="...",service="...",scope="..."`.
+func parseChallenge(h string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(h), " ")
+	params := map[string]string{}
+	for _, part := range strings.Split(rest, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok {
+			params[strings.ToLower(k)] = strings.Trim(v, `"`)
+		}
+	}
+	return strings.ToLower(scheme), params
+}
+
+// ---------- network limits ----------
+
+// Общие ограничения для всех онлайн-проверок (флаги --net-*), чтобы
+// большие прогоны не заваливали реестры запросами.
+var (
+	netLimiter     = newRateLimiter(10)
+	netConcurrency = 4
+	netRetries     = 3
+)
+
+// netSend выполняет запрос через общий лимитер, повторяя его с
+// экспоненциальной задержкой при сетевых ошибках, 429 и 5xx; задержку
+// может задать сервер заголовком Retry-After. Тело запроса перед
+// повтором берётся заново из GetBody.
+func netSend(client *http.Client, req *http.Request) (*http.Response, error) {
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		netLimiter.wait()
+		resp, err := client.Do(req)
+		retry := err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retry || attempt >= netRetries {
+			return resp, err
+		}
+		delay := backoff << attempt
+		if resp != nil {
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				delay = time.Duration(secs) * time.Second
+			}
+			resp.Body.Close()
+		}
+		time.Sleep(delay)
+	}
+}
+
+// rateLimiter раздаёт слоты равномерно: не чаще одного запроса в interval.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter с rps <= 0 не ограничивает ничего.
+func newRateLimiter(rps float64) *rateLimiter {
+	if rps <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rps)}
+}
+
+func (l *rateLimiter) wait() {
+	if l.interval == 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+	time.Sleep(time.Until(at))
+}
+
+// ---------- docker credentials ----------
+
+// dockerCreds — ~/.docker/config.json (или $DOCKER_CONFIG): auths,
+// credsStore и credHelpers, как их понимает docker login.
+type dockerCreds struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+func loadDockerCreds() dockerCreds {
+	var c dockerCreds
+	dir := os.Getenv("DOCKER_CONFIG")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return c
+		}
+		dir = filepath.Join(home, ".docker")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err == nil {
+		_ = json.Unmarshal(data, &c) // битый конфиг = анонимный доступ
+	}
+	return c
+}
+
+func (c dockerCreds) lookup(host string) (user, secret string, ok bool) {
+	keys := []string{host, "https://" + host}
+	if host == "docker.io" {
+		keys = append(keys, "https://index.docker.io/v1/", "index.docker.io")
+	}
+	helper := c.CredsStore
+	if h, ok := c.CredHelpers[host]; ok {
+		helper = h
+	}
+	if helper != "" {
+		for _, k := range keys {
+			if user, secret, ok = runCredHelper(helper, k); ok {
+				return user, secret, true
+			}
+		}
+	}
+	for _, k := range keys {
+		a, found := c.Auths[k]
+		if !found || a.Auth == "" {
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(a.Auth)
+		if err != nil {
+			continue
+		}
+		if user, secret, ok = strings.Cut(string(raw), ":"); ok {
+			return user, secret, true
+		}
+	}
+	return "", "", false
+}
+
+func runCredHelper(helper, serverURL string) (string, string, bool) {
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(serverURL)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", "", false
+	}
+	var res struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if json.Unmarshal(out, &res) != nil || res.Secret == "" {
+		return "", "", false
+	}
+	return res.Username, res.Secret, true
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// fakeRegistry — реестр с образами team/app:1.0 (открытый) и
+// team/private:1.0 (за Bearer-токеном); team/denied отвечает 403.
+func fakeRegistry(t *testing.T) string {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			if r.URL.Query().Get("scope") != "repository:team/private:pull" {
+				http.Error(w, "bad scope", http.StatusBadRequest)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]string{"access_token": "secret"})
+		case r.URL.Path == "/v2/team/app/manifests/1.0":
+			w.Header().Set("Docker-Content-Digest", "sha256:"+strings.Repeat("a", 64))
+		case r.URL.Path == "/v2/team/private/manifests/1.0":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+srv.URL+`/token",service="fake"`)
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		case strings.HasPrefix(r.URL.Path, "/v2/team/denied/"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	// клиент реестра ходит через http.DefaultTransport: подменяем его на
+	// транспорт, который доверяет сертификату тестового сервера
+	saved := http.DefaultTransport
+	http.DefaultTransport = srv.Client().Transport
+	t.Cleanup(func() { http.DefaultTransport = saved })
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	keepNetLimits(t)
+	netRetries, netLimiter = 0, newRateLimiter(0)
+	return strings.TrimPrefix(srv.URL, "https://")
+}
+
+// keepNetLimits возвращает общие сетевые лимиты после теста: их меняют и
+// тесты, и подкоманды с флагами --net-*.
+func keepNetLimits(t *testing.T) {
+	savedRetries, savedConcurrency, savedLimiter := netRetries, netConcurrency, netLimiter
+	t.Cleanup(func() { netRetries, netConcurrency, netLimiter = savedRetries, savedConcurrency, savedLimiter })
+}
+
+func TestCheckImagesExist(t *testing.T) {
+	host := fakeRegistry(t)
+	tests := []struct {
+		ref  string
+		want string // "" — образ есть
+		sev  validator.Severity
+	}{
+		{host + "/team/app:1.0", "", 0},
+		{host + "/team/private:1.0", "", 0},
+		{host + "/team/app:2.0", validator.CodeImageNotFound, validator.SeverityError},
+		// недоступный реестр — только предупреждение
+		{host + "/team/denied:1.0", validator.CodeImageCheckFailed, validator.SeverityWarning},
+	}
+	for _, tt := range tests {
+		res := &validator.Result{Images: []validator.Image{{Line: 7, Ref: tt.ref}}}
+		checkImagesExist(res)
+		var got string
+		for _, is := range res.Issues {
+			got += is.Code
+			if is.Line != 7 || is.Severity != tt.sev {
+				t.Errorf("%s: finding on line %d severity %v, want line 7 severity %v", tt.ref, is.Line, is.Severity, tt.sev)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: codes %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryRetry(t *testing.T) {
+	fakeRegistry(t) // чистые DOCKER_CONFIG и лимиты
+	netRetries = 2
+	hits := 0
+	flaky := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// первые два запроса — 429 с Retry-After: 0, третий проходит
+		if hits++; hits <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer flaky.Close()
+	http.DefaultTransport = flaky.Client().Transport
+
+	res := &validator.Result{Images: []validator.Image{{Line: 1, Ref: strings.TrimPrefix(flaky.URL, "https://") + "/team/app:1.0"}}}
+	checkImagesExist(res)
+	if len(res.Issues) != 0 || hits != 3 {
+		t.Errorf("findings %+v after %d requests, want none after 3", res.Issues, hits)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(100)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		l.wait()
+	}
+	if d := time.Since(start); d < 35*time.Millisecond {
+		t.Errorf("5 requests at 100 rps took %v, want >= 40ms", d)
+	}
+	// 0 — без ограничений
+	newRateLimiter(0).wait()
+}
+
+func TestParseImageRef(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want imageRef
+	}{
+		{"nginx", imageRef{"docker.io", "library/nginx", "latest"}},
+		{"team/app:1.0", imageRef{"docker.io", "team/app", "1.0"}},
+		{"localhost:5000/app", imageRef{"localhost:5000", "app", "latest"}},
+		{"ghcr.io/team/app@sha256:abc", imageRef{"ghcr.io", "team/app", "sha256:abc"}},
+	}
+	for _, tt := range tests {
+		if got := parseImageRef(tt.ref); got != tt.want {
+			t.Errorf("parseImageRef(%q) = %+v, want %+v", tt.ref, got, tt.want)
+		}
+	}
+}
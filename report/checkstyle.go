@@ -0,0 +1,59 @@
+// checkstyle.go
+package report
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Checkstyle — формат Checkstyle XML, который читают Jenkins Warnings NG
+// и reviewdog; source — код находки с префиксом инструмента.
+type Checkstyle struct {
+	w      io.Writer
+	issues byFile
+}
+
+func NewCheckstyle(w io.Writer) *Checkstyle { return &Checkstyle{w: w} }
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr,omitempty"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+func (r *Checkstyle) Start() error { return nil }
+
+func (r *Checkstyle) Report(file string, is validator.Issue) error {
+	r.issues.add(file, is)
+	return nil
+}
+
+func (r *Checkstyle) Finish(s Summary) error {
+	report := checkstyleReport{Version: "4.3"}
+	for _, name := range r.issues.files(s) {
+		f := checkstyleFile{Name: name}
+		for _, is := range r.issues.issues[name] {
+			f.Errors = append(f.Errors, checkstyleError{
+				Line: is.Line, Column: is.Column, Severity: is.Severity.String(),
+				Message: message(is), Source: "yamlvalid." + is.Code,
+			})
+		}
+		report.Files = append(report.Files, f)
+	}
+	return writeXML(r.w, report)
+}
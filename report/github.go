@@ -0,0 +1,45 @@
+// github.go
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// GitHub печатает workflow commands GitHub Actions: находки появляются
+// прямо в диффе пулл-реквеста без отдельного action.
+type GitHub struct{ w io.Writer }
+
+func NewGitHub(w io.Writer) *GitHub { return &GitHub{w: w} }
+
+var githubLevels = map[validator.Severity]string{
+	validator.SeverityError:   "error",
+	validator.SeverityWarning: "warning",
+	validator.SeverityInfo:    "notice",
+}
+
+// экранирование из @actions/core: в данных — %, CR, LF, в свойствах ещё : и ,
+var (
+	githubData     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubProperty = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+func (r *GitHub) Start() error { return nil }
+
+func (r *GitHub) Report(file string, is validator.Issue) error {
+	props := "file=" + githubProperty.Replace(file)
+	if is.Line > 0 {
+		props += fmt.Sprintf(",line=%d", is.Line)
+		if is.Column > 0 {
+			props += fmt.Sprintf(",col=%d", is.Column)
+		}
+	}
+	props += ",title=" + githubProperty.Replace(is.Rule())
+	_, err := fmt.Fprintf(r.w, "::%s %s::%s\n", githubLevels[is.Severity], props, githubData.Replace(message(is)))
+	return err
+}
+
+func (r *GitHub) Finish(Summary) error { return nil }
@@ -0,0 +1,108 @@
+// json.go
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+type jsonFinding struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Document int    `json:"document,omitempty"`
+	Item     int    `json:"item,omitempty"`
+	Rule     string `json:"rule,omitempty"`
+	RuleName string `json:"ruleName,omitempty"`
+	Message  string `json:"message"`
+	Help     string `json:"help,omitempty"`
+	Severity string `json:"severity"`
+	// Fingerprint — для yamlvalid suppress, только с --feedback-file
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// DocumentSHA256 — sha256 текста документа, в котором находка
+	DocumentSHA256 string `json:"documentSha256,omitempty"`
+	// Tool — только в NDJSON: у потока нет общей шапки
+	Tool *Tool `json:"tool,omitempty"`
+}
+
+func newJSONFinding(file string, is validator.Issue) jsonFinding {
+	return jsonFinding{
+		File: file, Line: is.Line, Column: is.Column, Document: is.Document, Item: is.Item, Rule: is.Code, RuleName: validator.RuleName(is.Code),
+		Message: is.Message(), Help: is.Help, Severity: is.Severity.String(), Fingerprint: is.Fingerprint,
+		DocumentSHA256: is.DocumentSHA256,
+	}
+}
+
+// jsonReport — документ формата json: чем проверено, какие документы
+// проверены (с sha256) и находки. Отчёт можно приложить к релизу как
+// свидетельство того, какое именно содержимое прошло проверку.
+type jsonReport struct {
+	Tool      *Tool         `json:"tool,omitempty"`
+	Documents []Document    `json:"documents"`
+	Findings  []jsonFinding `json:"findings"`
+}
+
+// JSON выводит один документ на весь прогон.
+type JSON struct {
+	w        io.Writer
+	tool     Tool
+	findings []jsonFinding
+}
+
+func NewJSON(w io.Writer) *JSON { return &JSON{w: w} }
+
+func (r *JSON) Start() error {
+	r.findings = []jsonFinding{}
+	return nil
+}
+
+func (r *JSON) Report(file string, is validator.Issue) error {
+	r.findings = append(r.findings, newJSONFinding(file, is))
+	return nil
+}
+
+func (r *JSON) Finish(s Summary) error {
+	enc := newEncoder(r.w)
+	enc.SetIndent("", "  ")
+	docs := s.Documents
+	if docs == nil {
+		docs = []Document{}
+	}
+	return enc.Encode(jsonReport{Tool: toolRef(r.tool), Documents: docs, Findings: r.findings})
+}
+
+// NDJSON пишет каждую находку отдельной JSON-строкой сразу, без буфера:
+// вывод можно читать через tail -f или отдавать в сборщик логов. Шапки
+// у потока нет, поэтому версия инструмента — в каждой строке.
+type NDJSON struct {
+	enc  *json.Encoder
+	tool *Tool
+}
+
+func NewNDJSON(w io.Writer) *NDJSON { return &NDJSON{enc: newEncoder(w)} }
+
+func (r *NDJSON) Start() error { return nil }
+
+func (r *NDJSON) Report(file string, is validator.Issue) error {
+	f := newJSONFinding(file, is)
+	f.Tool = r.tool
+	return r.enc.Encode(f)
+}
+
+func (r *NDJSON) Finish(Summary) error { return nil }
+
+func newEncoder(w io.Writer) *json.Encoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc
+}
+
+// toolRef — nil для пустого Tool: встраивающий код может его не задать.
+func toolRef(t Tool) *Tool {
+	if t == (Tool{}) {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,112 @@
+// junit.go
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// JUnit — один testcase на файл: ошибки валидации — failure, нечитаемый
+// файл — error, предупреждения и заметки — system-out. Такой отчёт
+// понимают Jenkins и GitLab без плагинов.
+type JUnit struct {
+	w      io.Writer
+	issues byFile
+}
+
+func NewJUnit(w io.Writer) *JUnit { return &JUnit{w: w} }
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// unreadable — находка о файле, который не прочитан, не разобран или не
+// проверен до конца.
+func unreadable(is validator.Issue) bool {
+	switch is.Code {
+	case validator.CodeReadFailed, validator.CodeParseFailed, validator.CodeValidationTimeout, validator.CodeValidationPanic:
+		return true
+	}
+	return false
+}
+
+func (r *JUnit) Start() error { return nil }
+
+func (r *JUnit) Report(file string, is validator.Issue) error {
+	r.issues.add(file, is)
+	return nil
+}
+
+func (r *JUnit) Finish(s Summary) error {
+	suite := junitSuite{Name: "yamlvalid"}
+	for _, name := range r.issues.files(s) {
+		tc := junitCase{Name: name, ClassName: "yamlvalid"}
+		var failed, other strings.Builder
+		count := 0
+		for _, is := range r.issues.issues[name] {
+			if unreadable(is) {
+				tc.Error = &junitFailure{Message: is.Message()}
+				continue
+			}
+			pos := name + ":"
+			if is.Line > 0 {
+				pos += fmt.Sprintf("%d", is.Line)
+			}
+			line := fmt.Sprintf("%s %s: %s\n", pos, is.Rule(), message(is))
+			if is.Severity == validator.SeverityError {
+				count++
+				failed.WriteString(line)
+			} else {
+				other.WriteString(is.Severity.String() + ": " + line)
+			}
+		}
+		if tc.Error != nil {
+			suite.Errors++
+		}
+		if count > 0 {
+			tc.Failure = &junitFailure{Message: fmt.Sprintf("%d validation errors", count), Type: "validation", Text: failed.String()}
+			suite.Failures++
+		}
+		tc.SystemOut = other.String()
+		suite.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+	return writeXML(r.w, suite)
+}
+
+// writeXML — документ с заголовком и отступами, общий для XML-форматов.
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
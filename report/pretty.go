@@ -0,0 +1,100 @@
+// pretty.go
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+)
+
+var prettyStyles = map[validator.Severity]struct{ icon, color string }{
+	validator.SeverityError:   {"✖", ansiRed},
+	validator.SeverityWarning: {"⚠", ansiYellow},
+	validator.SeverityInfo:    {"ℹ", ansiBlue},
+}
+
+// Pretty — вывод для терминала: находки сгруппированы по файлам, в конце
+// итог.
+type Pretty struct {
+	w     io.Writer
+	color bool
+	src   *sources
+	cur   string
+}
+
+func NewPretty(w io.Writer, color bool) *Pretty { return &Pretty{w: w, color: color} }
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
+
+func (r *Pretty) paint(color, s string) string {
+	if !r.color {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+func (r *Pretty) Start() error { return nil }
+
+func (r *Pretty) Report(file string, is validator.Issue) error {
+	if file != r.cur {
+		if r.cur != "" {
+			fmt.Fprintln(r.w)
+		}
+		r.cur = file
+		fmt.Fprintln(r.w, r.paint(ansiBold, file))
+	}
+	st := prettyStyles[is.Severity]
+	pos := ""
+	switch {
+	case is.Line > 0:
+		pos = fmt.Sprintf("%d:%d", is.Line, is.Column)
+	case is.Item > 0:
+		pos = fmt.Sprintf("item %d", is.Item)
+	case is.Document > 0:
+		// находка без строки во втором и следующих документах
+		pos = fmt.Sprintf("doc %d", is.Document)
+	}
+	_, err := fmt.Fprintf(r.w, "  %s %-7s %s  %s\n", r.paint(st.color, st.icon), pos, is.Message(), r.paint(ansiDim, is.Rule()))
+	if snippet := r.src.snippet(file, is.Line, is.Column, "      "); snippet != "" && err == nil {
+		_, err = fmt.Fprint(r.w, r.paint(ansiDim, snippet))
+	}
+	if is.Help != "" && err == nil {
+		_, err = fmt.Fprintf(r.w, "            %s\n", r.paint(ansiDim, "help: "+is.Help))
+	}
+	if is.Fingerprint != "" && err == nil {
+		_, err = fmt.Fprintf(r.w, "            %s\n", r.paint(ansiDim, "fingerprint: "+is.Fingerprint))
+	}
+	return err
+}
+
+func (r *Pretty) Finish(s Summary) error {
+	if r.cur != "" {
+		fmt.Fprintln(r.w)
+	}
+	summary := plural(s.Errors, "error") + ", " + plural(s.Warnings, "warning") + " in " + plural(len(s.Files), "file")
+	switch {
+	case s.Errors > 0:
+		summary = r.paint(ansiRed, "✖ "+summary)
+	case s.Warnings > 0:
+		summary = r.paint(ansiYellow, "⚠ "+summary)
+	default:
+		summary = r.paint(ansiBold, "✔ "+summary)
+	}
+	_, err := fmt.Fprintln(r.w, summary)
+	return err
+}
@@ -0,0 +1,170 @@
+// report.go
+
+// Package report — форматы вывода находок yamlvalid. Reporter получает
+// находки по одной, поэтому встраивающие программы и плагины могут
+// направить их в свой приёмник (лог, очередь, БД), не переписывая CLI.
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Reporter — приёмник находок. Порядок вызовов: Start, Report для каждой
+// находки (находки одного файла идут подряд), Finish.
+type Reporter interface {
+	Start() error
+	Report(file string, is validator.Issue) error
+	// Finish получает итог прогона; форматы, которым нужен весь документ
+	// сразу (JSON, XML), пишут его здесь.
+	Finish(s Summary) error
+}
+
+// Summary — итог прогона: все проверенные файлы в порядке проверки, в том
+// числе без находок, дайджесты их документов и число находок по важности.
+type Summary struct {
+	Files     []string
+	Documents []Document
+	Errors    int
+	Warnings  int
+	Infos     int
+}
+
+// Document — проверенный документ файла File.
+type Document struct {
+	File string `json:"file"`
+	validator.DocumentDigest
+}
+
+// Add учитывает файл, его документы и находки.
+func (s *Summary) Add(file string, res *validator.Result) {
+	s.Files = append(s.Files, file)
+	for _, d := range res.Documents {
+		s.Documents = append(s.Documents, Document{File: file, DocumentDigest: d})
+	}
+	for _, is := range res.Issues {
+		switch is.Severity {
+		case validator.SeverityError:
+			s.Errors++
+		case validator.SeverityWarning:
+			s.Warnings++
+		case validator.SeverityInfo:
+			s.Infos++
+		}
+	}
+}
+
+// Options — настройки встроенных форматов.
+type Options struct {
+	// Color — ANSI-цвета в формате pretty.
+	Color bool
+	// Source, если задан, возвращает содержимое файла: text и pretty
+	// печатают под находкой её строку с кареткой под колонкой.
+	Source func(file string) []byte
+	// Tool — версия инструмента и набора правил для json, ndjson и sarif.
+	Tool Tool
+}
+
+// Tool — чем проверено: имя и версия инструмента и отпечаток набора
+// правил (validator.RuleSetVersion).
+type Tool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	RuleSet string `json:"ruleSet"`
+}
+
+var formats = map[string]func(w io.Writer, opts Options) Reporter{
+	"text":       func(w io.Writer, opts Options) Reporter { return &Text{w: w, src: &sources{get: opts.Source}} },
+	"json":       func(w io.Writer, opts Options) Reporter { return &JSON{w: w, tool: opts.Tool} },
+	"ndjson":     func(w io.Writer, opts Options) Reporter { return &NDJSON{enc: newEncoder(w), tool: toolRef(opts.Tool)} },
+	"junit":      func(w io.Writer, _ Options) Reporter { return NewJUnit(w) },
+	"github":     func(w io.Writer, _ Options) Reporter { return NewGitHub(w) },
+	"checkstyle": func(w io.Writer, _ Options) Reporter { return NewCheckstyle(w) },
+	"sarif":      func(w io.Writer, opts Options) Reporter { return &SARIF{w: w, tool: opts.Tool} },
+	"pretty": func(w io.Writer, opts Options) Reporter {
+		return &Pretty{w: w, color: opts.Color, src: &sources{get: opts.Source}}
+	},
+}
+
+// New возвращает встроенный Reporter по имени формата.
+func New(format string, w io.Writer, opts Options) (Reporter, error) {
+	newReporter, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format '%s' (known: %s)", format, strings.Join(Formats(), ", "))
+	}
+	return newReporter(w, opts), nil
+}
+
+// Formats — имена встроенных форматов по алфавиту.
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write прогоняет через r готовые результаты: names[i] — имя файла для
+// results[i].
+func Write(r Reporter, names []string, results []*validator.Result) error {
+	if err := r.Start(); err != nil {
+		return err
+	}
+	var s Summary
+	for i, res := range results {
+		for _, is := range res.Issues {
+			if err := r.Report(names[i], is); err != nil {
+				return err
+			}
+		}
+		s.Add(names[i], res)
+	}
+	return r.Finish(s)
+}
+
+// message — сообщение с подсказкой для форматов без отдельного поля под
+// неё.
+func message(is validator.Issue) string {
+	if is.Help == "" {
+		return is.Message()
+	}
+	return is.Message() + "; " + is.Help
+}
+
+// byFile копит находки по файлам для форматов, которые пишут документ
+// целиком в Finish.
+type byFile struct {
+	order  []string
+	issues map[string][]validator.Issue
+}
+
+func (b *byFile) add(file string, is validator.Issue) {
+	if b.issues == nil {
+		b.issues = map[string][]validator.Issue{}
+	}
+	if _, ok := b.issues[file]; !ok {
+		b.order = append(b.order, file)
+	}
+	b.issues[file] = append(b.issues[file], is)
+}
+
+// files — файлы из итога, а за ними те, о которых итог не знает: Reporter
+// могут звать и без Summary.Add.
+func (b *byFile) files(s Summary) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, list := range [][]string{s.Files, b.order} {
+		for _, f := range list {
+			if !seen[f] {
+				seen[f] = true
+				out = append(out, f)
+			}
+		}
+	}
+	return out
+}
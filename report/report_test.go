@@ -0,0 +1,330 @@
+package report
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// go test ./report -update переписывает testdata/*.golden по текущему
+// выводу.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+const (
+	digestApp = "01556c1b213a2dffd4cfbb0904b27c35c3cbe925939b2780274279eac0379589"
+	digestPod = "48d12d77dc04667dcee969d31fe7c81da0e1329dd70dd04c7d549faf659d98b5"
+	digestOK  = "9b2e3f1c4f0ab0b3f6c66b8ea7bb0d6e7c8cbe8c1f1a4e3f2d1c0b9a8f7e6d5c"
+)
+
+// sampleRun — прогон на два файла: в первом находки трёх важностей в двух
+// документах, второй чистый.
+func sampleRun() ([]string, []*validator.Result) {
+	return []string{"k8s/app.yaml", "k8s/ok.yaml"}, []*validator.Result{
+		{
+			Issues: []validator.Issue{
+				{Line: 8, Column: 26, Code: "PRT003", Severity: validator.SeverityError, Args: []any{"containerPort"}, DocumentSHA256: digestApp},
+				{Line: 15, Column: 14, Code: "BPR001", Severity: validator.SeverityWarning, Args: []any{"nginx:latest"},
+					Help: "use a version tag such as nginx:1.25", Document: 2, DocumentSHA256: digestPod},
+				{Code: "FIL003", Severity: validator.SeverityInfo, Args: []any{"Service"}},
+			},
+			Documents: []validator.DocumentDigest{
+				{Document: 1, Line: 1, SHA256: digestApp},
+				{Document: 2, Line: 10, SHA256: digestPod},
+			},
+		},
+		{Documents: []validator.DocumentDigest{{Document: 1, Line: 1, SHA256: digestOK}}},
+	}
+}
+
+// sampleSource — текст k8s/app.yaml, к которому относятся находки
+// sampleRun.
+const sampleSource = `apiVersion: v1
+kind: Pod
+metadata: {name: web}
+spec:
+  containers:
+    - name: web
+      ports:
+        - containerPort: 70000
+---
+apiVersion: v1
+kind: Pod
+metadata: {name: app}
+spec:
+  containers:
+    - image: nginx:latest
+      name: app
+`
+
+var sampleTool = Tool{Name: "yamlvalid", Version: "v1.2.3", RuleSet: "sha256:0123456789abcdef"}
+
+// checkGolden сравнивает вывод формата с testdata/<format>.golden.
+func checkGolden(t *testing.T, format string, opts Options) {
+	t.Helper()
+	checkGoldenFile(t, format, format, opts)
+}
+
+// checkGoldenFile — то же для варианта формата в testdata/<name>.golden.
+func checkGoldenFile(t *testing.T, name, format string, opts Options) {
+	t.Helper()
+	var buf bytes.Buffer
+	r, err := New(format, &buf, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, results := sampleRun()
+	if err := Write(r, names, results); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("%s output differs from %s:\n%s", format, path, got)
+	}
+}
+
+func TestJSONGolden(t *testing.T) {
+	checkGolden(t, "json", Options{Tool: sampleTool})
+	checkGolden(t, "ndjson", Options{Tool: sampleTool})
+}
+
+// Встраивающий код без Tool получает отчёт без шапки инструмента.
+func TestJSONWithoutTool(t *testing.T) {
+	var buf bytes.Buffer
+	names, results := sampleRun()
+	if err := Write(NewNDJSON(&buf), names, results); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"tool"`)) {
+		t.Errorf("ndjson without Tool has tool field:\n%s", buf.String())
+	}
+	buf.Reset()
+	if err := Write(NewJSON(&buf), names, results); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"tool"`)) {
+		t.Errorf("json without Tool has tool field:\n%s", buf.String())
+	}
+}
+
+// Чистый прогон — пустые массивы, а не null: потребителю не нужно
+// проверять поля на null.
+func TestJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(NewJSON(&buf), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"documents\": [],\n  \"findings\": []\n}\n"; buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestJUnitGolden(t *testing.T) {
+	checkGolden(t, "junit", Options{})
+}
+
+// Неразобранный файл — error, а не failure: это сбой, а не вердикт.
+func TestJUnitUnreadable(t *testing.T) {
+	var buf bytes.Buffer
+	res := &validator.Result{Issues: []validator.Issue{
+		{Line: 3, Code: validator.CodeParseFailed, Severity: validator.SeverityError, Args: []any{"mapping values are not allowed here"}},
+	}}
+	if err := Write(NewJUnit(&buf), []string{"bad.yaml"}, []*validator.Result{res}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `failures="0" errors="1"`) || !strings.Contains(out, `<error message=`) {
+		t.Errorf("unreadable file is not a junit error:\n%s", out)
+	}
+}
+
+func TestGitHubGolden(t *testing.T) {
+	checkGolden(t, "github", Options{})
+}
+
+// Запятая в имени файла и перевод строки в сообщении не ломают команду.
+func TestGitHubEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	res := &validator.Result{Issues: []validator.Issue{
+		{Line: 1, Code: validator.CodeParseFailed, Severity: validator.SeverityError, Args: []any{"50% done\nat line 2"}},
+	}}
+	if err := Write(NewGitHub(&buf), []string{"a,b:c.yaml"}, []*validator.Result{res}); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=a%2Cb%3Ac.yaml,line=1,title=IO002 " + validator.RuleName(validator.CodeParseFailed) +
+		"::cannot unmarshal file content: 50%25 done%0Aat line 2\n"
+	if buf.String() != want {
+		t.Errorf("got  %q\nwant %q", buf.String(), want)
+	}
+}
+
+func TestCheckstyleGolden(t *testing.T) {
+	checkGolden(t, "checkstyle", Options{})
+}
+
+func TestSARIFGolden(t *testing.T) {
+	checkGolden(t, "sarif", Options{Tool: sampleTool})
+}
+
+func TestPrettyGolden(t *testing.T) {
+	checkGolden(t, "pretty", Options{})
+	checkGoldenFile(t, "pretty-color", "pretty", Options{Color: true})
+}
+
+func TestTextGolden(t *testing.T) {
+	checkGolden(t, "text", Options{})
+}
+
+// recorder — свой приёмник находок, как у встраивающей программы.
+type recorder struct {
+	calls   []string
+	summary Summary
+}
+
+func (r *recorder) Start() error {
+	r.calls = append(r.calls, "start")
+	return nil
+}
+
+func (r *recorder) Report(file string, is validator.Issue) error {
+	r.calls = append(r.calls, file+" "+is.Code)
+	return nil
+}
+
+func (r *recorder) Finish(s Summary) error {
+	r.calls = append(r.calls, "finish")
+	r.summary = s
+	return nil
+}
+
+func TestWriteCustomReporter(t *testing.T) {
+	var r recorder
+	names, results := sampleRun()
+	if err := Write(&r, names, results); err != nil {
+		t.Fatal(err)
+	}
+	want := "start|k8s/app.yaml PRT003|k8s/app.yaml BPR001|k8s/app.yaml FIL003|finish"
+	if got := strings.Join(r.calls, "|"); got != want {
+		t.Errorf("calls %s, want %s", got, want)
+	}
+	s := r.summary
+	if len(s.Files) != 2 || len(s.Documents) != 3 || s.Errors != 1 || s.Warnings != 1 || s.Infos != 1 {
+		t.Errorf("summary %+v", s)
+	}
+}
+
+func TestNewUnknownFormat(t *testing.T) {
+	if _, err := New("yaml", &bytes.Buffer{}, Options{}); err == nil || !strings.Contains(err.Error(), "known: checkstyle, github") {
+		t.Errorf("error %v, want list of known formats", err)
+	}
+}
+
+func TestSourceGolden(t *testing.T) {
+	source := func(file string) []byte {
+		if file == "k8s/app.yaml" {
+			return []byte(sampleSource)
+		}
+		return nil
+	}
+	checkGoldenFile(t, "text-source", "text", Options{Source: source})
+	checkGoldenFile(t, "pretty-source", "pretty", Options{Source: source})
+}
+
+// Отпечаток печатается только у находок, где его проставили.
+func TestFingerprintOutput(t *testing.T) {
+	res := &validator.Result{Issues: []validator.Issue{
+		{Line: 6, Code: "POD007", Severity: validator.SeverityError, Args: []any{"plan9"}, Fingerprint: "3fa4c1d2e9b0"},
+	}}
+	for format, want := range map[string]string{
+		"text":   "k8s/app.yaml:6 os has unsupported value 'plan9'\n    fingerprint: 3fa4c1d2e9b0\n",
+		"ndjson": `"fingerprint":"3fa4c1d2e9b0"`,
+		"pretty": "fingerprint: 3fa4c1d2e9b0\n",
+		"sarif":  `"yamlvalid/v1": "3fa4c1d2e9b0"`,
+	} {
+		var buf bytes.Buffer
+		r, err := New(format, &buf, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Write(r, []string{"k8s/app.yaml"}, []*validator.Result{res}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s: output lacks %q:\n%s", format, want, buf.String())
+		}
+	}
+}
+
+// Находка без строки из второго документа показывает его номер.
+func TestDocumentOutput(t *testing.T) {
+	res := &validator.Result{Issues: []validator.Issue{
+		{Code: "FIL003", Severity: validator.SeverityInfo, Args: []any{"Service"}, Document: 2},
+	}}
+	for format, want := range map[string]string{
+		"pretty": "  ℹ doc 2   file has no document",
+		"ndjson": `"document":2,`,
+	} {
+		var buf bytes.Buffer
+		r, err := New(format, &buf, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Write(r, []string{"k8s/app.yaml"}, []*validator.Result{res}); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s: output lacks %q:\n%s", format, want, buf.String())
+		}
+	}
+}
+
+func TestCaret(t *testing.T) {
+	tests := []struct {
+		text string
+		col  int
+		want string
+	}{
+		{"  image: nginx:latest", 10, "         ^~~~~"},
+		{"  ports: [{port: 70000}]", 18, "                 ^~~~~"},
+		{"\tname: web", 8, "\t      ^~~"},
+		{"  имя: web", 8, "       ^~~"},
+		{"key:", 9, "    ^"},
+	}
+	for _, tt := range tests {
+		if got := caret(tt.text, tt.col); got != tt.want {
+			t.Errorf("caret(%q, %d) = %q, want %q", tt.text, tt.col, got, tt.want)
+		}
+	}
+}
+
+// NDJSON пишет находку сразу, не дожидаясь Finish.
+func TestNDJSONStreams(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewNDJSON(&buf)
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	_, results := sampleRun()
+	for i, is := range results[0].Issues {
+		if err := r.Report("k8s/app.yaml", is); err != nil {
+			t.Fatal(err)
+		}
+		if got := bytes.Count(buf.Bytes(), []byte("\n")); got != i+1 {
+			t.Fatalf("after %d findings %d lines written", i+1, got)
+		}
+	}
+}
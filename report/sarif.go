@@ -0,0 +1,155 @@
+// sarif.go
+package report
+
+import (
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// SARIF — SARIF 2.1.0 для GitHub code scanning и других анализаторов. У
+// каждой находки в properties — sha256 её документа, в run.properties —
+// версия инструмента и набора правил и все проверенные документы, как в
+// формате json.
+type SARIF struct {
+	w       io.Writer
+	tool    Tool
+	rules   []sarifRule
+	seen    map[string]int
+	results []sarifResult
+}
+
+func NewSARIF(w io.Writer) *SARIF { return &SARIF{w: w} }
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool          `json:"tool"`
+	Results    []sarifResult      `json:"results"`
+	Properties sarifRunProperties `json:"properties"`
+}
+
+type sarifRunProperties struct {
+	Tool      *Tool      `json:"tool,omitempty"`
+	Documents []Document `json:"documents"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version,omitempty"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             sarifMessage       `json:"message"`
+	Locations           []sarifLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Properties          *sarifResultDetail `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifResultDetail struct {
+	Document       int    `json:"document,omitempty"`
+	Item           int    `json:"item,omitempty"`
+	DocumentSHA256 string `json:"documentSha256,omitempty"`
+}
+
+func (r *SARIF) Start() error {
+	r.rules, r.seen, r.results = []sarifRule{}, map[string]int{}, []sarifResult{}
+	return nil
+}
+
+func (r *SARIF) Report(file string, is validator.Issue) error {
+	index, ok := r.seen[is.Code]
+	if !ok {
+		index = len(r.rules)
+		r.seen[is.Code] = index
+		r.rules = append(r.rules, sarifRule{ID: is.Code, Name: validator.RuleName(is.Code)})
+	}
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: file}}}
+	if is.Line > 0 {
+		loc.PhysicalLocation.Region = &sarifRegion{StartLine: is.Line, StartColumn: is.Column}
+	}
+	res := sarifResult{
+		RuleID: is.Code, RuleIndex: index, Level: sarifLevel(is.Severity),
+		Message: sarifMessage{Text: message(is)}, Locations: []sarifLocation{loc},
+	}
+	if is.Fingerprint != "" {
+		res.PartialFingerprints = map[string]string{"yamlvalid/v1": is.Fingerprint}
+	}
+	if is.Document > 0 || is.DocumentSHA256 != "" {
+		res.Properties = &sarifResultDetail{Document: is.Document, Item: is.Item, DocumentSHA256: is.DocumentSHA256}
+	}
+	r.results = append(r.results, res)
+	return nil
+}
+
+func (r *SARIF) Finish(s Summary) error {
+	name := r.tool.Name
+	if name == "" {
+		name = "yamlvalid"
+	}
+	docs := s.Documents
+	if docs == nil {
+		docs = []Document{}
+	}
+	enc := newEncoder(r.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:       sarifTool{Driver: sarifDriver{Name: name, Version: r.tool.Version, Rules: r.rules}},
+			Results:    r.results,
+			Properties: sarifRunProperties{Tool: toolRef(r.tool), Documents: docs},
+		}},
+	})
+}
+
+// sarifLevel — уровень SARIF для важности находки.
+func sarifLevel(s validator.Severity) string {
+	switch s {
+	case validator.SeverityError:
+		return "error"
+	case validator.SeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
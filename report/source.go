@@ -0,0 +1,67 @@
+// source.go
+package report
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// sources отдаёт строки исходных файлов для фрагментов под находками;
+// разбивка на строки кешируется для текущего файла — находки одного файла
+// идут подряд.
+type sources struct {
+	get   func(file string) []byte
+	file  string
+	lines []string
+}
+
+// snippet — строка line файла и каретка под колонкой col, с отступом
+// indent; пусто, если источника нет или находка без строки.
+func (s *sources) snippet(file string, line, col int, indent string) string {
+	if s == nil || s.get == nil || line <= 0 {
+		return ""
+	}
+	if file != s.file || s.lines == nil {
+		s.file = file
+		s.lines = strings.Split(string(s.get(file)), "\n")
+	}
+	if line > len(s.lines) {
+		return ""
+	}
+	text := strings.TrimRight(s.lines[line-1], "\r")
+	gutter := fmt.Sprintf("%d", line)
+	out := fmt.Sprintf("%s%s | %s\n", indent, gutter, text)
+	if col <= 0 {
+		return out
+	}
+	return out + fmt.Sprintf("%s%s | %s\n", indent, strings.Repeat(" ", len(gutter)), caret(text, col))
+}
+
+// caret — ^ под колонкой col (в рунах, с 1) и ~ до конца токена. Табы
+// в префиксе сохраняются, чтобы каретка совпала с текстом при любой
+// ширине табуляции.
+func caret(text string, col int) string {
+	var b strings.Builder
+	i := 0
+	for n := 1; n < col && i < len(text); n++ {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+		i += size
+	}
+	b.WriteByte('^')
+	if i < len(text) {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		for _, r := range text[i+size:] {
+			if strings.ContainsRune(" \t:,]}#", r) {
+				break
+			}
+			b.WriteByte('~')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,53 @@
+// text.go
+package report
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// Text — плоские строки file:line message, по одной на находку; этот
+// формат читают автотесты, поэтому он пишет сразу и ничего не добавляет
+// в начале и в конце.
+type Text struct {
+	w   io.Writer
+	src *sources
+}
+
+func NewText(w io.Writer) *Text { return &Text{w: w} }
+
+func (r *Text) Start() error { return nil }
+
+func (r *Text) Report(file string, is validator.Issue) error {
+	msg := is.Message()
+	switch is.Severity {
+	case validator.SeverityWarning:
+		msg = "warning: " + msg
+	case validator.SeverityInfo:
+		msg = "info: " + msg
+	}
+	var err error
+	if is.Line > 0 {
+		_, err = fmt.Fprintf(r.w, "%s:%d %s\n", file, is.Line, msg)
+	} else {
+		_, err = fmt.Fprintf(r.w, "%s: %s\n", file, msg)
+	}
+	// отпечаток есть только с --feedback-file: по нему ложное
+	// срабатывание отмечают через yamlvalid suppress
+	if is.Fingerprint != "" && err == nil {
+		_, err = fmt.Fprintf(r.w, "    fingerprint: %s\n", is.Fingerprint)
+	}
+	// подсказки — только вместе с фрагментом исходника (--show-source):
+	// без него строки остаются такими, как их ждут автотесты
+	if snippet := r.src.snippet(file, is.Line, is.Column, "    "); snippet != "" && err == nil {
+		_, err = fmt.Fprint(r.w, snippet)
+		if is.Help != "" && err == nil {
+			_, err = fmt.Fprintf(r.w, "    help: %s\n", is.Help)
+		}
+	}
+	return err
+}
+
+func (r *Text) Finish(Summary) error { return nil }
@@ -0,0 +1,90 @@
+// showdefaults.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	yaml "gopkg.in/yaml.v3"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// showDefaults печатает для --show-defaults документы файлов такими,
+// какими их создаст кластер: с подставленными значениями по умолчанию,
+// помеченными комментарием # default. Проверки не выполняются; код
+// выхода 2, если какой-то файл не прочитался или не разобрался.
+func showDefaults(v *validator.Validator, paths []string, stdinName string, stdout, stderr io.Writer) int {
+	return printDocuments(v.Defaulted, paths, stdinName, stdout, stderr)
+}
+
+// Подкоманда render-pod печатает поды, которые создадут контроллеры,
+// отдельными манифестами Pod со значениями по умолчанию: чтобы увидеть
+// итоговый под и передать его инструментам, которые понимают только Pod.
+//
+//	yamlvalid render-pod deployment.yaml | kubectl apply --dry-run=server -f -
+func runRenderPod(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render-pod", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: yamlvalid render-pod [file | -]...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{stdinArg}
+	}
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 2
+	}
+	return printDocuments(v.RenderPods, paths, defaultStdinName, stdout, stderr)
+}
+
+// printDocuments печатает документы, которые render строит по каждому
+// файлу, через ---, с комментарием об исходном файле.
+func printDocuments(render func([]byte) ([]*yaml.Node, error), paths []string, stdinName string, stdout, stderr io.Writer) int {
+	exitCode := 0
+	first := true
+	for _, path := range paths {
+		if isTerraformFile(path) {
+			continue
+		}
+		name := path
+		if path == stdinArg {
+			name = stdinName
+		}
+		data, err := readInput(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot read file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		docs, err := render(data)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: cannot unmarshal file content: %v\n", name, err)
+			exitCode = 2
+			continue
+		}
+		for _, doc := range docs {
+			if !first {
+				io.WriteString(stdout, "---\n")
+			}
+			first = false
+			fmt.Fprintf(stdout, "# Source: %s\n", name)
+			enc := yaml.NewEncoder(stdout)
+			enc.SetIndent(2)
+			if err := enc.Encode(doc); err != nil {
+				fmt.Fprintf(stderr, "%s: cannot write manifests: %v\n", name, err)
+				return 2
+			}
+			enc.Close()
+		}
+	}
+	return exitCode
+}
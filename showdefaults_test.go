@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPod(t *testing.T) {
+	dir := t.TempDir()
+	deploy := filepath.Join(dir, "deploy.yaml")
+	data := `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web, namespace: prod}
+spec:
+  template:
+    metadata: {labels: {app: web}}
+    spec:
+      containers:
+        - name: web
+          image: nginx:1.25
+---
+apiVersion: v1
+kind: Service
+metadata: {name: web}
+`
+	if err := os.WriteFile(deploy, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := runRenderPod([]string{deploy}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit %d, stderr %q", code, stderr.String())
+	}
+	got := stdout.String()
+	for _, want := range []string{"# Source: " + deploy + "\n", "kind: Pod\n", "  name: web\n  namespace: prod\n", "restartPolicy: Always # default"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "---") || strings.Contains(got, "Service") {
+		t.Errorf("more than one pod:\n%s", got)
+	}
+
+	if code := runRenderPod([]string{filepath.Join(dir, "missing.yaml")}, &stdout, &stderr); code != 2 {
+		t.Errorf("missing file: exit %d, want 2", code)
+	}
+}
@@ -0,0 +1,217 @@
+// terraform.go
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Манифесты внутри Terraform: kubernetes_manifest.manifest — объект HCL
+// или yamldecode(<<EOT ... EOT), kubectl_manifest.yaml_body — heredoc.
+// Из них строятся те же yaml.Node, но строки указывают на .tf-файл.
+// Манифесты из file()/templatefile() пропускаем: такие файлы
+// проверяются сами по себе.
+var terraformManifestAttrs = map[string]string{
+	"kubernetes_manifest": "manifest",
+	"kubectl_manifest":    "yaml_body",
+}
+
+func isTerraformFile(path string) bool { return strings.EqualFold(filepath.Ext(path), ".tf") }
+
+// validateTerraform проверяет все манифесты .tf-файла одним набором, чтобы
+// файловые правила (documents) видели их вместе.
+func validateTerraform(v *validator.Validator, name string, src []byte) (*validator.Result, error) {
+	file, diags := hclsyntax.ParseConfig(src, name, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	var docs []*yaml.Node
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		if block.Type != "resource" || len(block.Labels) != 2 {
+			continue
+		}
+		attrName, ok := terraformManifestAttrs[block.Labels[0]]
+		if !ok {
+			continue
+		}
+		attr, ok := block.Body.Attributes[attrName]
+		if !ok {
+			continue
+		}
+		found, err := manifestDocs(attr.Expr, src)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", block.Labels[0], block.Labels[1], err)
+		}
+		docs = append(docs, found...)
+	}
+	if len(docs) == 0 {
+		return &validator.Result{}, nil // в файле нет Kubernetes-ресурсов
+	}
+	return v.ValidateDocuments("", docs), nil
+}
+
+func manifestDocs(expr hclsyntax.Expression, src []byte) ([]*yaml.Node, error) {
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		return []*yaml.Node{hclNode(e, src)}, nil
+	case *hclsyntax.FunctionCallExpr:
+		if e.Name == "yamldecode" && len(e.Args) == 1 {
+			return manifestDocs(e.Args[0], src)
+		}
+	case *hclsyntax.TemplateExpr:
+		return embeddedYAML(e, src)
+	}
+	return nil, nil
+}
+
+// embeddedYAML разбирает YAML из строки или heredoc и сдвигает строки узлов
+// на позицию текста в .tf-файле.
+func embeddedYAML(e *hclsyntax.TemplateExpr, src []byte) ([]*yaml.Node, error) {
+	rng := e.Range()
+	raw := string(rng.SliceBytes(src))
+	heredoc := strings.HasPrefix(raw, "<<")
+	firstLine := rng.Start.Line
+	if heredoc {
+		firstLine++ // текст начинается со строки после <<EOT
+	}
+
+	var text string
+	if val, diags := e.Value(nil); !diags.HasErrors() && val.IsWhollyKnown() && val.Type() == cty.String {
+		text = val.AsString()
+	} else {
+		// есть интерполяции: берём исходный текст, ${...} останутся строками
+		text = raw
+		if heredoc {
+			lines := strings.Split(raw, "\n")
+			text = dedent(lines[1 : len(lines)-1])
+		} else {
+			text = strings.Trim(text, `"`)
+		}
+	}
+
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(strings.NewReader(text))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, err
+		}
+		if len(doc.Content) > 0 {
+			shiftLines(doc.Content[0], firstLine-1)
+			docs = append(docs, doc.Content[0])
+		}
+	}
+}
+
+// dedent убирает общий отступ строк, как <<- в Terraform.
+func dedent(lines []string) string {
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// shiftLines не заходит в Alias: якорь уже есть в дереве и сдвинут там.
+func shiftLines(n *yaml.Node, delta int) {
+	if n.Line > 0 {
+		n.Line += delta
+	}
+	for _, c := range n.Content {
+		shiftLines(c, delta)
+	}
+}
+
+// hclNode строит узел из выражения HCL. Значения, которые известны только
+// при plan (var.image, ссылки на ресурсы), попадают в проверку текстом
+// выражения.
+func hclNode(expr hclsyntax.Expression, src []byte) *yaml.Node {
+	line, col := expr.Range().Start.Line, expr.Range().Start.Column
+	switch e := expr.(type) {
+	case *hclsyntax.ObjectConsExpr:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: col}
+		for _, item := range e.Items {
+			key := string(item.KeyExpr.Range().SliceBytes(src))
+			if val, diags := item.KeyExpr.Value(nil); !diags.HasErrors() && val.IsKnown() && val.Type() == cty.String {
+				key = val.AsString()
+			}
+			start := item.KeyExpr.Range().Start
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, Line: start.Line, Column: start.Column},
+				hclNode(item.ValueExpr, src))
+		}
+		return n
+	case *hclsyntax.TupleConsExpr:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line, Column: col}
+		for _, ex := range e.Exprs {
+			n.Content = append(n.Content, hclNode(ex, src))
+		}
+		return n
+	}
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() || !val.IsWhollyKnown() {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: string(expr.Range().SliceBytes(src)), Line: line, Column: col}
+	}
+	return ctyNode(val, line, col)
+}
+
+func ctyNode(val cty.Value, line, col int) *yaml.Node {
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Line: line, Column: col}
+	}
+	switch {
+	case val.IsNull():
+		return scalar("!!null", "null")
+	case val.Type() == cty.String:
+		return scalar("!!str", val.AsString())
+	case val.Type() == cty.Bool:
+		if val.True() {
+			return scalar("!!bool", "true")
+		}
+		return scalar("!!bool", "false")
+	case val.Type() == cty.Number:
+		bf := val.AsBigFloat()
+		if bf.IsInt() {
+			i, _ := bf.Int(nil)
+			return scalar("!!int", i.String())
+		}
+		return scalar("!!float", bf.Text('g', -1))
+	case val.Type().IsObjectType() || val.Type().IsMapType():
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: line, Column: col}
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			n.Content = append(n.Content, scalar("!!str", k.AsString()), ctyNode(v, line, col))
+		}
+		return n
+	case val.CanIterateElements():
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: line, Column: col}
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			n.Content = append(n.Content, ctyNode(v, line, col))
+		}
+		return n
+	}
+	return scalar("!!str", val.GoString())
+}
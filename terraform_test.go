@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+const terraformPods = `variable "image" {}
+
+resource "kubernetes_manifest" "web" {
+  manifest = {
+    apiVersion = "v1"
+    kind       = "Pod"
+    metadata   = { name = "web" }
+    spec = {
+      containers = [{
+        name  = "web"
+        image = var.image
+        resources = {
+          requests = { cpu = 1, memory = "64Mi" }
+          limits   = { cpu = 1, memory = "64Mi" }
+        }
+      }]
+    }
+  }
+}
+
+resource "kubectl_manifest" "db" {
+  yaml_body = <<-EOT
+    apiVersion: v1
+    kind: Pod
+    metadata:
+      name: ${var.name}
+    spec:
+      os: {name: plan9}
+      containers:
+        - name: db
+          image: registry.bigbrother.io/db:1.0
+          resources:
+            requests: {cpu: 1, memory: 64Mi}
+            limits: {cpu: 1, memory: 64Mi}
+  EOT
+}
+
+resource "null_resource" "other" {}
+`
+
+// Находки указывают на строки .tf-файла; значения, известные только при
+// plan, проверяются текстом выражения.
+func TestValidateTerraform(t *testing.T) {
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := validateTerraform(v, "main.tf", []byte(terraformPods))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, is := range res.Issues {
+		got = append(got, fmt.Sprintf("%d %s", is.Line, is.Message()))
+	}
+	want := []string{
+		"11 image has invalid format 'var.image'",
+		"28 os has unsupported value 'plan9'",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateTerraformEmpty(t *testing.T) {
+	v, err := validator.New(validator.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// без Kubernetes-ресурсов — ни находок, ни заметки о пустом файле
+	res, err := validateTerraform(v, "vars.tf", []byte("variable \"image\" {}\n"))
+	if err != nil || len(res.Issues) != 0 {
+		t.Errorf("issues %v, err %v; want none", res, err)
+	}
+	if _, err := validateTerraform(v, "bad.tf", []byte("resource {")); err == nil {
+		t.Error("HCL syntax error not reported")
+	}
+}
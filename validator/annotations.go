@@ -0,0 +1,145 @@
+// annotations.go
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- annotation rules ----------
+
+// AnnotationRule — правило для аннотации из конфига: обязательность,
+// формат значения и ссылки на другие объекты набора.
+type AnnotationRule struct {
+	Key      string `yaml:"key"`
+	Required bool   `yaml:"required"`
+	// Pattern — регулярное выражение для всего значения.
+	Pattern string `yaml:"pattern"`
+	// References: значение — список Kind/name или Kind/namespace/name через
+	// запятую; каждый объект должен быть в проверяемом наборе файлов.
+	References bool `yaml:"references"`
+	// Severity — error (по умолчанию), warning или info.
+	Severity string `yaml:"severity"`
+}
+
+type annotationRule struct {
+	AnnotationRule
+	re  *regexp.Regexp
+	sev Severity
+}
+
+func compileAnnotationRules(cfg []AnnotationRule) ([]annotationRule, error) {
+	out := make([]annotationRule, 0, len(cfg))
+	for _, r := range cfg {
+		if r.Key == "" {
+			return nil, fmt.Errorf("rule without key")
+		}
+		ar := annotationRule{AnnotationRule: r}
+		var err error
+		if r.Pattern != "" {
+			if ar.re, err = regexp.Compile("^(?:" + r.Pattern + ")$"); err != nil {
+				return nil, fmt.Errorf("%s: %v", r.Key, err)
+			}
+		}
+		if r.Severity != "" {
+			if ar.sev, err = ParseSeverity(r.Severity); err != nil {
+				return nil, fmt.Errorf("%s: %v", r.Key, err)
+			}
+		}
+		out = append(out, ar)
+	}
+	return out, nil
+}
+
+// ObjectRef — объект в наборе: по таким ссылкам разрешаются References.
+type ObjectRef struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+func (o ObjectRef) String() string { return o.Kind + "/" + o.Namespace + "/" + o.Name }
+
+// Reference — ссылка из аннотации; существование цели проверяет CLI по
+// всему набору (CodeReferenceMissing).
+type Reference struct {
+	Line       int       `json:"line"`
+	Column     int       `json:"column"`
+	Annotation string    `json:"annotation"`
+	Target     ObjectRef `json:"target"`
+	Severity   Severity  `json:"severity"`
+}
+
+// collectObject запоминает объект документа; вызывается до проверки kind,
+// чтобы Service и Deployment, которых мы не валидируем, тоже находились.
+func collectObject(doc *yaml.Node, bag *errBag) {
+	kind, _ := child(doc, "kind")
+	meta, _ := child(doc, "metadata")
+	if kind == nil || meta == nil || !isScalarString(kind) {
+		return
+	}
+	if name, _ := child(meta, "name"); name != nil && isScalarString(name) {
+		bag.objects = append(bag.objects, ObjectRef{Kind: kind.Value, Namespace: namespaceOf(doc), Name: name.Value})
+	}
+}
+
+func validateAnnotationRules(ctx *ValidationContext, bag *errBag) {
+	rules := ctx.rules.annotations
+	if len(rules) == 0 {
+		return
+	}
+	doc := ctx.Doc
+	meta, _ := child(doc, "metadata")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		return
+	}
+	ann, _ := child(meta, "annotations")
+	for _, r := range rules {
+		var v *yaml.Node
+		if ann != nil {
+			v, _ = child(ann, r.Key)
+		}
+		if v == nil {
+			if r.Required {
+				bag.report(r.sev, nil, codeAnnotationRequired, r.Key)
+			}
+			continue
+		}
+		if !isScalarString(v) {
+			continue // типы значений аннотаций проверяет validateObjectMeta
+		}
+		if r.re != nil && !r.re.MatchString(v.Value) {
+			bag.report(r.sev, v, codeAnnotationFormat, r.Key, v.Value)
+			continue
+		}
+		if r.References {
+			collectReferences(doc, r, v, bag)
+		}
+	}
+}
+
+func collectReferences(doc *yaml.Node, r annotationRule, v *yaml.Node, bag *errBag) {
+	for _, item := range strings.Split(v.Value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, "/")
+		var target ObjectRef
+		switch len(parts) {
+		case 2:
+			target = ObjectRef{Kind: parts[0], Namespace: namespaceOf(doc), Name: parts[1]}
+		case 3:
+			target = ObjectRef{Kind: parts[0], Namespace: parts[1], Name: parts[2]}
+		default:
+			bag.report(r.sev, v, codeAnnotationReference, r.Key, item)
+			continue
+		}
+		bag.references = append(bag.references, Reference{
+			Line: v.Line, Column: v.Column, Annotation: r.Key, Target: target, Severity: r.sev,
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// annotated — под с аннотациями annotations в metadata.
+func annotated(annotations string) string {
+	return strings.Replace(pod("", ""), "  name: web\n", "  name: web\n  annotations:\n"+annotations, 1)
+}
+
+func TestAnnotationRules(t *testing.T) {
+	cfg := Config{AnnotationRules: []AnnotationRule{
+		{Key: "team.io/owner", Required: true, Pattern: "[a-z]+@example\\.com"},
+		{Key: "team.io/depends-on", References: true},
+	}}
+	owner := "    team.io/owner: ops@example.com\n"
+	checkRules(t, cfg, []ruleCase{
+		{"valid", annotated(owner), ""},
+		{"missing", pod("", ""), codeAnnotationRequired},
+		{"format", annotated("    team.io/owner: ops@example.org\n"), codeAnnotationFormat},
+		{"references", annotated(owner + "    team.io/depends-on: Service/db, ConfigMap/shop/app\n"), ""},
+		{"bad reference", annotated(owner + "    team.io/depends-on: Service/shop/db/extra\n"), codeAnnotationReference},
+	})
+}
+
+func TestAnnotationReferences(t *testing.T) {
+	v, err := New(Config{AnnotationRules: []AnnotationRule{{Key: "team.io/depends-on", References: true, Severity: "warning"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Validate([]byte(annotated("    team.io/depends-on: Service/db, ConfigMap/shop/app\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ObjectRef{{"Service", "default", "db"}, {"ConfigMap", "shop", "app"}}
+	if len(res.References) != len(want) {
+		t.Fatalf("references %+v, want %+v", res.References, want)
+	}
+	for i, ref := range res.References {
+		if ref.Target != want[i] || ref.Severity != SeverityWarning {
+			t.Errorf("reference %d: %+v, want target %v with warning", i, ref, want[i])
+		}
+	}
+	if len(res.Objects) != 1 || res.Objects[0] != (ObjectRef{"Pod", "default", "web"}) {
+		t.Errorf("objects %+v, want Pod/default/web", res.Objects)
+	}
+}
+
+func TestAnnotationRulesConfig(t *testing.T) {
+	for name, rules := range map[string][]AnnotationRule{
+		"no key":   {{Required: true}},
+		"pattern":  {{Key: "a", Pattern: "("}},
+		"severity": {{Key: "a", Severity: "fatal"}},
+	} {
+		if _, err := New(Config{AnnotationRules: rules}); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
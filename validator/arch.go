@@ -0,0 +1,107 @@
+// arch.go
+package validator
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- image architectures ----------
+
+// archAnnotationPrefix — аннотация пода image-arch.yamlvalid.io/<контейнер>
+// со списком архитектур через запятую; важнее записи в конфиге.
+const archAnnotationPrefix = "image-arch.yamlvalid.io/"
+
+// archSelectorKeys — метки узлов с архитектурой, старая и новая.
+var archSelectorKeys = []string{"kubernetes.io/arch", "beta.kubernetes.io/arch"}
+
+// ImageArchPolicy — известные архитектуры образов. Ключ Images — образ без
+// тега и digest'а; ключ с / на конце покрывает все образы под ним.
+type ImageArchPolicy struct {
+	// Require: образ без объявленных архитектур — предупреждение.
+	Require bool                `yaml:"require"`
+	Images  map[string][]string `yaml:"images"`
+}
+
+// archsFor ищет самое длинное совпадение: точный образ, затем префиксы.
+func (p *ImageArchPolicy) archsFor(repo string) ([]string, bool) {
+	if archs, ok := p.Images[repo]; ok {
+		return archs, true
+	}
+	best := ""
+	for key := range p.Images {
+		if strings.HasSuffix(key, "/") && strings.HasPrefix(repo, key) && len(key) > len(best) {
+			best = key
+		}
+	}
+	if best == "" {
+		return nil, false
+	}
+	return p.Images[best], true
+}
+
+// imageRepository отрезает от ссылки тег и digest.
+func imageRepository(ref string) string {
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndexByte(ref, ':'); i > strings.LastIndexByte(ref, '/') {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// validateImageArch ловит частую ошибку выката на arm64: под с
+// nodeSelector по архитектуре ссылается на образ, собранный только под
+// amd64.
+func validateImageArch(ctx *ValidationContext, bag *errBag) {
+	policy := ctx.rules.imageArch
+	spec := ctx.PodSpec
+	if !policy.Require && len(policy.Images) == 0 || spec == nil {
+		return
+	}
+	var annotations *yaml.Node
+	if meta, _ := child(ctx.Doc, "metadata"); meta != nil {
+		annotations, _ = child(meta, "annotations")
+	}
+	var wantArch string
+	if sel, _ := child(spec, "nodeSelector"); sel != nil {
+		for _, key := range archSelectorKeys {
+			if v, ok := child(sel, key); ok && isScalarString(v) {
+				wantArch = v.Value
+				break
+			}
+		}
+	}
+
+	for _, c := range ctx.Containers {
+		img, _ := child(c.Node, "image")
+		if img == nil || !isScalarString(img) {
+			continue // формат образа проверяет validateContainer
+		}
+		archs, known := policy.archsFor(imageRepository(img.Value))
+		if c.Name != "" && annotations != nil {
+			if a, ok := child(annotations, archAnnotationPrefix+c.Name); ok && isScalarString(a) {
+				archs, known = strings.Split(a.Value, ","), true
+			}
+		}
+		switch {
+		case !known:
+			if policy.Require {
+				bag.warn(img, codeImageArchUnknown, img.Value)
+			}
+		case wantArch != "" && !containsArch(archs, wantArch):
+			bag.warn(img, codeImageArchMismatch, img.Value, wantArch)
+		}
+	}
+}
+
+func containsArch(archs []string, want string) bool {
+	for _, a := range archs {
+		if strings.TrimSpace(a) == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImageArch(t *testing.T) {
+	arm := "  nodeSelector: {kubernetes.io/arch: arm64}\n"
+	annotated := func(archs string) string {
+		return strings.Replace(pod("", arm), "  name: web\n",
+			"  name: web\n  annotations:\n    image-arch.yamlvalid.io/web: "+archs+"\n", 1)
+	}
+	cfg := Config{ImageArch: ImageArchPolicy{Require: true, Images: map[string][]string{
+		"registry.bigbrother.io/":    {"amd64"},
+		"registry.bigbrother.io/web": {"amd64", "arm64"},
+	}}}
+	checkRules(t, cfg, []ruleCase{
+		{"no selector", pod("", ""), ""},
+		{"exact image", pod("", arm), ""},
+		{"old selector key", pod("", "  nodeSelector: {beta.kubernetes.io/arch: s390x}\n"), codeImageArchMismatch},
+		{"annotation wins", annotated("amd64"), codeImageArchMismatch},
+		{"annotation list", annotated("amd64, arm64"), ""},
+	})
+	prefix := Config{ImageArch: ImageArchPolicy{Require: true, Images: map[string][]string{"registry.bigbrother.io/": {"amd64"}}}}
+	checkRules(t, prefix, []ruleCase{{"prefix", pod("", arm), codeImageArchMismatch}})
+	checkRules(t, Config{ImageArch: ImageArchPolicy{Require: true}}, []ruleCase{
+		{"unknown", pod("", ""), codeImageArchUnknown},
+		{"annotation declares", annotated("arm64"), ""},
+	})
+	// без Require незнакомый образ не предупреждение
+	checkRules(t, Config{ImageArch: ImageArchPolicy{Images: map[string][]string{"other/": {"amd64"}}}}, []ruleCase{
+		{"unknown not required", pod("", arm), ""},
+	})
+}
+
+func TestImageRepository(t *testing.T) {
+	for ref, want := range map[string]string{
+		"registry.bigbrother.io/web:1.0":         "registry.bigbrother.io/web",
+		"registry.bigbrother.io:5000/web":        "registry.bigbrother.io:5000/web",
+		"registry.bigbrother.io/web@sha256:abcd": "registry.bigbrother.io/web",
+	} {
+		if got := imageRepository(ref); got != want {
+			t.Errorf("imageRepository(%q) = %q, want %q", ref, got, want)
+		}
+	}
+}
@@ -0,0 +1,445 @@
+// config.go
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Config — настройки правил. Формат совпадает с .yamlvalid.yaml, который
+// читает CLI.
+type Config struct {
+	// Enums дополняет встроенные списки допустимых значений,
+	// например enums: {protocol: [SCTP]}.
+	Enums map[string][]string `yaml:"enums"`
+
+	// FeatureGates включает проверки для опциональных возможностей
+	// кластера, например featureGates: {sctp: true}.
+	FeatureGates map[string]bool `yaml:"featureGates"`
+
+	// RulePacks — опциональные наборы правил, например [recommended-labels].
+	RulePacks []string `yaml:"rulePacks"`
+
+	// NonManifest — важность заметки о пропущенных бинарных файлах и
+	// YAML без apiVersion/kind: info (по умолчанию), warning или error.
+	// Пропускаются только файлы из обхода каталога (ValidateScanned).
+	NonManifest string `yaml:"nonManifest"`
+
+	// EmptyDocument — как сообщать о пустых файлах, файлах из одних
+	// комментариев и пустых документах (---): info (по умолчанию),
+	// warning, error или off.
+	EmptyDocument string `yaml:"emptyDocument"`
+
+	// Documents — ожидания к файлу целиком, например ровно один документ
+	// или обязательные Deployment и Service.
+	Documents DocumentPolicy `yaml:"documents"`
+
+	// FileName — шаблон имени файла для GitOps-раскладки, например
+	// {name}.yaml или {name}-{kind}.yaml; подстановки: {name},
+	// {namespace}, {kind}. Пустая строка выключает правило.
+	FileName string `yaml:"fileName"`
+
+	// Layout — шаблоны каталогов, в которых могут лежать манифесты,
+	// например k8s/*/{namespace}: сегменты как в path.Match, ** — любая
+	// глубина, подстановки как в FileName. Файл подходит, если его
+	// каталог заканчивается на один из шаблонов.
+	Layout []string `yaml:"layout"`
+
+	// Images — разрешённые реестры, репозитории и формат тега, например
+	// images: {registries: [ghcr.io, registry.bigbrother.io]}.
+	Images ImagePolicy `yaml:"images"`
+
+	// ContainerNames — соглашение об именах контейнеров.
+	ContainerNames NamePolicy `yaml:"containerNames"`
+
+	// ImageArch — архитектуры образов для проверки nodeSelector
+	// kubernetes.io/arch, например images: {registry.bigbrother.io/legacy: [amd64]}.
+	ImageArch ImageArchPolicy `yaml:"imageArch"`
+
+	// Cluster — инвентарь пулов узлов для проверки tolerations и
+	// nodeSelector и классов хранилища для проверки PVC; CLI может
+	// подгрузить его из файла.
+	Cluster ClusterInventory `yaml:"cluster"`
+
+	// PriorityClasses — PriorityClass'ы кластера; если список задан,
+	// неизвестный priorityClassName — ошибка. Встроенные system-* известны
+	// всегда.
+	PriorityClasses []string `yaml:"priorityClasses"`
+
+	// AnnotationRules — правила для аннотаций команды, например
+	// ссылки depends-on на другие объекты набора.
+	AnnotationRules []AnnotationRule `yaml:"annotationRules"`
+
+	// Rules настраивает отдельные проверки по коду или имени: off или
+	// важность, например rules: {LIM001: off, image-floating-tag: error}.
+	Rules map[string]string `yaml:"rules"`
+
+	// Thresholds — сколько находок правила допустимо на весь набор
+	// файлов: код, имя проверки или маска кодов, например
+	// thresholds: {IMG*: 5, LIM001: 0}. Сверх порога прогон падает.
+	Thresholds map[string]int `yaml:"thresholds"`
+
+	// Preset — набор групп проверок: minimal (только структура),
+	// standard или strict (всё, включая стиль и советы). Пусто — все
+	// проверки, как раньше.
+	Preset string `yaml:"preset"`
+
+	// Include и Exclude — маски путей для файлов, найденных в каталогах
+	// и по маскам из аргументов; ядро их не использует, их читает CLI.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
+
+	// Selector — селектор меток как у kubectl -l, например
+	// app=web,tier!=cache: документы, которые ему не подходят, не
+	// проверяются.
+	Selector string `yaml:"selector"`
+
+	// Kinds, Namespaces и Names — ещё фильтры документов: kind,
+	// metadata.namespace (default, если не задан) и metadata.name по
+	// маскам path.Match, например Deployment, prod-* и web-*.
+	Kinds      []string `yaml:"kinds"`
+	Namespaces []string `yaml:"namespaces"`
+	Names      []string `yaml:"names"`
+
+	// SkipUnknownKinds: документы неподдерживаемых kind'ов пропускаются с
+	// предупреждением, а не валят прогон.
+	SkipUnknownKinds bool `yaml:"skipUnknownKinds"`
+
+	// Engine — разборщик YAML: yaml.v3 или собранный под build-тегом,
+	// например goccy. Пусто — разборщик сборки по умолчанию.
+	Engine string `yaml:"engine"`
+}
+
+// DocumentPolicy — ограничения на число документов в файле и на kind'ы,
+// которые должны в нём встретиться. Нули означают «без ограничения»,
+// пустые документы не считаются.
+type DocumentPolicy struct {
+	Min          int      `yaml:"min"`
+	Max          int      `yaml:"max"`
+	RequireKinds []string `yaml:"requireKinds"`
+}
+
+// rules — Config, скомпилированный для быстрых проверок.
+type rules struct {
+	enums            map[string]enumSet
+	packs            []string
+	nonManifest      Severity
+	emptyDocument    Severity
+	emptyDocumentOff bool
+	documents        DocumentPolicy
+	fileName         string
+	layout           [][]string
+	layoutSrc        []string
+	imageArch        ImageArchPolicy
+	cluster          ClusterInventory
+	priorityClasses  map[string]bool
+	annotations      []annotationRule
+	settings         map[string]ruleSetting
+	images           *imagePolicy
+	containerNames   namePolicy
+	filter           documentFilter
+	skipUnknownKinds bool
+	engine           Engine
+	thresholds       []threshold
+	// kindAPIVersions — допустимые apiVersion для каждого известного kind
+	kindAPIVersions map[string]enumSet
+}
+
+// ruleSetting — настройка проверки из Config.Rules.
+type ruleSetting struct {
+	off bool
+	sev Severity
+}
+
+// compileRules сливает пользовательские списки со встроенными; вызывается
+// один раз в New, а не на каждый документ.
+func compileRules(cfg Config) (*rules, error) {
+	gates, err := compileGates(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := &rules{
+		nonManifest:      SeverityInfo,
+		emptyDocument:    SeverityInfo,
+		documents:        cfg.Documents,
+		fileName:         cfg.FileName,
+		layoutSrc:        cfg.Layout,
+		imageArch:        cfg.ImageArch,
+		cluster:          cfg.Cluster,
+		skipUnknownKinds: cfg.SkipUnknownKinds,
+	}
+	if r.enums, err = compileEnums(cfg, gates); err != nil {
+		return nil, err
+	}
+	r.kindAPIVersions = make(map[string]enumSet, len(supportedKinds))
+	for kind, apiVersion := range supportedKinds {
+		set := enumSet{apiVersion: {}}
+		for _, v := range cfg.Enums["apiVersion"] {
+			set[v] = struct{}{}
+		}
+		r.kindAPIVersions[kind] = set
+	}
+	if r.packs, err = compilePacks(cfg); err != nil {
+		return nil, err
+	}
+	if cfg.NonManifest != "" {
+		if r.nonManifest, err = ParseSeverity(cfg.NonManifest); err != nil {
+			return nil, fmt.Errorf("nonManifest: %v", err)
+		}
+	}
+	switch cfg.EmptyDocument {
+	case "":
+	case "off":
+		r.emptyDocumentOff = true
+	default:
+		if r.emptyDocument, err = ParseSeverity(cfg.EmptyDocument); err != nil {
+			return nil, fmt.Errorf("emptyDocument: %v", err)
+		}
+	}
+	if d := cfg.Documents; d.Min < 0 || d.Max < 0 || (d.Max > 0 && d.Min > d.Max) {
+		return nil, fmt.Errorf("documents: invalid range min %d, max %d", d.Min, d.Max)
+	}
+	if err := compileTemplate(cfg.FileName); err != nil {
+		return nil, fmt.Errorf("fileName: %v", err)
+	}
+	if err := checkStorageClasses(cfg.Cluster.StorageClasses); err != nil {
+		return nil, fmt.Errorf("cluster: %v", err)
+	}
+	if len(cfg.PriorityClasses) > 0 {
+		r.priorityClasses = map[string]bool{}
+		for _, name := range append(cfg.PriorityClasses, builtinPriorityClasses...) {
+			r.priorityClasses[name] = true
+		}
+	}
+	if r.annotations, err = compileAnnotationRules(cfg.AnnotationRules); err != nil {
+		return nil, fmt.Errorf("annotationRules: %v", err)
+	}
+	if r.layout, err = compileLayout(cfg.Layout); err != nil {
+		return nil, fmt.Errorf("layout: %v", err)
+	}
+	if r.containerNames, err = compileNamePolicy(cfg.ContainerNames); err != nil {
+		return nil, fmt.Errorf("containerNames: %v", err)
+	}
+	if r.images, err = compileImagePolicy(cfg.Images); err != nil {
+		return nil, fmt.Errorf("images: %v", err)
+	}
+	if r.settings, err = compileRuleSettings(cfg.Rules); err != nil {
+		return nil, fmt.Errorf("rules: %v", err)
+	}
+	if err := compilePreset(cfg.Preset, r.settings); err != nil {
+		return nil, fmt.Errorf("preset: %v", err)
+	}
+	if r.filter, err = compileFilter(cfg); err != nil {
+		return nil, err
+	}
+	if r.engine, err = compileEngine(cfg.Engine); err != nil {
+		return nil, fmt.Errorf("engine: %v", err)
+	}
+	if r.thresholds, err = compileThresholds(cfg.Thresholds); err != nil {
+		return nil, fmt.Errorf("thresholds: %v", err)
+	}
+	return r, nil
+}
+
+// compileRuleSettings переводит имена проверок в коды.
+func compileRuleSettings(in map[string]string) (map[string]ruleSetting, error) {
+	byName := make(map[string]string, len(ruleNames))
+	for code, name := range ruleNames {
+		byName[name] = code
+	}
+	out := make(map[string]ruleSetting, len(in))
+	for key, val := range in {
+		code := key
+		if c, ok := byName[key]; ok {
+			code = c
+		} else if _, ok := ruleNames[key]; !ok {
+			return nil, fmt.Errorf("unknown rule '%s'", key)
+		}
+		if val == "off" {
+			out[code] = ruleSetting{off: true}
+			continue
+		}
+		sev, err := ParseSeverity(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		out[code] = ruleSetting{sev: sev}
+	}
+	return out, nil
+}
+
+// ---------- container names ----------
+
+// NamePolicy — стиль имён: Style — один из nameStyles, Pattern — своё
+// регулярное выражение для имени целиком. Задаётся что-то одно; по
+// умолчанию snake_case.
+type NamePolicy struct {
+	Style   string `yaml:"style"`
+	Pattern string `yaml:"pattern"`
+}
+
+var nameStyles = map[string]*regexp.Regexp{
+	"snake_case": regexp.MustCompile(`^[a-z0-9]+(?:_[a-z0-9]+)*$`),
+	"kebab-case": regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`),
+	// DNS-1123 label — то, что требует сам Kubernetes
+	"dns-1123": regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`),
+}
+
+type namePolicy struct {
+	re   *regexp.Regexp
+	desc string // для подсказки: имя стиля или выражение
+}
+
+func compileNamePolicy(p NamePolicy) (namePolicy, error) {
+	switch {
+	case p.Style != "" && p.Pattern != "":
+		return namePolicy{}, fmt.Errorf("style and pattern are mutually exclusive")
+	case p.Pattern != "":
+		re, err := regexp.Compile(`^(?:` + p.Pattern + `)$`)
+		if err != nil {
+			return namePolicy{}, fmt.Errorf("pattern: %v", err)
+		}
+		return namePolicy{re: re, desc: "name matching " + p.Pattern}, nil
+	}
+	style := p.Style
+	if style == "" {
+		style = "snake_case"
+	}
+	re, ok := nameStyles[style]
+	if !ok {
+		return namePolicy{}, fmt.Errorf("unknown style '%s' (known: dns-1123, kebab-case, snake_case)", style)
+	}
+	return namePolicy{re: re, desc: style + " name"}, nil
+}
+
+// ---------- feature gates ----------
+
+// knownGates — все поддерживаемые гейты и их значения по умолчанию.
+var knownGates = map[string]bool{
+	"sctp": false, // protocol: SCTP в портах
+}
+
+// FeatureGateKnown — есть ли гейт с таким именем (без учёта регистра).
+func FeatureGateKnown(name string) bool {
+	_, ok := knownGates[strings.ToLower(name)]
+	return ok
+}
+
+// compileGates накладывает конфиг поверх значений по умолчанию.
+func compileGates(cfg Config) (map[string]bool, error) {
+	out := make(map[string]bool, len(knownGates))
+	for name, on := range knownGates {
+		out[name] = on
+	}
+	for name, on := range cfg.FeatureGates {
+		key := strings.ToLower(name)
+		if _, ok := knownGates[key]; !ok {
+			return nil, fmt.Errorf("unknown feature gate '%s'", name)
+		}
+		out[key] = on
+	}
+	return out, nil
+}
+
+// ---------- rule packs ----------
+
+// knownPacks — наборы правил, которые включаются только явно; получают
+// контекст документа, как и группы правил.
+var knownPacks = map[string]func(ctx *ValidationContext, bag *errBag){
+	"recommended-labels": validateRecommendedLabels,
+	"podman":             validatePodmanCompat,
+	"best-practices":     validateBestPractices,
+}
+
+// RulePackKnown — есть ли набор правил с таким именем.
+func RulePackKnown(name string) bool {
+	_, ok := knownPacks[name]
+	return ok
+}
+
+// compilePacks возвращает включённые наборы в порядке имён, чтобы вывод
+// был стабильным.
+func compilePacks(cfg Config) ([]string, error) {
+	seen := map[string]struct{}{}
+	for _, name := range append(cfg.RulePacks, presets[cfg.Preset].packs...) {
+		if _, ok := knownPacks[name]; !ok {
+			return nil, fmt.Errorf("unknown rule pack '%s'", name)
+		}
+		seen[name] = struct{}{}
+	}
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// ---------- enums ----------
+
+// builtinEnums — значения, которые принимаются без всякого конфига.
+// Ключ os сравнивается без учёта регистра, поэтому хранится в нижнем.
+var builtinEnums = map[string][]string{
+	"apiVersion": {"v1"},
+	"protocol":   {"TCP", "UDP"},
+	"os":         {"linux", "windows"},
+}
+
+// reAPIVersion — v1 или group/version (apps/v1, networking.k8s.io/v1beta1).
+var reAPIVersion = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*/)?v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// ValidAPIVersion — подходит ли строка как apiVersion: version или
+// group/version.
+func ValidAPIVersion(s string) bool { return reAPIVersion.MatchString(s) }
+
+type enumSet map[string]struct{}
+
+func (s enumSet) has(v string) bool { _, ok := s[v]; return ok }
+
+// values — значения по алфавиту, для подсказок.
+func (s enumSet) values() []string {
+	out := make([]string, 0, len(s))
+	for v := range s {
+		out = append(out, v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// compileEnums собирает списки из встроенных значений, конфига и гейтов.
+func compileEnums(cfg Config, gates map[string]bool) (map[string]enumSet, error) {
+	out := make(map[string]enumSet, len(builtinEnums))
+	for name, vals := range builtinEnums {
+		set := enumSet{}
+		for _, v := range vals {
+			set[v] = struct{}{}
+		}
+		out[name] = set
+	}
+	names := make([]string, 0, len(cfg.Enums))
+	for name := range cfg.Enums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		set, ok := out[name]
+		if !ok {
+			return nil, fmt.Errorf("enums: unknown enum '%s'", name)
+		}
+		for _, v := range cfg.Enums[name] {
+			if name == "os" {
+				v = strings.ToLower(v)
+			}
+			if name == "apiVersion" && !reAPIVersion.MatchString(v) {
+				return nil, fmt.Errorf("enums: apiVersion '%s' must be version or group/version", v)
+			}
+			set[v] = struct{}{}
+		}
+	}
+	if gates["sctp"] {
+		out["protocol"]["SCTP"] = struct{}{}
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+// configmap.go
+package validator
+
+import (
+	"encoding/base64"
+	"regexp"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// reConfigMapKey — ключ data и binaryData: буквы, цифры, -, _ и точка,
+// как имя файла при монтировании тома.
+var reConfigMapKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// maxConfigMapSize — предел apiserver на ключи и значения data и
+// binaryData вместе.
+const maxConfigMapSize = 1 << 20
+
+// validateConfigMap — у ConfigMap нет spec: данные лежат в data и
+// binaryData на верхнем уровне.
+func validateConfigMap(m fields, bag *errBag) {
+	size := 0
+	keys := map[string]bool{}
+	if data, ok := m.get("data"); ok {
+		size += validateConfigMapData(data, keys, false, bag)
+	}
+	if bin, ok := m.get("binaryData"); ok {
+		size += validateConfigMapData(bin, keys, true, bag)
+	}
+	if size > maxConfigMapSize {
+		bag.add(nil, codeConfigMapSize, size, maxConfigMapSize)
+	}
+	if im, ok := m.get("immutable"); ok && !(im.Kind == yaml.ScalarNode && im.Tag == "!!bool") {
+		bag.add(im, codeImmutableType)
+	}
+}
+
+// validateConfigMapData проверяет data (binary=false) или binaryData и
+// возвращает их размер в байтах; ключи, уже встреченные в другой
+// секции, — ошибка.
+func validateConfigMapData(n *yaml.Node, keys map[string]bool, binary bool, bag *errBag) int {
+	field, typeCode, valueCode := "data", codeConfigMapDataType, codeConfigMapValueType
+	if binary {
+		field, typeCode, valueCode = "binaryData", codeBinaryDataType, codeBinaryDataValue
+	}
+	if n.Kind != yaml.MappingNode {
+		bag.add(n, typeCode)
+		return 0
+	}
+	size := 0
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		switch {
+		case !isScalarString(k) || len(k.Value) > maxNameLen || !reConfigMapKey.MatchString(k.Value):
+			bag.add(k, codeConfigMapKey, field, k.Value)
+		case keys[k.Value]:
+			bag.add(k, codeConfigMapKeyDup, k.Value)
+		}
+		keys[k.Value] = true
+		size += len(k.Value)
+		if !isScalarString(v) {
+			bag.add(v, valueCode, k.Value)
+			continue
+		}
+		if !binary {
+			size += len(v.Value)
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(v.Value)
+		if err != nil {
+			bag.add(v, valueCode, k.Value)
+			continue
+		}
+		size += len(raw)
+	}
+	return size
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// configMap — ConfigMap с верхнеуровневыми строками body.
+func configMap(body string) string {
+	return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app\n" + body
+}
+
+func TestConfigMap(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", configMap("data:\n  app.properties: |\n    a=1\n  LOG_LEVEL: info\nbinaryData:\n  logo.png: iVBORw0KGgo=\nimmutable: true\n"), ""},
+		{"no data", configMap(""), ""},
+		{"data not object", configMap("data: [a]\n"), codeConfigMapDataType},
+		{"value not string", configMap("data:\n  replicas: 3\n"), codeConfigMapValueType},
+		{"binaryData not object", configMap("binaryData: logo\n"), codeBinaryDataType},
+		{"binary not base64", configMap("binaryData:\n  logo.png: not base64!\n"), codeBinaryDataValue},
+		{"key format", configMap("data:\n  app/config: x\n"), codeConfigMapKey},
+		{"key too long", configMap("data:\n  " + strings.Repeat("k", 254) + ": x\n"), codeConfigMapKey},
+		{"key in both", configMap("data:\n  logo: x\nbinaryData:\n  logo: eA==\n"), codeConfigMapKeyDup},
+		{"too large", configMap("data:\n  blob: " + strings.Repeat("a", 1<<20) + "\n"), codeConfigMapSize},
+		{"immutable not bool", configMap("immutable: \"true\"\n"), codeImmutableType},
+	})
+}
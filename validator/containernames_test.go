@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainerNamePolicy(t *testing.T) {
+	named := func(name string) string { return strings.Replace(pod("", ""), "- name: web", "- name: "+name, 1) }
+	tests := []struct {
+		policy NamePolicy
+		cases  []ruleCase
+	}{
+		{NamePolicy{}, []ruleCase{
+			{"snake by default", named("web_app"), ""},
+			{"kebab under snake", named("web-app"), codeContainerNameFormat},
+		}},
+		{NamePolicy{Style: "kebab-case"}, []ruleCase{
+			{"kebab", named("web-app"), ""},
+			{"snake under kebab", named("web_app"), codeContainerNameFormat},
+		}},
+		{NamePolicy{Style: "dns-1123"}, []ruleCase{
+			{"dns label", named("web-2"), ""},
+			{"too long", named(strings.Repeat("a", 64)), codeContainerNameFormat},
+		}},
+		{NamePolicy{Pattern: "app-[a-z]+"}, []ruleCase{
+			{"pattern", named("app-web"), ""},
+			{"whole name", named("my-app-web"), codeContainerNameFormat},
+		}},
+	}
+	for _, tt := range tests {
+		checkRules(t, Config{ContainerNames: tt.policy}, tt.cases)
+	}
+}
+
+func TestContainerNameHelp(t *testing.T) {
+	v, err := New(Config{ContainerNames: NamePolicy{Style: "kebab-case"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Validate([]byte(strings.Replace(pod("", ""), "- name: web", "- name: web_app", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if is := findIssue(res, codeContainerNameFormat); is == nil || is.Help != "expected kebab-case name" {
+		t.Errorf("issue %+v, want help 'expected kebab-case name'", is)
+	}
+}
+
+func TestContainerNamePolicyConfig(t *testing.T) {
+	for name, p := range map[string]NamePolicy{
+		"both":          {Style: "kebab-case", Pattern: "[a-z]+"},
+		"unknown style": {Style: "camelCase"},
+		"bad pattern":   {Pattern: "[a-"},
+	} {
+		if _, err := New(Config{ContainerNames: p}); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
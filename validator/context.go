@@ -0,0 +1,95 @@
+// context.go
+package validator
+
+import (
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ValidationContext — документ и то, что о нём знают все проверки:
+// kind, настройки и факты пода, собранные одним проходом. Группы правил
+// получают его вместо голого узла и не ищут spec пода и контейнеры
+// каждая заново; правила, сверяющие разные части пода (порты с пробами,
+// volumeMounts с volumes), берут факты отсюда. После построения
+// контекст только читается: группы работают с ним параллельно.
+type ValidationContext struct {
+	Doc  *yaml.Node
+	Kind string
+	// PodSpec — spec пода (podSpecOf); nil у kind'ов без пода и если spec
+	// не объект.
+	PodSpec *yaml.Node
+	// Containers — контейнеры пода-объекты: сначала initContainers, затем
+	// containers, в порядке манифеста.
+	Containers []ContainerInfo
+	// Volumes — spec.volumes по имени.
+	Volumes map[string]*yaml.Node
+
+	rules *rules
+}
+
+// ContainerInfo — контейнер пода и его поля, на которые ссылаются другие
+// части манифеста.
+type ContainerInfo struct {
+	Node *yaml.Node
+	Name string
+	Init bool
+	// Ports — именованные containerPort: имя → номер.
+	Ports map[string]int
+	// Mounts — элементы volumeMounts.
+	Mounts []*yaml.Node
+}
+
+func newValidationContext(doc *yaml.Node, r *rules) *ValidationContext {
+	ctx := &ValidationContext{Doc: doc, Kind: kindOf(doc), rules: r}
+	spec := podSpecOf(doc)
+	if spec == nil || spec.Kind != yaml.MappingNode {
+		return ctx
+	}
+	ctx.PodSpec = spec
+	for _, list := range []string{"initContainers", "containers"} {
+		cs, _ := child(spec, list)
+		if cs == nil || cs.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range cs.Content {
+			if c.Kind != yaml.MappingNode {
+				continue
+			}
+			ctx.Containers = append(ctx.Containers, containerInfo(c, list == "initContainers"))
+		}
+	}
+	if vols, _ := child(spec, "volumes"); vols != nil && vols.Kind == yaml.SequenceNode {
+		ctx.Volumes = map[string]*yaml.Node{}
+		for _, v := range vols.Content {
+			if name, _ := child(v, "name"); name != nil && isScalarString(name) {
+				ctx.Volumes[name.Value] = v
+			}
+		}
+	}
+	return ctx
+}
+
+func containerInfo(c *yaml.Node, init bool) ContainerInfo {
+	info := ContainerInfo{Node: c, Init: init}
+	if name, _ := child(c, "name"); name != nil && isScalarString(name) {
+		info.Name = name.Value
+	}
+	if ports, _ := child(c, "ports"); ports != nil && ports.Kind == yaml.SequenceNode {
+		for _, p := range ports.Content {
+			name, _ := child(p, "name")
+			num, _ := child(p, "containerPort")
+			if name == nil || num == nil || !isScalarString(name) || !isScalarInt(num) {
+				continue
+			}
+			if n, err := toInt(num.Value); err == nil {
+				if info.Ports == nil {
+					info.Ports = map[string]int{}
+				}
+				info.Ports[name.Value] = n
+			}
+		}
+	}
+	if mounts, _ := child(c, "volumeMounts"); mounts != nil && mounts.Kind == yaml.SequenceNode {
+		info.Mounts = mounts.Content
+	}
+	return info
+}
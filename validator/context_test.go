@@ -0,0 +1,41 @@
+package validator
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestValidationContext(t *testing.T) {
+	src := pod("      ports: [{name: http, containerPort: 8080}, {containerPort: 9090}]\n      volumeMounts: [{name: data, mountPath: /data}]\n",
+		"  initContainers: [{name: init, image: busybox:1.36}]\n  volumes: [{name: data, emptyDir: {}}]\n")
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+		t.Fatal(err)
+	}
+	ctx := newValidationContext(doc.Content[0], nil)
+	if ctx.Kind != "Pod" || ctx.PodSpec == nil {
+		t.Fatalf("context %+v", ctx)
+	}
+	if len(ctx.Containers) != 2 {
+		t.Fatalf("%d containers, want 2", len(ctx.Containers))
+	}
+	init, web := ctx.Containers[0], ctx.Containers[1]
+	if init.Name != "init" || !init.Init || web.Name != "web" || web.Init {
+		t.Errorf("containers %q init=%v, %q init=%v", init.Name, init.Init, web.Name, web.Init)
+	}
+	if len(web.Ports) != 1 || web.Ports["http"] != 8080 || len(web.Mounts) != 1 {
+		t.Errorf("web ports %v, %d mounts", web.Ports, len(web.Mounts))
+	}
+	if ctx.Volumes["data"] == nil {
+		t.Errorf("volumes %v", ctx.Volumes)
+	}
+
+	var cm yaml.Node
+	if err := yaml.Unmarshal([]byte("kind: ConfigMap\ndata: {a: b}\n"), &cm); err != nil {
+		t.Fatal(err)
+	}
+	if ctx := newValidationContext(cm.Content[0], nil); ctx.PodSpec != nil || ctx.Containers != nil {
+		t.Errorf("configmap context %+v", ctx)
+	}
+}
@@ -0,0 +1,162 @@
+// cronjob.go
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var concurrencyPolicies = []string{"Allow", "Forbid", "Replace"}
+
+// cronMacros — сокращения расписания, которые понимает robfig/cron в
+// контроллере CronJob.
+var cronMacros = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+
+// cronField — поле cron-выражения: допустимый диапазон и имена значений.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 6, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+func validateCronJobSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeWorkloadSpecType)
+		return
+	}
+	sched, ok := m.get("schedule")
+	switch {
+	case !ok:
+		bag.add(nil, codeScheduleRequired)
+	case !isScalarString(sched):
+		bag.add(sched, codeScheduleInvalid, sched.Value)
+		bag.help("schedule must be string")
+	default:
+		if err := parseSchedule(sched.Value); err != nil {
+			bag.add(sched, codeScheduleInvalid, sched.Value)
+			bag.help("%v", err)
+		}
+	}
+	if cp, ok := m.get("concurrencyPolicy"); ok {
+		if !isScalarString(cp) || !contains(concurrencyPolicies, cp.Value) {
+			unsupported(bag, cp, codeConcurrencyPolicy, codeConcurrencyPolicyCase, concurrencyPolicies)
+		}
+	}
+	for _, field := range []string{"startingDeadlineSeconds", "successfulJobsHistoryLimit", "failedJobsHistoryLimit"} {
+		if v, ok := m.get(field); ok {
+			if x, err := toInt(v.Value); !isScalarInt(v) || err != nil || x < 0 {
+				bag.add(v, codeCronJobCount, field)
+			}
+		}
+	}
+
+	jt, ok := m.get("jobTemplate")
+	if !ok {
+		bag.add(nil, codeJobTemplateRequired)
+		return
+	}
+	jm, node := getMap(jt)
+	if jm == nil {
+		bag.add(node, codeJobTemplateType)
+		return
+	}
+	spec, ok := jm.get("spec")
+	if !ok {
+		bag.add(nil, codeSpecRequired)
+		return
+	}
+	validateJobSpec(spec, bag)
+}
+
+// parseSchedule разбирает расписание так же, как контроллер: пять полей
+// или макрос. Часовой пояс в расписании (TZ=, CRON_TZ=) Kubernetes
+// запрещает — для него есть spec.timeZone.
+func parseSchedule(s string) error {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "TZ=") || strings.HasPrefix(s, "CRON_TZ=") {
+		return fmt.Errorf("time zone in schedule is not supported, use timeZone")
+	}
+	if strings.HasPrefix(s, "@") {
+		if rest, ok := strings.CutPrefix(s, "@every "); ok {
+			if d, err := time.ParseDuration(strings.TrimSpace(rest)); err != nil || d <= 0 {
+				return fmt.Errorf("@every needs positive duration, e.g. @every 1h30m")
+			}
+			return nil
+		}
+		if !contains(cronMacros, s) {
+			return fmt.Errorf("unknown macro, allowed: %s, @every <duration>", strings.Join(cronMacros, ", "))
+		}
+		return nil
+	}
+	parts := strings.Fields(s)
+	if len(parts) != len(cronFields) {
+		return fmt.Errorf("expected 5 fields (minute hour day-of-month month day-of-week), got %d", len(parts))
+	}
+	for i, part := range parts {
+		if err := cronFields[i].parse(part); err != nil {
+			return fmt.Errorf("%s: %v", cronFields[i].name, err)
+		}
+	}
+	return nil
+}
+
+// parse проверяет одно поле: список через запятую из *, ?, значений и
+// диапазонов a-b, у каждого может быть шаг /n.
+func (f cronField) parse(s string) error {
+	for _, item := range strings.Split(s, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+				return fmt.Errorf("invalid step '%s'", step)
+			}
+		}
+		if rng == "*" || rng == "?" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		from, err := f.value(lo)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		to, err := f.value(hi)
+		if err != nil {
+			return err
+		}
+		if from > to {
+			return fmt.Errorf("range '%s' is reversed", rng)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
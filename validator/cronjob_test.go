@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// cronJob — CronJob со строками spec и корректным jobTemplate.
+func cronJob(spec string) string {
+	// шаблон пода Job на четыре пробела глубже: spec.jobTemplate.spec
+	tmpl := strings.TrimSuffix(jobTemplate("OnFailure"), "\n")
+	return "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: report\nspec:\n" + spec +
+		"  jobTemplate:\n    spec:\n    " + strings.ReplaceAll(tmpl, "\n", "\n    ") + "\n"
+}
+
+func TestCronJob(t *testing.T) {
+	header := "apiVersion: batch/v1\nkind: CronJob\nmetadata:\n  name: report\nspec:\n  schedule: \"0 3 * * *\"\n"
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", cronJob("  schedule: \"*/15 * * * MON-FRI\"\n  concurrencyPolicy: Forbid\n  successfulJobsHistoryLimit: 3\n"), ""},
+		{"schedule missing", cronJob(""), codeScheduleRequired},
+		{"schedule not string", cronJob("  schedule: 5\n"), codeScheduleInvalid},
+		{"schedule invalid", cronJob("  schedule: \"0 25 * * *\"\n"), codeScheduleInvalid},
+		{"concurrencyPolicy", cronJob("  schedule: \"@daily\"\n  concurrencyPolicy: Queue\n"), codeConcurrencyPolicy},
+		{"concurrencyPolicy case", cronJob("  schedule: \"@daily\"\n  concurrencyPolicy: forbid\n"), codeConcurrencyPolicyCase},
+		{"count negative", cronJob("  schedule: \"@daily\"\n  failedJobsHistoryLimit: -1\n"), codeCronJobCount},
+		{"jobTemplate missing", header, codeJobTemplateRequired},
+		{"jobTemplate not object", header + "  jobTemplate: job\n", codeJobTemplateType},
+		{"jobTemplate spec missing", header + "  jobTemplate: {metadata: {labels: {app: web}}}\n", codeSpecRequired},
+		{"job rules apply", strings.Replace(cronJob("  schedule: \"@daily\"\n"), "restartPolicy: OnFailure", "restartPolicy: Always", 1), codeRestartPolicy},
+	})
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		schedule string
+		err      string // "" — расписание корректно
+	}{
+		{"0 3 * * *", ""},
+		{"*/5 1-6,22 ? JAN-MAR sun", ""},
+		{"@hourly", ""},
+		{"@every 1h30m", ""},
+		{"@every -1h", "@every needs positive duration, e.g. @every 1h30m"},
+		{"@often", "unknown macro"},
+		{"0 3 * *", "expected 5 fields (minute hour day-of-month month day-of-week), got 4"},
+		{"60 * * * *", "minute: value 60 out of range 0-59"},
+		{"0 0 0 * *", "day of month: value 0 out of range 1-31"},
+		{"0 0 * 12-1 *", "month: range '12-1' is reversed"},
+		{"*/0 * * * *", "minute: invalid step '0'"},
+		{"0 0 * * FUN", "day of week: invalid value 'FUN'"},
+		{"CRON_TZ=UTC 0 3 * * *", "time zone in schedule is not supported, use timeZone"},
+	}
+	for _, tt := range tests {
+		err := parseSchedule(tt.schedule)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%q: unexpected error %v", tt.schedule, err)
+		case tt.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.err)):
+			t.Errorf("%q: error %v, want %q", tt.schedule, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// daemonset.go
+package validator
+
+import yaml "gopkg.in/yaml.v3"
+
+func validateDaemonSetSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeWorkloadSpecType)
+		return
+	}
+	// под на каждом подходящем узле: число реплик задаёт не манифест
+	if r, ok := m.get("replicas"); ok {
+		bag.add(r, codeDaemonSetReplicas)
+	}
+	validateControllerSpec(m, bag)
+
+	// updateStrategy (optional)
+	if us, ok := m.get("updateStrategy"); ok {
+		validateDaemonSetUpdate(us, bag)
+	}
+}
+
+func validateDaemonSetUpdate(n *yaml.Node, bag *errBag) {
+	rm := validateUpdateStrategy(n, bag)
+	if rm == nil {
+		return
+	}
+	// по умолчанию maxSurge 0, maxUnavailable 1
+	surgeZero, unavailableZero := true, false
+	if v, ok := rm.get("maxSurge"); ok {
+		surgeZero = zeroIntOrPercent(v, "maxSurge", bag)
+	}
+	mu, ok := rm.get("maxUnavailable")
+	if ok {
+		unavailableZero = zeroIntOrPercent(mu, "maxUnavailable", bag)
+	}
+	// оба нуля — выкат не может ни добавить под, ни убрать старый
+	if surgeZero && unavailableZero {
+		bag.add(mu, codeRollingUpdateZero)
+	}
+}
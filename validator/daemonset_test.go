@@ -0,0 +1,18 @@
+package validator
+
+import "testing"
+
+func TestDaemonSet(t *testing.T) {
+	ds := func(spec string) string {
+		return "apiVersion: apps/v1\nkind: DaemonSet\nmetadata:\n  name: agent\nspec:\n" + webSelector + webTemplate + spec
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", ds(""), ""},
+		{"replicas", ds("  replicas: 3\n"), codeDaemonSetReplicas},
+		{"surge", ds("  updateStrategy: {rollingUpdate: {maxSurge: 1, maxUnavailable: 0}}\n"), ""},
+		{"on delete", ds("  updateStrategy: {type: OnDelete}\n"), ""},
+		{"both zero", ds("  updateStrategy: {rollingUpdate: {maxUnavailable: 0}}\n"), codeRollingUpdateZero},
+		{"maxSurge format", ds("  updateStrategy: {rollingUpdate: {maxSurge: one}}\n"), codeIntOrPercent},
+		{"update unsupported", ds("  updateStrategy: {type: Recreate}\n"), codeUpdateStrategyUnsupported},
+	})
+}
@@ -0,0 +1,283 @@
+// defaults.go
+package validator
+
+import (
+	"strconv"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// defaultComment — пометка подставленных полей в --show-defaults.
+const defaultComment = "# default"
+
+// probeDefaults — значения полей проб по умолчанию; по ним же правила
+// сравнивают поля, которых нет в манифесте.
+var probeDefaults = map[string]int{
+	"periodSeconds":    10,
+	"timeoutSeconds":   1,
+	"successThreshold": 1,
+	"failureThreshold": 3,
+}
+
+// kindDefaults — поля spec по умолчанию у контроллеров и Service; пути
+// через точку от spec.
+var kindDefaults = map[string][][2]string{
+	"Deployment": {
+		{"replicas", "1"}, {"revisionHistoryLimit", "10"}, {"progressDeadlineSeconds", "600"},
+		{"strategy.type", "RollingUpdate"},
+	},
+	"StatefulSet": {
+		{"replicas", "1"}, {"revisionHistoryLimit", "10"}, {"podManagementPolicy", "OrderedReady"},
+		{"updateStrategy.type", "RollingUpdate"},
+	},
+	"DaemonSet": {
+		{"revisionHistoryLimit", "10"}, {"updateStrategy.type", "RollingUpdate"},
+	},
+	"Job": {
+		{"backoffLimit", "6"}, {"completions", "1"}, {"parallelism", "1"},
+	},
+	"CronJob": {
+		{"concurrencyPolicy", "Allow"}, {"suspend", "false"},
+		{"successfulJobsHistoryLimit", "3"}, {"failedJobsHistoryLimit", "1"},
+	},
+	"Service": {
+		{"type", "ClusterIP"}, {"sessionAffinity", "None"},
+	},
+}
+
+// Defaulted — документы data с полями, которые подставил бы API-сервер:
+// протокол портов, imagePullPolicy по тегу, restartPolicy, параметры
+// проб и поля контроллеров. Подставленные ключи помечены комментарием
+// # default; исходное дерево не меняется. Пустые документы и документы
+// вне фильтров пропускаются.
+func (v *Validator) Defaulted(data []byte) ([]*yaml.Node, error) {
+	docs, err := v.rules.engine.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var out []*yaml.Node
+	for _, doc := range docs {
+		if isNullDocument(doc) || !v.rules.filter.allows(doc) {
+			continue
+		}
+		doc = copyNode(doc)
+		applyDefaults(doc)
+		unflowDefaults(doc)
+		out = append(out, doc)
+	}
+	return out, nil
+}
+
+func copyNode(n *yaml.Node) *yaml.Node {
+	c := *n
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = copyNode(child)
+	}
+	return &c
+}
+
+// unflowDefaults переводит в блочный стиль узлы, внутри которых есть
+// подставленные поля: комментарий # default внутри [...] или {...}
+// закрыл бы скобку и сломал документ. Возвращает, нашлись ли такие поля.
+func unflowDefaults(n *yaml.Node) bool {
+	found := n.LineComment == defaultComment
+	for _, c := range n.Content {
+		if unflowDefaults(c) {
+			found = true
+		}
+	}
+	if found {
+		n.Style &^= yaml.FlowStyle
+	}
+	return found
+}
+
+// applyDefaults дописывает в doc поля по умолчанию.
+func applyDefaults(doc *yaml.Node) {
+	kind := kindOf(doc)
+	if spec, _ := child(doc, "spec"); spec != nil {
+		if kind == "CronJob" {
+			// у CronJob свои поля в spec, поля Job — в jobTemplate.spec
+			for _, d := range kindDefaults[kind] {
+				setDefault(spec, d[0], d[1])
+			}
+			kind = "Job"
+		}
+		if target := controllerSpecOf(doc); target != nil {
+			for _, d := range kindDefaults[kind] {
+				setDefault(target, d[0], d[1])
+			}
+		}
+		if kind == "Service" {
+			defaultServicePorts(spec)
+		}
+	}
+	if spec := podSpecOf(doc); spec != nil {
+		defaultPodSpec(spec, kind != "Job")
+	}
+}
+
+func defaultServicePorts(spec *yaml.Node) {
+	ports, _ := child(spec, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, p := range ports.Content {
+		setDefault(p, "protocol", "TCP")
+		// targetPort по умолчанию равен port
+		if port, ok := child(p, "port"); ok && isScalarInt(port) {
+			setDefault(p, "targetPort", port.Value)
+		}
+	}
+}
+
+// defaultPodSpec — restartPolicy Always (у Job его по умолчанию нет: без
+// него Job отвергается) и поля контейнеров.
+func defaultPodSpec(spec *yaml.Node, restartAlways bool) {
+	if spec.Kind != yaml.MappingNode {
+		return
+	}
+	if restartAlways {
+		setDefault(spec, "restartPolicy", "Always")
+	}
+	setDefault(spec, "dnsPolicy", "ClusterFirst")
+	setDefault(spec, "terminationGracePeriodSeconds", "30")
+	for _, list := range []string{"initContainers", "containers"} {
+		cs, _ := child(spec, list)
+		if cs == nil || cs.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, c := range cs.Content {
+			defaultContainer(c)
+		}
+	}
+}
+
+func defaultContainer(c *yaml.Node) {
+	if c.Kind != yaml.MappingNode {
+		return
+	}
+	// imagePullPolicy: Always для latest и образа без тега, иначе IfNotPresent
+	if img, ok := child(c, "image"); ok && isScalarString(img) {
+		policy := "IfNotPresent"
+		if floatingTag(img.Value) {
+			policy = "Always"
+		}
+		setDefault(c, "imagePullPolicy", policy)
+	}
+	setDefault(c, "terminationMessagePath", "/dev/termination-log")
+	setDefault(c, "terminationMessagePolicy", "File")
+	if ports, _ := child(c, "ports"); ports != nil && ports.Kind == yaml.SequenceNode {
+		for _, p := range ports.Content {
+			setDefault(p, "protocol", "TCP")
+		}
+	}
+	for _, field := range []string{"livenessProbe", "readinessProbe", "startupProbe"} {
+		probe, _ := child(c, field)
+		if probe == nil || probe.Kind != yaml.MappingNode {
+			continue
+		}
+		for _, key := range sortedKeys(probeDefaults) {
+			setDefault(probe, key, strconv.Itoa(probeDefaults[key]))
+		}
+		if get, _ := child(probe, "httpGet"); get != nil {
+			setDefault(get, "scheme", "HTTP")
+		}
+	}
+}
+
+// setDefault дописывает в маппинг n значение по пути path (ключи через
+// точку), если его там нет; промежуточные маппинги создаются. Значения
+// другого типа на пути не трогаются — о них сообщат правила.
+func setDefault(n *yaml.Node, path, value string) {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return
+	}
+	key, rest, nested := cutPath(path)
+	if v, ok := child(n, key); ok {
+		if nested {
+			setDefault(v, rest, value)
+		}
+		return
+	}
+	k := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key, LineComment: defaultComment}
+	if !nested {
+		n.Content = append(n.Content, k, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+		return
+	}
+	m := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	n.Content = append(n.Content, k, m)
+	setDefault(m, rest, value)
+}
+
+func cutPath(path string) (key, rest string, nested bool) {
+	for i := 0; i < len(path); i++ {
+		if path[i] == '.' {
+			return path[:i], path[i+1:], true
+		}
+	}
+	return path, "", false
+}
+
+// RenderPods — поды, которые создадут объекты data: у Pod это он сам, у
+// контроллеров — шаблон пода, оформленный отдельным манифестом Pod с
+// именем и неймспейсом контроллера. Значения по умолчанию подставлены,
+// как в Defaulted; элементы List разбираются по отдельности, kind'ы без
+// пода пропускаются.
+func (v *Validator) RenderPods(data []byte) ([]*yaml.Node, error) {
+	docs, err := v.Defaulted(data)
+	if err != nil {
+		return nil, err
+	}
+	var out []*yaml.Node
+	for _, doc := range expandLists(docs) {
+		kind := kindOf(doc)
+		switch {
+		case kind == "Pod":
+			out = append(out, doc)
+		case templateKinds[kind]:
+			if pod := podFromTemplate(doc); pod != nil {
+				out = append(out, pod)
+			}
+		}
+	}
+	return out, nil
+}
+
+// podFromTemplate — Pod из spec.template контроллера; nil, если шаблона
+// или его spec нет.
+func podFromTemplate(doc *yaml.Node) *yaml.Node {
+	tmpl := podTemplateOf(doc)
+	if tmpl == nil || tmpl.Kind != yaml.MappingNode {
+		return nil
+	}
+	spec, _ := child(tmpl, "spec")
+	if spec == nil {
+		return nil
+	}
+	meta, _ := child(tmpl, "metadata")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		meta = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	meta.Style &^= yaml.FlowStyle
+	if owner, _ := child(doc, "metadata"); owner != nil {
+		for _, field := range []string{"namespace", "name"} {
+			if v, _ := child(owner, field); v != nil && isScalarString(v) {
+				if _, ok := child(meta, field); !ok {
+					meta.Content = append([]*yaml.Node{scalarNode(field), scalarNode(v.Value)}, meta.Content...)
+				}
+			}
+		}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		scalarNode("apiVersion"), scalarNode("v1"),
+		scalarNode("kind"), scalarNode("Pod"),
+		scalarNode("metadata"), meta,
+		scalarNode("spec"), spec,
+	}}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
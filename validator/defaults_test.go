@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestProbeTimeout(t *testing.T) {
+	probe := func(fields string) string {
+		return pod("      livenessProbe: {httpGet: {path: /healthz, port: 8080}"+fields+"}\n", "")
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"defaults", probe(""), ""},
+		{"within period", probe(", timeoutSeconds: 5, periodSeconds: 10"), ""},
+		{"equal", probe(", timeoutSeconds: 10"), ""},
+		{"exceeds default period", probe(", timeoutSeconds: 15"), codeProbeTimeout},
+		{"exceeds period", probe(", timeoutSeconds: 3, periodSeconds: 2"), codeProbeTimeout},
+	})
+}
+
+func TestDefaulted(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := `apiVersion: apps/v1
+kind: Deployment
+metadata: {name: web}
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+        - name: web
+          image: nginx:latest
+          ports: [{containerPort: 80}]
+          readinessProbe: {httpGet: {path: /, port: 80}, periodSeconds: 5}
+        - name: sidecar
+          image: envoy:1.29
+---
+`
+	docs, err := v.Defaulted([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 {
+		t.Fatalf("%d documents, want 1 (empty one skipped)", len(docs))
+	}
+	out, err := yaml.Marshal(docs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// подстановка внутри [...] не ломает документ
+	var back any
+	if err := yaml.Unmarshal(out, &back); err != nil {
+		t.Fatalf("defaulted document does not parse: %v\n%s", err, out)
+	}
+	got := string(out)
+	for _, want := range []string{
+		"replicas: 3\n",
+		"revisionHistoryLimit: 10 # default",
+		"type: RollingUpdate # default",
+		"restartPolicy: Always # default",
+		"protocol: TCP # default",
+		"imagePullPolicy: Always # default",
+		"imagePullPolicy: IfNotPresent # default",
+		"periodSeconds: 5\n",
+		"timeoutSeconds: 1 # default",
+		"scheme: HTTP # default",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("defaulted document lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "replicas: 1") || strings.Contains(got, "periodSeconds: 10") {
+		t.Errorf("default overrides a set value:\n%s", got)
+	}
+}
+
+// У Job restartPolicy по умолчанию нет: без него Job отвергается.
+func TestDefaultedJob(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := v.Defaulted([]byte(job("", "")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := yaml.Marshal(docs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); strings.Contains(got, "restartPolicy") || !strings.Contains(got, "backoffLimit: 6 # default") {
+		t.Errorf("defaulted job:\n%s", got)
+	}
+}
+
+// Шаблон контроллера становится Pod с именем и неймспейсом владельца;
+// kind'ы без пода пропускаются.
+func TestRenderPods(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := strings.Replace(job("", "Never"), "  name: ", "  namespace: batch\n  name: ", 1) +
+		"---\n" + pod("", "") + "---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: cfg}\n"
+	docs, err := v.RenderPods([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("%d pods, want 2", len(docs))
+	}
+	var got struct {
+		Kind     string
+		Metadata struct{ Name, Namespace string }
+		Spec     struct {
+			RestartPolicy string `yaml:"restartPolicy"`
+		}
+	}
+	if err := docs[0].Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Kind != "Pod" || got.Metadata.Namespace != "batch" || got.Metadata.Name != "migrate" || got.Spec.RestartPolicy != "Never" {
+		t.Errorf("job pod %+v", got)
+	}
+	if kindOf(docs[1]) != "Pod" {
+		t.Errorf("second document %s, want Pod", kindOf(docs[1]))
+	}
+}
@@ -0,0 +1,89 @@
+// digest.go
+package validator
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// DocumentDigest — sha256 исходного текста документа: по нему в отчёте
+// видно, какое именно содержимое проверено.
+type DocumentDigest struct {
+	// Document — номер документа в файле, начиная с 1.
+	Document int    `json:"document"`
+	Line     int    `json:"line,omitempty"`
+	SHA256   string `json:"sha256"`
+}
+
+// docSegment — строки файла между разделителями документов.
+type docSegment struct {
+	line int
+	text []byte
+}
+
+// splitSegments режет поток по строкам ---; голая строка --- в текст
+// документа не входит, --- с содержимым (--- !!map, --- {a: 1}) — входит.
+// Сегмент начинается со строки своего разделителя, чтобы пустой документ
+// тоже нашёл свой сегмент.
+func splitSegments(data []byte) []docSegment {
+	segs := []docSegment{{line: 1}}
+	line := 1
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+		l := data[:end]
+		data = data[end:]
+		if bytes.HasPrefix(l, []byte("---")) && (len(l) == 3 || l[3] == ' ' || l[3] == '\t' || l[3] == '\n' || l[3] == '\r') {
+			segs = append(segs, docSegment{line: line})
+			rest := bytes.TrimSpace(l[3:])
+			if len(rest) > 0 && rest[0] != '#' {
+				segs[len(segs)-1].text = append(segs[len(segs)-1].text, l...)
+			}
+		} else {
+			segs[len(segs)-1].text = append(segs[len(segs)-1].text, l...)
+		}
+		line++
+	}
+	return segs
+}
+
+// documentDigests — дайджест каждого документа потока в порядке docs.
+// Документ относится к последнему сегменту, который начался не позже его
+// первой строки; у пустого документа строки нет, ему достаётся сегмент
+// сразу за предыдущим документом.
+func documentDigests(data []byte, docs []*yaml.Node) []DocumentDigest {
+	segs := splitSegments(data)
+	out := make([]DocumentDigest, len(docs))
+	prev := -1
+	for i, doc := range docs {
+		seg := min(prev+1, len(segs)-1)
+		if !isNullDocument(doc) {
+			for j := seg; j < len(segs) && segs[j].line <= doc.Line; j++ {
+				seg = j
+			}
+		}
+		prev = seg
+		sum := sha256.Sum256(segs[seg].text)
+		out[i] = DocumentDigest{Document: i + 1, Line: doc.Line, SHA256: hex.EncodeToString(sum[:])}
+	}
+	return out
+}
+
+// RuleSetVersion — отпечаток набора правил сборки: кодов, имён и текстов
+// сообщений. Меняется с любым правилом, поэтому вместе с версией
+// инструмента говорит, чем именно проверен отчёт.
+func RuleSetVersion() string { return ruleSetVersion() }
+
+var ruleSetVersion = sync.OnceValue(func() string {
+	h := sha256.New()
+	for _, code := range sortedKeys(English) {
+		h.Write([]byte(code + "\x00" + ruleNames[code] + "\x00" + English[code] + "\n"))
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))[:16]
+})
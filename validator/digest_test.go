@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func sha(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// Дайджест документа — sha256 его текста без голой строки ---: его можно
+// пересчитать sha256sum по куску файла.
+func TestDocumentDigests(t *testing.T) {
+	cm := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"
+	pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: p\nspec: {}\n"
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"single", cm, []string{sha(cm)}},
+		{"two", cm + "---\n" + pod, []string{sha(cm), sha(pod)}},
+		{"leading marker", "---\n" + cm, []string{sha(cm)}},
+		{"marker with comment", cm + "--- # pod\n" + pod, []string{sha(cm), sha(pod)}},
+		{"marker with content", "--- {a: 1}\n", []string{sha("--- {a: 1}\n")}},
+		{"empty document", cm + "---\n---\n" + pod, []string{sha(cm), sha(""), sha(pod)}},
+	}
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		res, err := v.ValidateFile("app.yaml", []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for i, d := range res.Documents {
+			if d.Document != i+1 {
+				t.Errorf("%s: document %d numbered %d", tt.name, i+1, d.Document)
+			}
+			got = append(got, d.SHA256)
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: digests %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Находка несёт дайджест своего документа, находка файла — нет.
+func TestIssueDocumentDigest(t *testing.T) {
+	cm := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"
+	pod := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: p\nspec: {}\n"
+	v, err := New(Config{Documents: DocumentPolicy{Max: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.ValidateFile("app.yaml", []byte(cm+"---\n"+pod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, is := range res.Issues {
+		want := ""
+		switch {
+		case is.Code == codeTooManyDocuments:
+		case is.Document == 2:
+			want = sha(pod)
+		default:
+			t.Errorf("unexpected finding %s in document %d", is.Code, is.Document)
+		}
+		if is.DocumentSHA256 != want {
+			t.Errorf("%s: digest %q, want %q", is.Code, is.DocumentSHA256, want)
+		}
+	}
+	if len(res.Issues) < 2 {
+		t.Errorf("want a file finding and a document finding, got %s", codes(res))
+	}
+}
+
+func TestRuleSetVersion(t *testing.T) {
+	if v := RuleSetVersion(); !strings.HasPrefix(v, "sha256:") || len(v) != len("sha256:")+16 || v != RuleSetVersion() {
+		t.Errorf("RuleSetVersion() = %q", v)
+	}
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDocumentPolicy(t *testing.T) {
+	deploy := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec: {}\n"
+	svc := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec: {}\n"
+	tests := []ruleCase{
+		{"deployment and service", deploy + "---\n" + svc, ""},
+		{"too few", deploy, codeTooFewDocuments + " " + codeKindMissing},
+		{"too many", deploy + "---\n" + svc + "---\n" + svc + "---\n" + svc, codeTooManyDocuments},
+		// пустые документы не считаются
+		{"empty documents", deploy + "---\n---\n" + svc, ""},
+		{"kind missing", svc + "---\n" + svc, codeKindMissing},
+	}
+	v, err := New(Config{Documents: DocumentPolicy{Min: 2, Max: 3, RequireKinds: []string{"Deployment", "Service"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		res, err := v.Validate([]byte(tt.doc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// находки самих документов здесь ни при чём: смотрим только FIL
+		var got []string
+		for _, is := range res.Issues {
+			if strings.HasPrefix(is.Code, "FIL") {
+				got = append(got, is.Code)
+			}
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("%s: file findings %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentPolicyConfig(t *testing.T) {
+	for _, p := range []DocumentPolicy{{Min: -1}, {Max: -1}, {Min: 3, Max: 2}} {
+		if _, err := New(Config{Documents: p}); err == nil {
+			t.Errorf("documents %+v accepted", p)
+		}
+	}
+}
+
+// Номер документа ставится находкам второго и следующих документов.
+func TestDocumentIndex(t *testing.T) {
+	plan9 := pod("", "  os: {name: plan9}\n")
+	res := validate(t, Config{}, plan9+"---\n"+plan9+"---\n"+pod("", "")+"---\n"+plan9)
+	var got []int
+	for _, is := range res.Issues {
+		got = append(got, is.Document)
+	}
+	if fmt.Sprint(got) != "[0 2 4]" {
+		t.Errorf("documents %v, want [0 2 4]", got)
+	}
+}
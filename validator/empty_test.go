@@ -0,0 +1,46 @@
+package validator
+
+import "testing"
+
+func TestEmptyInput(t *testing.T) {
+	doc := pod("", "")
+	checkRules(t, Config{}, []ruleCase{
+		{"empty file", "", codeEmptyFile},
+		{"whitespace", "\n\n  \n", codeEmptyFile},
+		{"comments only", "# TODO\n# manifests go here\n", codeCommentsOnly},
+		{"empty document", doc + "---\n---\n" + doc, codeEmptyDocument},
+		{"leading separator", "---\n" + doc, ""},
+		{"trailing separator", doc + "---\n", ""},
+	})
+	checkRules(t, Config{EmptyDocument: "off"}, []ruleCase{
+		{"off: empty file", "", ""},
+		{"off: empty document", doc + "---\n---\n" + doc, ""},
+	})
+}
+
+func TestEmptyDocumentSeverity(t *testing.T) {
+	tests := []struct {
+		setting string
+		want    Severity
+	}{
+		{"", SeverityInfo},
+		{"warning", SeverityWarning},
+		{"error", SeverityError},
+	}
+	for _, tt := range tests {
+		v, err := New(Config{EmptyDocument: tt.setting})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := v.Validate(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Issues) != 1 || res.Issues[0].Severity != tt.want {
+			t.Errorf("emptyDocument %q: %+v, want one finding of %v", tt.setting, res.Issues, tt.want)
+		}
+	}
+	if _, err := New(Config{EmptyDocument: "loud"}); err == nil {
+		t.Error("emptyDocument: loud accepted")
+	}
+}
@@ -0,0 +1,131 @@
+// engine.go
+package validator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Engine — разборщик YAML. Правила работают с деревом yaml.v3, поэтому
+// другой парсер обязан отдать те же узлы: Kind, Tag, Value, Style и
+// позиции, а у пустого документа — скаляр !!null. Документ-корень не
+// возвращается, только его содержимое.
+type Engine interface {
+	Name() string
+	Decode(data []byte) ([]*yaml.Node, error)
+}
+
+// engines — доступные разборщики; дополнительные регистрируются из
+// файлов под build-тегами, чтобы обычная сборка не тянула их зависимости.
+var engines = map[string]Engine{}
+
+// defaultEngine — разборщик, если Config.Engine не задан; build-тег
+// может его заменить.
+var defaultEngine = "yaml.v3"
+
+func registerEngine(e Engine) { engines[e.Name()] = e }
+
+func init() { registerEngine(yamlV3{}) }
+
+// Engines — имена разборщиков, собранных в бинарник, по алфавиту.
+func Engines() []string { return sortedKeys(engines) }
+
+// DefaultEngine — имя разборщика по умолчанию в этой сборке.
+func DefaultEngine() string { return defaultEngine }
+
+// CheckEngine — ошибка, если разборщика с таким именем нет в сборке.
+func CheckEngine(name string) error {
+	_, err := compileEngine(name)
+	return err
+}
+
+// compileEngine выбирает разборщик по имени из конфига.
+func compileEngine(name string) (Engine, error) {
+	if name == "" {
+		name = defaultEngine
+	}
+	e, ok := engines[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown engine '%s' (available: %s)", name, strings.Join(Engines(), ", "))
+	}
+	return e, nil
+}
+
+// yamlV3 — разборщик gopkg.in/yaml.v3, эталон для остальных.
+type yamlV3 struct{}
+
+func (yamlV3) Name() string { return "yaml.v3" }
+
+// Decode читает все документы потока, разделённые ---.
+func (yamlV3) Decode(data []byte) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []*yaml.Node
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				return docs, nil
+			}
+			return nil, err
+		}
+		if len(doc.Content) > 0 {
+			docs = append(docs, doc.Content[0])
+		}
+	}
+}
+
+// EngineDiff — расхождение разборщиков на одном файле: находка, которую
+// выдал только один из них, или ошибка разбора только у одного.
+type EngineDiff struct {
+	Engine string
+	Line   int
+	Text   string
+}
+
+// CompareEngines проверяет data каждым разборщиком сборки и возвращает
+// расхождения с эталонным yaml.v3. Это проверка соответствия для нового
+// парсера: на одном и том же корпусе результаты должны совпасть.
+func (v *Validator) CompareEngines(path string, data []byte) []EngineDiff {
+	base := v.issueSet(yamlV3{}, path, data)
+	var diffs []EngineDiff
+	for _, name := range Engines() {
+		if name == (yamlV3{}).Name() {
+			continue
+		}
+		other := v.issueSet(engines[name], path, data)
+		for _, key := range sortedKeys(base) {
+			if !hasKey(other, key) {
+				diffs = append(diffs, EngineDiff{Engine: name, Line: base[key], Text: "missing: " + key})
+			}
+		}
+		for _, key := range sortedKeys(other) {
+			if !hasKey(base, key) {
+				diffs = append(diffs, EngineDiff{Engine: name, Line: other[key], Text: "extra: " + key})
+			}
+		}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool { return diffs[i].Line < diffs[j].Line })
+	return diffs
+}
+
+// issueSet — находки прогона одним разборщиком как множество
+// «строка:колонка код текст» со строкой находки; ошибка разбора — один
+// элемент без позиции, текст у разных парсеров разный.
+func (v *Validator) issueSet(e Engine, path string, data []byte) map[string]int {
+	r := *v.rules
+	r.engine = e
+	res, err := (&Validator{rules: &r}).ValidateFile(path, data)
+	if err != nil {
+		return map[string]int{"parse error": 0}
+	}
+	set := make(map[string]int, len(res.Issues))
+	for _, is := range res.Issues {
+		set[fmt.Sprintf("%d:%d %s %s", is.Line, is.Column, is.Code, is.Message())] = is.Line
+	}
+	return set
+}
@@ -0,0 +1,254 @@
+//go:build goccy
+
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/lexer"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// сборка с -tags goccy разбирает github.com/goccy/go-yaml: он точнее
+// указывает позиции ошибок и быстрее на больших файлах. yaml.v3 остаётся
+// доступен через engine: yaml.v3.
+func init() {
+	registerEngine(goccyEngine{})
+	defaultEngine = "goccy"
+}
+
+type goccyEngine struct{}
+
+func (goccyEngine) Name() string { return "goccy" }
+
+// Decode переводит AST goccy в узлы yaml.v3. Документ без тела —
+// скаляр !!null на позиции следующего токена, как у yaml.v3; файл из
+// одних комментариев не даёт документов.
+func (goccyEngine) Decode(data []byte) ([]*yaml.Node, error) {
+	c := goccyConverter{anchors: map[string]*yaml.Node{}}
+	chunks := splitDocuments(lexer.Tokenize(string(data)))
+	var docs []*yaml.Node
+	for i, chunk := range chunks {
+		f, err := parser.Parse(chunk, 0)
+		if err != nil {
+			return nil, errors.New(goyaml.FormatError(err, false, false))
+		}
+		for _, doc := range f.Docs {
+			if _, ok := doc.Body.(*ast.DirectiveNode); !ok && doc.Body != nil {
+				docs = append(docs, c.node(doc.Body))
+				continue
+			}
+			if doc.Start == nil {
+				continue
+			}
+			null := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+			if i+1 < len(chunks) {
+				null.Line, null.Column = position(chunks[i+1][0])
+			} else {
+				null.Line, null.Column = strings.Count(string(data), "\n")+1, 1
+			}
+			docs = append(docs, null)
+		}
+	}
+	return docs, nil
+}
+
+// splitDocuments режет поток токенов по --- и ...: парсер goccy теряет
+// документы, идущие в одном потоке после пустого. Директивы %YAML
+// остаются с документом, который за ними следует.
+func splitDocuments(tokens token.Tokens) []token.Tokens {
+	var chunks []token.Tokens
+	var cur token.Tokens
+	body, directiveLine := false, 0
+	for _, tk := range tokens {
+		if tk.Type == token.DocumentHeaderType && body {
+			chunks, cur = append(chunks, cur), nil
+		}
+		cur = append(cur, tk)
+		switch {
+		case tk.Type == token.DocumentEndType:
+			chunks, cur, body = append(chunks, cur), nil, false
+		case tk.Type == token.DirectiveType:
+			directiveLine = tk.Position.Line
+		case tk.Position.Line != directiveLine:
+			body = true
+		}
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks
+}
+
+// goccyConverter помнит якоря потока, чтобы алиас указывал на узел.
+type goccyConverter struct {
+	anchors map[string]*yaml.Node
+}
+
+func (c goccyConverter) node(n ast.Node) *yaml.Node {
+	switch n := n.(type) {
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	case *ast.MappingNode:
+		out := collection(yaml.MappingNode, "!!map", n.Start, n.IsFlowStyle)
+		for _, kv := range n.Values {
+			out.Content = append(out.Content, c.node(kv.Key), c.node(kv.Value))
+		}
+		if !n.IsFlowStyle && len(out.Content) > 0 {
+			out.Line, out.Column = out.Content[0].Line, out.Content[0].Column
+		}
+		return out
+	case *ast.MappingValueNode:
+		// маппинг из одной пары парсер отдаёт без обёртки
+		out := collection(yaml.MappingNode, "!!map", nil, n.IsFlowStyle)
+		out.Content = append(out.Content, c.node(n.Key), c.node(n.Value))
+		out.Line, out.Column = out.Content[0].Line, out.Content[0].Column
+		return out
+	case *ast.MappingKeyNode:
+		return c.node(n.Value)
+	case *ast.SequenceNode:
+		out := collection(yaml.SequenceNode, "!!seq", n.Start, n.IsFlowStyle)
+		for _, v := range n.Values {
+			out.Content = append(out.Content, c.node(v))
+		}
+		return out
+	case *ast.AnchorNode:
+		out := c.node(n.Value)
+		out.Anchor = n.Name.GetToken().Value
+		out.Line, out.Column = position(n.Start)
+		c.anchors[out.Anchor] = out
+		return out
+	case *ast.AliasNode:
+		name := n.Value.GetToken().Value
+		out := &yaml.Node{Kind: yaml.AliasNode, Value: name, Alias: c.anchors[name]}
+		out.Line, out.Column = position(n.Start)
+		return out
+	case *ast.TagNode:
+		out := c.node(n.Value)
+		if tag := n.Start.Value; tag != "!" {
+			out.Tag = shortTag(tag)
+			out.Style |= yaml.TaggedStyle
+		}
+		out.Line, out.Column = position(n.Start)
+		return out
+	case *ast.LiteralNode:
+		style := yaml.LiteralStyle
+		if strings.HasPrefix(n.Start.Value, ">") {
+			style = yaml.FoldedStyle
+		}
+		return scalar(n.Start, n.Value.Value, style)
+	case *ast.StringNode:
+		var style yaml.Style
+		switch n.Token.Type {
+		case token.SingleQuoteType:
+			style = yaml.SingleQuotedStyle
+		case token.DoubleQuoteType:
+			style = yaml.DoubleQuotedStyle
+		}
+		return scalar(n.Token, n.Value, style)
+	case *ast.NullNode:
+		// неявный null (key: без значения) у yaml.v3 пустой
+		if n.Token.Type != token.NullType {
+			return scalar(n.Token, "", 0)
+		}
+	}
+	// числа, bool, null, <<: тип узла goccy не важен, тег выводится из
+	// текста по правилам yaml.v3
+	return scalar(n.GetToken(), n.GetToken().Value, 0)
+}
+
+func collection(kind yaml.Kind, tag string, start *token.Token, flow bool) *yaml.Node {
+	out := &yaml.Node{Kind: kind, Tag: tag}
+	out.Line, out.Column = position(start)
+	if flow {
+		out.Style = yaml.FlowStyle
+	}
+	return out
+}
+
+// scalar — скаляр с тегом как у yaml.v3: в кавычках и блочные — !!str,
+// простые — по resolvePlain.
+func scalar(tk *token.Token, value string, style yaml.Style) *yaml.Node {
+	tag := "!!str"
+	if style == 0 {
+		tag = resolvePlain(value)
+	}
+	out := &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value, Style: style}
+	out.Line, out.Column = position(tk)
+	return out
+}
+
+func position(tk *token.Token) (int, int) {
+	if tk == nil || tk.Position == nil {
+		return 0, 0
+	}
+	return tk.Position.Line, tk.Position.Column
+}
+
+// shortTag сокращает tag:yaml.org,2002:int до !!int, как yaml.v3.
+func shortTag(tag string) string {
+	if rest, ok := strings.CutPrefix(tag, "tag:yaml.org,2002:"); ok {
+		return "!!" + rest
+	}
+	return tag
+}
+
+var (
+	plainTags = map[string]string{
+		"true": "!!bool", "True": "!!bool", "TRUE": "!!bool",
+		"false": "!!bool", "False": "!!bool", "FALSE": "!!bool",
+		"": "!!null", "~": "!!null", "null": "!!null", "Null": "!!null", "NULL": "!!null",
+		".nan": "!!float", ".NaN": "!!float", ".NAN": "!!float",
+		".inf": "!!float", ".Inf": "!!float", ".INF": "!!float",
+		"+.inf": "!!float", "+.Inf": "!!float", "+.INF": "!!float",
+		"-.inf": "!!float", "-.Inf": "!!float", "-.INF": "!!float",
+		"<<": "!!merge",
+	}
+	plainFloat      = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	plainTimestamps = []string{"2006-1-2T15:4:5.999999999Z07:00", "2006-1-2t15:4:5.999999999Z07:00", "2006-1-2 15:4:5.999999999", "2006-1-2"}
+)
+
+// resolvePlain — тег простого скаляра по правилам yaml.v3 (resolve.go):
+// у goccy свои, например 1e3 для него строка.
+func resolvePlain(value string) string {
+	if tag, ok := plainTags[value]; ok {
+		return tag
+	}
+	if value == "" || !strings.ContainsRune("+-.0123456789", rune(value[0])) {
+		return "!!str"
+	}
+	if value[0] == '.' {
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			return "!!float"
+		}
+		return "!!str"
+	}
+	if len(value) > 4 && value[4] == '-' && strings.Trim(value[:4], "0123456789") == "" {
+		for _, layout := range plainTimestamps {
+			if _, err := time.Parse(layout, value); err == nil {
+				return "!!timestamp"
+			}
+		}
+	}
+	plain := strings.ReplaceAll(value, "_", "")
+	if _, err := strconv.ParseInt(plain, 0, 64); err == nil {
+		return "!!int"
+	}
+	if _, err := strconv.ParseUint(plain, 0, 64); err == nil {
+		return "!!int"
+	}
+	if plainFloat.MatchString(plain) {
+		if _, err := strconv.ParseFloat(plain, 64); err == nil {
+			return "!!float"
+		}
+	}
+	return "!!str"
+}
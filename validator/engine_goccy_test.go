@@ -0,0 +1,57 @@
+//go:build goccy
+
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Разборщики должны давать одинаковые находки: корпус testdata/engines и
+// случаи, на которых парсеры расходятся чаще всего.
+func TestEnginesAgree(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"anchor and alias":  "apiVersion: v1\nkind: Pod\nmetadata: &m\n  name: a\nspec:\n  containers: [{name: a, image: x, resources: {}}]\n  extra: *m\n",
+		"merge key":         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  <<: {name: a}\n  labels: {app: a}\n",
+		"yaml 1.1 booleans": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  on: yes\n  off: no\n",
+		"octal and hex":     "apiVersion: v1\nkind: Service\nmetadata:\n  name: a\nspec:\n  ports: [{port: 0x50}, {port: 0o17, name: b}]\n",
+		"block scalars":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\ndata:\n  k: |\n    line\n  f: >-\n    folded\n",
+		"empty documents":   "---\n---\napiVersion: v1\nkind: ConfigMap\nmetadata: {name: a}\n---\n",
+		"explicit tags":     "apiVersion: v1\nkind: Pod\nmetadata: {name: a}\nspec:\n  containers:\n    - {name: a, image: x, resources: {}, ports: [{containerPort: !!str 80}]}\n",
+		"unicode":           "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n  annotations: {описание: \"значение — 😀\"}\n",
+		"syntax error":      "apiVersion: v1\nkind: [\n",
+	}
+	files, err := filepath.Glob(filepath.Join("testdata", "engines", "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("testdata/engines is empty")
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tests[f] = string(data)
+	}
+	for name, data := range tests {
+		for _, d := range v.CompareEngines("", []byte(data)) {
+			t.Errorf("%s:%d %s %s", name, d.Line, d.Engine, d.Text)
+		}
+	}
+}
+
+func TestGoccyIsDefault(t *testing.T) {
+	if DefaultEngine() != "goccy" {
+		t.Errorf("default engine %s, want goccy", DefaultEngine())
+	}
+	if len(Engines()) < 2 {
+		t.Errorf("engines %v, want goccy and yaml.v3", Engines())
+	}
+}
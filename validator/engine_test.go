@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestEngineConfig(t *testing.T) {
+	if _, err := New(Config{Engine: "yaml.v3"}); err != nil {
+		t.Errorf("yaml.v3: %v", err)
+	}
+	if err := CheckEngine(""); err != nil {
+		t.Errorf("default engine %s: %v", DefaultEngine(), err)
+	}
+	if err := CheckEngine("libyaml"); err == nil || !strings.Contains(err.Error(), "available: ") {
+		t.Errorf("unknown engine: %v", err)
+	}
+}
+
+// firstDocOnly — разборщик с ошибкой: теряет все документы, кроме первого.
+type firstDocOnly struct{}
+
+func (firstDocOnly) Name() string { return "first-doc-only" }
+
+func (firstDocOnly) Decode(data []byte) ([]*yaml.Node, error) {
+	docs, err := yamlV3{}.Decode(data)
+	if len(docs) > 1 {
+		docs = docs[:1]
+	}
+	return docs, err
+}
+
+func TestCompareEngines(t *testing.T) {
+	registerEngine(firstDocOnly{})
+	defer delete(engines, firstDocOnly{}.Name())
+
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan9 := strings.Replace(pod("", ""), "spec:\n", "spec:\n  os: {name: plan9}\n", 1)
+	var diffs []EngineDiff
+	for _, d := range v.CompareEngines("app.yaml", []byte(pod("", "")+"---\n"+plan9)) {
+		if d.Engine == (firstDocOnly{}).Name() {
+			diffs = append(diffs, d)
+		}
+	}
+	if len(diffs) != 1 || diffs[0].Line != 18 || !strings.HasPrefix(diffs[0].Text, "missing: ") {
+		t.Errorf("diffs %+v, want the plan9 finding missing", diffs)
+	}
+	for _, d := range v.CompareEngines("app.yaml", []byte(plan9)) {
+		if d.Engine == (firstDocOnly{}).Name() {
+			t.Errorf("single document differs: %+v", d)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// findIssue — первая находка с кодом code или nil.
+func findIssue(res *Result, code string) *Issue {
+	for i := range res.Issues {
+		if res.Issues[i].Code == code {
+			return &res.Issues[i]
+		}
+	}
+	return nil
+}
+
+func TestEnumCase(t *testing.T) {
+	port := func(protocol string) string {
+		return pod("      ports: [{containerPort: 8080, protocol: "+protocol+"}]\n", "")
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"apiVersion case", strings.Replace(pod("", ""), "apiVersion: v1", "apiVersion: V1", 1), codeAPIVersionCase},
+		{"kind case", strings.Replace(pod("", ""), "kind: Pod", "kind: POD", 1), codeKindCase},
+		{"kind unsupported", strings.Replace(pod("", ""), "kind: Pod", "kind: Widget", 1), codeKindUnsupported},
+		{"protocol case", port("tcp"), codeProtocolCase},
+		{"protocol spaces", port("\" UDP\""), codeProtocolCase},
+		{"protocol unsupported", port("HTTP"), codeProtocolUnsupported},
+	})
+}
+
+// Подсказка: для опечатки в регистре — правильное значение, иначе —
+// список допустимых.
+func TestEnumHelp(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		protocol, code, help string
+	}{
+		{"udp", codeProtocolCase, "did you mean 'UDP'?"},
+		{"HTTP", codeProtocolUnsupported, "allowed: TCP, UDP"},
+	}
+	for _, tt := range tests {
+		res, err := v.Validate([]byte(pod("      ports: [{containerPort: 8080, protocol: "+tt.protocol+"}]\n", "")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		is := findIssue(res, tt.code)
+		if is == nil || !strings.HasPrefix(is.Help, tt.help) {
+			t.Errorf("%s: issue %+v, want %s with help %q", tt.protocol, is, tt.code, tt.help)
+		}
+	}
+}
+
+func TestOSCase(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"mixed case", pod("", "  os: {name: Linux}\n"), ""},
+		{"spaces", pod("", "  os: {name: \" linux \"}\n"), codeOSCase},
+		{"scalar spaces", pod("", "  os: 'windows '\n"), codeOSCase},
+		{"unsupported", pod("", "  os: {name: darwin}\n"), codeOSUnsupported},
+	})
+}
+
+// --fix заменяет значение с опечаткой, сохраняя кавычки.
+func TestEnumCaseFix(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ spec, container, want string }{
+		{"", "      ports: [{containerPort: 8080, protocol: tcp}]\n", "protocol: TCP}"},
+		{"  os: {name: \" linux \"}\n", "", `os: {name: "linux"}`},
+		{"  os: 'windows '\n", "", "os: 'windows'"},
+	}
+	for _, tt := range tests {
+		data := []byte(pod(tt.container, tt.spec))
+		res, err := v.Validate(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, fixed := ApplyFixes(data, res.Issues)
+		if len(fixed) != 1 || !fixed[0] || !strings.Contains(string(out), tt.want) {
+			t.Errorf("fixed %v, want %q in:\n%s", fixed, tt.want, out)
+		}
+		if res, _ := v.Validate(out); len(res.Issues) != 0 {
+			t.Errorf("fixed document still has %s", codes(res))
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnums(t *testing.T) {
+	sctp := pod("      ports:\n        - containerPort: 8080\n          protocol: SCTP\n", "")
+	freebsd := pod("", "  os: {name: freebsd}\n")
+	v2 := strings.Replace(pod("", ""), "apiVersion: v1", "apiVersion: v2", 1)
+
+	checkRules(t, Config{}, []ruleCase{
+		{"builtin protocol", pod("      ports:\n        - containerPort: 8080\n          protocol: UDP\n", ""), ""},
+		{"protocol", sctp, codeProtocolUnsupported},
+		{"os", freebsd, codeOSUnsupported},
+		{"apiVersion", v2, codeAPIVersionUnsupported},
+	})
+	checkRules(t, Config{Enums: map[string][]string{
+		"protocol":   {"SCTP"},
+		"os":         {"FreeBSD"},
+		"apiVersion": {"v2"},
+	}}, []ruleCase{
+		{"extended protocol", sctp, ""},
+		{"extended os", freebsd, ""},
+		{"extended apiVersion", v2, ""},
+	})
+}
+
+func TestEnumsConfig(t *testing.T) {
+	_, err := New(Config{Enums: map[string][]string{"restartPolicy": {"Sometimes"}}})
+	if err == nil || !strings.Contains(err.Error(), "unknown enum 'restartPolicy'") {
+		t.Errorf("error %v, want unknown enum", err)
+	}
+	_, err = New(Config{Enums: map[string][]string{"apiVersion": {"apps/v1", "apps"}}})
+	if err == nil || !strings.Contains(err.Error(), "apiVersion 'apps' must be version or group/version") {
+		t.Errorf("error %v, want bad apiVersion", err)
+	}
+}
@@ -0,0 +1,139 @@
+// env.go
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// reEnvName — C_IDENTIFIER: имя переменной, которое поймёт любой shell.
+var reEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envSources — источники valueFrom; задаётся ровно один.
+var envSources = []string{"configMapKeyRef", "fieldRef", "resourceFieldRef", "secretKeyRef"}
+
+// envResources — ресурсы контейнера, доступные через resourceFieldRef.
+var envResources = []string{
+	"limits.cpu", "limits.ephemeral-storage", "limits.memory",
+	"requests.cpu", "requests.ephemeral-storage", "requests.memory",
+}
+
+func validateEnv(n *yaml.Node, bag *errBag) {
+	if n.Kind != yaml.SequenceNode {
+		bag.add(n, codeEnvType)
+		return
+	}
+	for _, e := range n.Content {
+		validateEnvVar(e, bag)
+	}
+}
+
+// validateEnvVar — имя обязательно, значение задаётся value или valueFrom,
+// но не обоими.
+func validateEnvVar(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeEnvItemType)
+		return
+	}
+	name, ok := m.get("name")
+	envName := ""
+	if ok {
+		envName = name.Value
+	}
+	switch {
+	case !ok || (isScalarString(name) && name.Value == ""):
+		bag.add(n, codeEnvNameRequired)
+	case !isScalarString(name) || !reEnvName.MatchString(name.Value):
+		bag.add(name, codeEnvNameFormat, name.Value)
+		bag.help("expected letters, digits and '_', not starting with a digit")
+	}
+
+	value, hasValue := m.get("value")
+	from, hasFrom := m.get("valueFrom")
+	if hasValue && hasFrom {
+		bag.add(from, codeEnvValueExclusive)
+	}
+	// value: 8080 без кавычек — int, а API ждёт строку; правка берёт
+	// значение в кавычки
+	if hasValue && !isScalarString(value) && value.Tag != "!!null" {
+		bag.add(value, codeEnvValueType, envName)
+		if value.Kind == yaml.ScalarNode && value.Style == 0 {
+			bag.fixValue(value, `"`+value.Value+`"`)
+		}
+	}
+	if hasFrom {
+		validateValueFrom(from, bag)
+	}
+}
+
+func validateValueFrom(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeValueFromType)
+		return
+	}
+	var source string
+	count := 0
+	for _, s := range envSources {
+		if _, ok := m.get(s); ok {
+			source = s
+			count++
+		}
+	}
+	if count != 1 {
+		bag.add(n, codeValueFromSource, strings.Join(envSources, ", "))
+		return
+	}
+	ref, _ := m.get(source)
+	rm, node := getMap(ref)
+	if rm == nil {
+		bag.add(node, codeEnvRefType, source)
+		return
+	}
+	switch source {
+	case "fieldRef":
+		envRefString(rm, ref, source, "fieldPath", true, bag)
+		envRefString(rm, ref, source, "apiVersion", false, bag)
+	case "resourceFieldRef":
+		if res := envRefString(rm, ref, source, "resource", true, bag); res != nil && !contains(envResources, res.Value) {
+			unsupported(bag, res, codeEnvResourceUnsupported, codeEnvResourceCase, envResources)
+		}
+		envRefString(rm, ref, source, "containerName", false, bag)
+		if d, ok := rm.get("divisor"); ok {
+			if _, err := ParseQuantity(d.Value); err != nil || d.Kind != yaml.ScalarNode {
+				bag.add(d, codeEnvDivisor, d.Value)
+			}
+		}
+	case "configMapKeyRef", "secretKeyRef":
+		envRefString(rm, ref, source, "name", true, bag)
+		envRefString(rm, ref, source, "key", true, bag)
+		if opt, ok := rm.get("optional"); ok && (opt.Kind != yaml.ScalarNode || opt.Tag != "!!bool") {
+			bag.add(opt, codeEnvRefFieldType, source+".optional", "boolean")
+		}
+	}
+}
+
+// envRefString проверяет строковое поле ссылки и возвращает его, если оно
+// непустая строка.
+func envRefString(m fields, ref *yaml.Node, source, field string, required bool, bag *errBag) *yaml.Node {
+	v, ok := m.get(field)
+	switch {
+	case !ok:
+		if required {
+			bag.add(ref, codeEnvRefField, source+"."+field)
+		}
+		return nil
+	case !isScalarString(v):
+		bag.add(v, codeEnvRefFieldType, source+"."+field, "string")
+		return nil
+	case v.Value == "":
+		if required {
+			bag.add(v, codeEnvRefField, source+"."+field)
+		}
+		return nil
+	}
+	return v
+}
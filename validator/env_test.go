@@ -0,0 +1,63 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// env — под с одной переменной окружения var (flow-маппинг).
+func env(v string) string { return pod("      env: ["+v+"]\n", "") }
+
+// valueFrom — переменная APP со значением из источника from.
+func valueFrom(from string) string { return env("{name: APP, valueFrom: {" + from + "}}") }
+
+func TestEnv(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"value", env("{name: LOG_LEVEL, value: info}"), ""},
+		{"empty value", env("{name: _DEBUG, value: \"\"}"), ""},
+		{"null value", env("{name: DEBUG, value: null}"), ""},
+		{"configMapKeyRef", valueFrom("configMapKeyRef: {name: app, key: url, optional: true}"), ""},
+		{"secretKeyRef", valueFrom("secretKeyRef: {name: db, key: password}"), ""},
+		{"fieldRef", valueFrom("fieldRef: {fieldPath: status.podIP, apiVersion: v1}"), ""},
+		{"resourceFieldRef", valueFrom("resourceFieldRef: {resource: limits.memory, divisor: 1Mi}"), ""},
+		{"env not array", pod("      env: {LOG_LEVEL: info}\n", ""), codeEnvType},
+		{"item not object", env("LOG_LEVEL=info"), codeEnvItemType},
+		{"name missing", env("{value: info}"), codeEnvNameRequired},
+		{"name empty", env("{name: \"\", value: info}"), codeEnvNameRequired},
+		{"name format", env("{name: log-level, value: info}"), codeEnvNameFormat},
+		{"name digit", env("{name: 1ST, value: info}"), codeEnvNameFormat},
+		{"value and valueFrom", env("{name: APP, value: a, valueFrom: {fieldRef: {fieldPath: metadata.name}}}"), codeEnvValueExclusive},
+		{"value not string", env("{name: PORT, value: 8080}"), codeEnvValueType},
+		{"valueFrom not object", env("{name: APP, valueFrom: app}"), codeValueFromType},
+		{"valueFrom empty", valueFrom(""), codeValueFromSource},
+		{"valueFrom two sources", valueFrom("fieldRef: {fieldPath: metadata.name}, secretKeyRef: {name: db, key: password}"), codeValueFromSource},
+		{"ref not object", valueFrom("secretKeyRef: db"), codeEnvRefType},
+		{"key missing", valueFrom("configMapKeyRef: {name: app}"), codeEnvRefField},
+		{"fieldPath empty", valueFrom("fieldRef: {fieldPath: \"\"}"), codeEnvRefField},
+		{"key not string", valueFrom("secretKeyRef: {name: db, key: [password]}"), codeEnvRefFieldType},
+		{"optional not bool", valueFrom("secretKeyRef: {name: db, key: password, optional: \"yes\"}"), codeEnvRefFieldType},
+		{"resource unsupported", valueFrom("resourceFieldRef: {resource: limits.gpu}"), codeEnvResourceUnsupported},
+		{"resource case", valueFrom("resourceFieldRef: {resource: Limits.Memory}"), codeEnvResourceCase},
+		{"divisor", valueFrom("resourceFieldRef: {resource: limits.cpu, divisor: 1x}"), codeEnvDivisor},
+	})
+}
+
+// Число в value правка берёт в кавычки.
+func TestEnvValueFix(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(env("{name: PORT, value: 8080}"))
+	res, err := v.Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, fixed := ApplyFixes(data, res.Issues)
+	if len(fixed) != 1 || !fixed[0] || !strings.Contains(string(out), `value: "8080"`) {
+		t.Errorf("fixed %v:\n%s", fixed, out)
+	}
+	if res, _ := v.Validate(out); len(res.Issues) != 0 {
+		t.Errorf("fixed document still has %s", codes(res))
+	}
+}
@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateToError(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.ValidateToError([]byte(pod("", ""))); err != nil {
+		t.Fatalf("valid pod: %v", err)
+	}
+	if err := v.ValidateToError([]byte("a: [")); err == nil {
+		t.Fatal("parse error lost")
+	}
+	doc := strings.Replace(pod("", ""), "spec:\n", "spec:\n  os: {name: plan9}\n", 1)
+	err = v.ValidateToError([]byte(doc))
+	var ie *IssueError
+	if !errors.As(err, &ie) || ie.Issue.Code != codeOSUnsupported {
+		t.Fatalf("err = %v, want IssueError %s", err, codeOSUnsupported)
+	}
+	if want := "line 6: os has unsupported value 'plan9'"; err.Error() != want {
+		t.Errorf("err = %q, want %q", err, want)
+	}
+}
+
+// В error попадают только ошибки.
+func TestResultErr(t *testing.T) {
+	res := &Result{Issues: []Issue{
+		{Code: codeOSUnsupported, Severity: SeverityWarning, Args: []any{"plan9"}},
+	}}
+	if err := res.Err(); err != nil {
+		t.Errorf("warning became error: %v", err)
+	}
+	res.Issues = append(res.Issues,
+		Issue{Code: codeOSUnsupported, Severity: SeverityError, Args: []any{"a"}},
+		Issue{Code: codeOSUnsupported, Severity: SeverityError, Args: []any{"b"}, Line: 3})
+	if got := strings.Split(res.Err().Error(), "\n"); len(got) != 2 || got[1] != "line 3: os has unsupported value 'b'" {
+		t.Errorf("Err() = %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+// facts.go
+package validator
+
+import (
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Facts — сведения об объекте для инвентаризации: образы, порты, тома,
+// метки и ресурсы пода. Собираются тем же проходом, что и проверки, чтобы
+// инструментам вокруг не приходилось разбирать манифесты заново.
+type Facts struct {
+	// Document — номер документа в файле, начиная с 1; Item — номер
+	// элемента items в kind: List.
+	Document  int               `json:"document"`
+	Item      int               `json:"item,omitempty"`
+	Line      int               `json:"line"`
+	Kind      string            `json:"kind,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Images    []string          `json:"images,omitempty"`
+	Ports     []PortFact        `json:"ports,omitempty"`
+	Volumes   []VolumeFact      `json:"volumes,omitempty"`
+	// Replicas — подов у контроллера (replicasOf); 0 у kind'ов без пода.
+	Replicas int `json:"replicas,omitempty"`
+	// Requests и Limits — на один под, по правилу kube-scheduler.
+	Requests *ResourceTotals `json:"requests,omitempty"`
+	Limits   *ResourceTotals `json:"limits,omitempty"`
+}
+
+// PortFact — порт контейнера или Service; у порта Service Container пуст.
+type PortFact struct {
+	Container string `json:"container,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Port      int    `json:"port"`
+	Protocol  string `json:"protocol"`
+}
+
+// VolumeFact — том пода и его тип: emptyDir, configMap, secret, ...
+type VolumeFact struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// ResourceTotals — CPU в ядрах, Memory в байтах.
+type ResourceTotals struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// collectFacts собирает Facts документа; значения с ошибками типа
+// пропускаются — о них сообщают проверки.
+func collectFacts(ctx *ValidationContext, bag *errBag) {
+	doc := ctx.Doc
+	f := Facts{Line: doc.Line, Kind: ctx.Kind}
+	if meta, _ := child(doc, "metadata"); meta != nil {
+		if name, _ := child(meta, "name"); name != nil && isScalarString(name) {
+			f.Name = name.Value
+			f.Namespace = namespaceOf(doc)
+		}
+		if labels, _ := child(meta, "labels"); labels != nil && isStringMap(labels) && len(labels.Content) > 0 {
+			f.Labels = map[string]string{}
+			for i := 0; i+1 < len(labels.Content); i += 2 {
+				f.Labels[labels.Content[i].Value] = labels.Content[i+1].Value
+			}
+		}
+	}
+	if ctx.Kind == "Service" {
+		spec, _ := child(doc, "spec")
+		if spec != nil {
+			f.Ports = portFacts("", spec, "port")
+		}
+	}
+	if ctx.PodSpec != nil {
+		for _, c := range ctx.Containers {
+			if img, _ := child(c.Node, "image"); img != nil && isScalarString(img) {
+				f.Images = append(f.Images, img.Value)
+			}
+			f.Ports = append(f.Ports, portFacts(c.Name, c.Node, "containerPort")...)
+		}
+		if vols, _ := child(ctx.PodSpec, "volumes"); vols != nil && vols.Kind == yaml.SequenceNode {
+			for _, v := range vols.Content {
+				if vf, ok := volumeFact(v); ok {
+					f.Volumes = append(f.Volumes, vf)
+				}
+			}
+		}
+		f.Replicas = replicasOf(doc)
+		var req, lim ResourceTotals
+		req.CPU, req.Memory = podResources(ctx, "requests")
+		lim.CPU, lim.Memory = podResources(ctx, "limits")
+		f.Requests, f.Limits = &req, &lim
+	}
+	bag.facts = append(bag.facts, f)
+}
+
+// portFacts — числовые порты из списка ports узла n; field — поле
+// номера: containerPort у контейнера, port у Service.
+func portFacts(container string, n *yaml.Node, field string) []PortFact {
+	ports, _ := child(n, "ports")
+	if ports == nil || ports.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var out []PortFact
+	for _, p := range ports.Content {
+		num, _ := child(p, field)
+		if num == nil || !isScalarInt(num) {
+			continue
+		}
+		x, err := toInt(num.Value)
+		if err != nil {
+			continue
+		}
+		pf := PortFact{Container: container, Port: x, Protocol: "TCP"}
+		if name, _ := child(p, "name"); name != nil && isScalarString(name) {
+			pf.Name = name.Value
+		}
+		if proto, _ := child(p, "protocol"); proto != nil && isScalarString(proto) {
+			pf.Protocol = proto.Value
+		}
+		out = append(out, pf)
+	}
+	return out
+}
+
+// volumeFact — имя тома и первое поле источника после name.
+func volumeFact(v *yaml.Node) (VolumeFact, bool) {
+	name, _ := child(v, "name")
+	if name == nil || !isScalarString(name) {
+		return VolumeFact{}, false
+	}
+	vf := VolumeFact{Name: name.Value}
+	for i := 0; i+1 < len(v.Content); i += 2 {
+		if k := v.Content[i].Value; k != "name" {
+			vf.Type = k
+			break
+		}
+	}
+	return vf, true
+}
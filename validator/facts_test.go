@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFacts(t *testing.T) {
+	deploy := strings.Replace(deployment("  replicas: 3\n"+webSelector+webTemplate), "  name: web\n", "  name: web\n  namespace: prod\n  labels: {app: web}\n", 1)
+	deploy = strings.Replace(deploy, "          resources:", "          ports: [{name: http, containerPort: 8080}]\n          resources:", 1) +
+		"      volumes: [{name: cache, emptyDir: {}}]\n"
+	svc := service("  selector: {app: web}\n  ports: [{port: 80, protocol: UDP}]\n")
+	res := validate(t, Config{}, deploy+"---\n"+list(listItem(svc)))
+	if len(res.Facts) != 2 {
+		t.Fatalf("%d facts, want 2", len(res.Facts))
+	}
+
+	want := Facts{
+		Document: 1, Line: 1, Kind: "Deployment", Namespace: "prod", Name: "web",
+		Labels:   map[string]string{"app": "web"},
+		Images:   []string{"registry.bigbrother.io/web:1.0"},
+		Ports:    []PortFact{{Container: "web", Name: "http", Port: 8080, Protocol: "TCP"}},
+		Volumes:  []VolumeFact{{Name: "cache", Type: "emptyDir"}},
+		Replicas: 3,
+		Requests: &ResourceTotals{CPU: 1, Memory: 64 << 20},
+		Limits:   &ResourceTotals{CPU: 1, Memory: 64 << 20},
+	}
+	if got := res.Facts[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("deployment facts\n%+v\nwant\n%+v", got, want)
+	}
+	if got := res.Facts[1]; got.Document != 2 || got.Item != 1 || got.Kind != "Service" ||
+		!reflect.DeepEqual(got.Ports, []PortFact{{Port: 80, Protocol: "UDP"}}) || got.Requests != nil {
+		t.Errorf("service facts %+v", got)
+	}
+}
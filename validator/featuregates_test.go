@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFeatureGateSCTP(t *testing.T) {
+	sctp := pod("      ports:\n        - containerPort: 8080\n          protocol: SCTP\n", "")
+	checkRules(t, Config{FeatureGates: map[string]bool{"sctp": false}}, []ruleCase{
+		{"gate off", sctp, codeProtocolUnsupported},
+	})
+	// имя гейта сравнивается без учёта регистра
+	checkRules(t, Config{FeatureGates: map[string]bool{"SCTP": true}}, []ruleCase{
+		{"gate on", sctp, ""},
+	})
+}
+
+func TestFeatureGateUnknown(t *testing.T) {
+	if FeatureGateKnown("ipv6") || !FeatureGateKnown("Sctp") {
+		t.Error("FeatureGateKnown mismatch")
+	}
+	_, err := New(Config{FeatureGates: map[string]bool{"ipv6": true}})
+	if err == nil || !strings.Contains(err.Error(), "unknown feature gate 'ipv6'") {
+		t.Errorf("error %v, want unknown feature gate", err)
+	}
+}
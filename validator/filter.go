@@ -0,0 +1,85 @@
+// filter.go
+package validator
+
+import (
+	"fmt"
+	"path"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// documentFilter — какие документы входа проверять: селектор меток и
+// маски kind, неймспейса и имени. Остальные документы пропускаются, как
+// будто их нет во входе.
+type documentFilter struct {
+	selector   labelSelector
+	kinds      []string
+	namespaces []string
+	names      []string
+}
+
+func compileFilter(cfg Config) (documentFilter, error) {
+	var f documentFilter
+	var err error
+	if f.selector, err = compileSelector(cfg.Selector); err != nil {
+		return f, fmt.Errorf("selector: %v", err)
+	}
+	for _, list := range []struct {
+		field string
+		src   []string
+		dst   *[]string
+	}{{"kinds", cfg.Kinds, &f.kinds}, {"namespaces", cfg.Namespaces, &f.namespaces}, {"names", cfg.Names, &f.names}} {
+		for _, p := range list.src {
+			if err := CheckPattern(p); err != nil {
+				return f, fmt.Errorf("%s: %v", list.field, err)
+			}
+			*list.dst = append(*list.dst, p)
+		}
+	}
+	return f, nil
+}
+
+// CheckPattern — ошибка в маске kind, неймспейса или имени (синтаксис
+// path.Match), nil для корректной.
+func CheckPattern(p string) error {
+	if _, err := path.Match(p, ""); err != nil {
+		return fmt.Errorf("'%s': bad pattern", p)
+	}
+	return nil
+}
+
+func (f documentFilter) empty() bool {
+	return len(f.selector) == 0 && f.kinds == nil && f.namespaces == nil && f.names == nil
+}
+
+// allows — документ проходит все заданные фильтры; неймспейс по
+// умолчанию — default.
+func (f documentFilter) allows(doc *yaml.Node) bool {
+	if f.kinds != nil && !matchPattern(f.kinds, kindOf(doc)) {
+		return false
+	}
+	if f.namespaces != nil && !matchPattern(f.namespaces, namespaceOf(doc)) {
+		return false
+	}
+	if f.names != nil {
+		name := ""
+		if meta, _ := child(doc, "metadata"); meta != nil {
+			if n, _ := child(meta, "name"); n != nil && isScalarString(n) {
+				name = n.Value
+			}
+		}
+		if !matchPattern(f.names, name) {
+			return false
+		}
+	}
+	return f.selector.matches(labelsOf(doc))
+}
+
+func matchPattern(patterns []string, s string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, s); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentFilter(t *testing.T) {
+	bad := strings.Replace(pod("", ""), "spec:\n", "spec:\n  os: {name: plan9}\n", 1)
+	inProd := strings.Replace(bad, "  name: web\n", "  name: web\n  namespace: prod-eu\n", 1)
+	tests := []struct {
+		name string
+		cfg  Config
+		doc  string
+		want string
+	}{
+		{"kind", Config{Kinds: []string{"Deployment", "P*"}}, bad, codeOSUnsupported},
+		{"other kind", Config{Kinds: []string{"Deployment"}}, bad, ""},
+		{"default namespace", Config{Namespaces: []string{"default"}}, bad, codeOSUnsupported},
+		{"namespace mask", Config{Namespaces: []string{"prod-*"}}, inProd, codeOSUnsupported},
+		{"other namespace", Config{Namespaces: []string{"prod-*"}}, bad, ""},
+		{"name", Config{Names: []string{"web*"}}, bad, codeOSUnsupported},
+		{"other name", Config{Names: []string{"api"}}, bad, ""},
+		// фильтры складываются
+		{"all", Config{Kinds: []string{"Pod"}, Names: []string{"api"}}, bad, ""},
+	}
+	for _, tt := range tests {
+		if got := codes(validate(t, tt.cfg, tt.doc)); got != tt.want {
+			t.Errorf("%s: codes %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for name, cfg := range map[string]Config{
+		"kinds":      {Kinds: []string{"[Pod"}},
+		"namespaces": {Namespaces: []string{"prod-[a"}},
+		"names":      {Names: []string{"\\"}},
+	} {
+		if _, err := New(cfg); err == nil || !strings.HasPrefix(err.Error(), name+":") {
+			t.Errorf("%s: error %v", name, err)
+		}
+	}
+}
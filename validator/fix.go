@@ -0,0 +1,97 @@
+// fix.go
+package validator
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ApplyFixes применяет правки находок к data. Правка пропускается, если
+// текст на её месте уже не Old или она пересекается с другой правкой;
+// fixed[i] — применена ли правка находки i.
+func ApplyFixes(data []byte, issues []Issue) (out []byte, fixed []bool) {
+	type edit struct {
+		i, start, end int
+	}
+	lines := lineOffsets(data)
+	var edits []edit
+	for i, is := range issues {
+		f := is.Fix
+		if f == nil || f.Line < 1 || f.Line > len(lines) {
+			continue
+		}
+		start := runeOffset(data, lines[f.Line-1], f.Column)
+		end := start + len(f.Old)
+		if end > len(data) || string(data[start:end]) != f.Old {
+			continue
+		}
+		if f.Lines > 0 {
+			if strings.TrimLeft(string(data[lines[f.Line-1]:start]), " ") != "" {
+				continue // перед ключом что-то есть, например «- » элемента списка
+			}
+			start, end = lines[f.Line-1], blockEnd(data, lines, f.Line-1+f.Lines, start-lines[f.Line-1])
+		}
+		edits = append(edits, edit{i, start, end})
+	}
+	// с конца файла, чтобы смещения ещё не применённых правок не съезжали
+	sort.SliceStable(edits, func(a, b int) bool { return edits[a].start > edits[b].start })
+	fixed = make([]bool, len(issues))
+	out = append([]byte(nil), data...)
+	limit := len(data)
+	for _, e := range edits {
+		if e.end > limit {
+			continue
+		}
+		out = append(out[:e.start], append([]byte(issues[e.i].Fix.New), out[e.end:]...)...)
+		fixed[e.i] = true
+		limit = e.start
+	}
+	return out, fixed
+}
+
+// blockEnd — конец удаляемого блока: строка next и все следующие за ней
+// строки с отступом больше indent (продолжения многострочных скаляров),
+// пустые — только если блок после них продолжается.
+func blockEnd(data []byte, lines []int, next, indent int) int {
+	end := len(data)
+	if next < len(lines) {
+		end = lines[next]
+	}
+	for i := next; i < len(lines); i++ {
+		lineEnd := len(data)
+		if i+1 < len(lines) {
+			lineEnd = lines[i+1]
+		}
+		text := string(data[lines[i]:lineEnd])
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if len(text)-len(strings.TrimLeft(text, " ")) <= indent {
+			break
+		}
+		end = lineEnd
+	}
+	return end
+}
+
+func lineOffsets(data []byte) []int {
+	offsets := []int{0}
+	for i, c := range data {
+		if c == '\n' {
+			offsets = append(offsets, i+1)
+		}
+	}
+	return offsets
+}
+
+// runeOffset — байтовое смещение колонки col (в символах, с 1) строки,
+// начинающейся с start: колонки yaml.v3 считает в рунах.
+func runeOffset(data []byte, start, col int) int {
+	i := start
+	for n := 1; n < col && i < len(data) && data[i] != '\n'; n++ {
+		_, size := utf8.DecodeRune(data[i:])
+		i += size
+	}
+	return i
+}
@@ -0,0 +1,29 @@
+package validator
+
+import "testing"
+
+func TestApplyFixes(t *testing.T) {
+	data := []byte("name: Web\nos: {name: Linux}\nnote: «ы» Beta\n")
+	issues := []Issue{
+		{Fix: &Fix{Line: 1, Column: 7, Old: "Web", New: "web"}},
+		{Fix: &Fix{Line: 2, Column: 12, Old: "Linux", New: "linux"}},
+		// текст уже другой — правка устарела
+		{Fix: &Fix{Line: 1, Column: 7, Old: "Api", New: "api"}},
+		// пересекается с предыдущей правкой той же строки
+		{Fix: &Fix{Line: 2, Column: 5, Old: "{name: L", New: "{name: l"}},
+		// колонка в символах, а не в байтах
+		{Fix: &Fix{Line: 3, Column: 11, Old: "Beta", New: "beta"}},
+		{Fix: &Fix{Line: 9, Column: 1, Old: "x", New: "y"}},
+		{},
+	}
+	out, fixed := ApplyFixes(data, issues)
+	if want := "name: web\nos: {name: linux}\nnote: «ы» beta\n"; string(out) != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+	want := []bool{true, true, false, false, true, false, false}
+	for i := range want {
+		if fixed[i] != want[i] {
+			t.Errorf("fixed[%d] = %v, want %v", i, fixed[i], want[i])
+		}
+	}
+}
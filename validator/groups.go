@@ -0,0 +1,111 @@
+// groups.go
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Группы проверок. Группа выводится из кода: явные исключения — в
+// groupOverrides, остальное — по имени проверки (…-type и …-required —
+// структура) и по префиксу кода.
+const (
+	GroupStructural   = "structural"
+	GroupSemantic     = "semantic"
+	GroupSecurity     = "security"
+	GroupStyle        = "style"
+	GroupBestPractice = "bestpractice"
+)
+
+// groupOverrides — проверки, группа которых не следует из имени.
+var groupOverrides = map[string]string{
+	codeImageFormat:         GroupSecurity,
+	codePlaintextCredential: GroupSecurity,
+	CodeImageVulnerable:     GroupSecurity,
+
+	codeContainerNameFormat: GroupStyle,
+	codeFileName:            GroupStyle,
+	codeLayout:              GroupStyle,
+
+	CodeServerField: GroupBestPractice,
+}
+
+// groupPrefixes — группы целых семейств кодов.
+var groupPrefixes = map[string]string{
+	"OBJ": GroupStructural,
+	"DOC": GroupStructural,
+	"IO":  GroupStructural,
+	"YML": GroupStyle,
+	"LBL": GroupStyle,
+	"BPR": GroupBestPractice,
+	"PDM": GroupBestPractice,
+}
+
+// flagCodes — проверки, которые включает флаг CLI (--frozen,
+// --check-image-exists, --image-scan-results, --namespace-budgets):
+// флаг уже выбор пользователя, и пресет их не выключает.
+var flagCodes = []string{
+	CodeImageNotFound, CodeImageCheckFailed, CodeImageVulnerable,
+	CodeQuotaExceeded, CodeReferenceMissing,
+	CodeDigestMismatch, CodeNotLocked,
+}
+
+// RuleGroup — группа проверки с кодом code.
+func RuleGroup(code string) string {
+	if g, ok := groupOverrides[code]; ok {
+		return g
+	}
+	if g, ok := groupPrefixes[strings.TrimRight(code, "0123456789")]; ok {
+		return g
+	}
+	name := ruleNames[code]
+	if strings.HasSuffix(name, "-type") || strings.HasSuffix(name, "-required") {
+		return GroupStructural
+	}
+	return GroupSemantic
+}
+
+// presets — группы, которые включает --preset. Новичок начинает с
+// minimal и переходит к строже, не перечисляя коды; отдельные проверки
+// по-прежнему включаются и выключаются через Config.Rules. strict
+// добавляет и опциональные наборы правил с советами.
+var presets = map[string]struct {
+	groups []string
+	packs  []string
+}{
+	"minimal":  {groups: []string{GroupStructural}},
+	"standard": {groups: []string{GroupStructural, GroupSemantic, GroupSecurity}},
+	"strict": {
+		groups: []string{GroupStructural, GroupSemantic, GroupSecurity, GroupStyle, GroupBestPractice},
+		packs:  []string{"best-practices", "recommended-labels"},
+	},
+}
+
+// PresetKnown — есть ли пресет с таким именем.
+func PresetKnown(name string) bool {
+	_, ok := presets[name]
+	return ok
+}
+
+// Presets — имена пресетов по алфавиту.
+func Presets() []string { return sortedKeys(presets) }
+
+// compilePreset выключает проверки групп вне пресета; настройки из
+// Config.Rules сильнее пресета, поэтому их не трогает, как и проверки
+// из flagCodes.
+func compilePreset(name string, settings map[string]ruleSetting) error {
+	if name == "" {
+		return nil
+	}
+	p, ok := presets[name]
+	if !ok {
+		return fmt.Errorf("unknown preset '%s' (available: %s)", name, strings.Join(Presets(), ", "))
+	}
+	for code := range ruleNames {
+		if _, set := settings[code]; set || contains(p.groups, RuleGroup(code)) || contains(flagCodes, code) {
+			continue
+		}
+		settings[code] = ruleSetting{off: true}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleGroup(t *testing.T) {
+	for code, want := range map[string]string{
+		codeAPIVersionRequired:  GroupStructural,
+		codeContainersType:      GroupStructural,
+		codeOSUnsupported:       GroupSemantic,
+		codeImageFormat:         GroupSecurity,
+		codeContainerNameFormat: GroupStyle,
+		codeImageLatestTag:      GroupBestPractice,
+		CodeServerField:         GroupBestPractice,
+	} {
+		if got := RuleGroup(code); got != want {
+			t.Errorf("RuleGroup(%s) = %s, want %s", code, got, want)
+		}
+	}
+}
+
+func TestPresetGroups(t *testing.T) {
+	tests := []struct {
+		preset string
+		code   string
+		kept   bool
+	}{
+		{"minimal", codeContainersRequired, true},
+		{"minimal", codeImageFormat, false},
+		{"minimal", codeFileName, false},
+		{"standard", codeImageFormat, true},
+		{"standard", codeFileName, false},
+		{"strict", codeFileName, true},
+	}
+	for _, tt := range tests {
+		v, err := New(Config{Preset: tt.preset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := &Result{Issues: []Issue{{Code: tt.code}}}
+		v.Adjust(res)
+		if got := len(res.Issues) == 1; got != tt.kept {
+			t.Errorf("preset %s, %s: kept = %v, want %v", tt.preset, tt.code, got, tt.kept)
+		}
+	}
+}
+
+// Config.Rules сильнее пресета, strict включает наборы советов.
+func TestPresetConfig(t *testing.T) {
+	v, err := New(Config{Preset: "minimal", Rules: map[string]string{"os-unsupported": "warning"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &Result{Issues: []Issue{{Code: codeOSUnsupported, Severity: SeverityError}}}
+	v.Adjust(res)
+	if len(res.Issues) != 1 || res.Issues[0].Severity != SeverityWarning {
+		t.Errorf("rules setting lost under preset: %+v", res.Issues)
+	}
+
+	latest := strings.Replace(pod("", ""), "web:1.0", "web:latest", 1)
+	checkRules(t, Config{Preset: "strict"}, []ruleCase{{"floating tag", latest, codeImageLatestTag}})
+	checkRules(t, Config{Preset: "standard"}, []ruleCase{{"floating tag off", latest, ""}})
+
+	if _, err := New(Config{Preset: "paranoid"}); err == nil {
+		t.Error("unknown preset accepted")
+	}
+}
+
+func TestPresetKeepsFlagCodes(t *testing.T) {
+	for _, preset := range Presets() {
+		v, err := New(Config{Preset: preset})
+		if err != nil {
+			t.Fatalf("%s: %v", preset, err)
+		}
+		for _, code := range flagCodes {
+			res := &Result{Issues: []Issue{{Code: code, Severity: SeverityError}}}
+			v.Adjust(res)
+			if !res.FailedAt(SeverityError) {
+				t.Errorf("preset %s turned off %s", preset, code)
+			}
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// hpa.go
+package validator
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var (
+	metricTypes       = []string{"ContainerResource", "External", "Object", "Pods", "Resource"}
+	metricTargetTypes = []string{"AverageValue", "Utilization", "Value"}
+)
+
+// metricSources — поле источника метрики по её type.
+var metricSources = map[string]string{
+	"ContainerResource": "containerResource",
+	"External":          "external",
+	"Object":            "object",
+	"Pods":              "pods",
+	"Resource":          "resource",
+}
+
+// metricTargetFields — поле значения цели по её type.
+var metricTargetFields = map[string]string{
+	"AverageValue": "averageValue",
+	"Utilization":  "averageUtilization",
+	"Value":        "value",
+}
+
+func validateHPASpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeHPASpecType)
+		return
+	}
+
+	// scaleTargetRef (required): kind и name масштабируемого объекта
+	ref, ok := m.get("scaleTargetRef")
+	if !ok {
+		bag.add(nil, codeScaleTargetRequired)
+	} else if rm, node := getMap(ref); rm == nil {
+		bag.add(node, codeScaleTargetType)
+	} else {
+		for _, field := range []string{"kind", "name"} {
+			if v, ok := rm.get(field); !ok || !isScalarString(v) || v.Value == "" {
+				bag.add(ref, codeScaleTargetField, field)
+			}
+		}
+		if api, ok := rm.get("apiVersion"); ok && !isScalarString(api) {
+			bag.add(api, codeAPIVersionType)
+		}
+	}
+
+	// minReplicas (optional, 1 по умолчанию) и maxReplicas (required):
+	// не меньше 1, min не больше max
+	minReplicas, minOK := 1, true
+	if v, ok := m.get("minReplicas"); ok {
+		x, err := toInt(v.Value)
+		if minOK = isScalarInt(v) && err == nil && x >= 1; minOK {
+			minReplicas = x
+		} else {
+			bag.add(v, codeHPAReplicas, "minReplicas")
+		}
+	}
+	maxNode, ok := m.get("maxReplicas")
+	if !ok {
+		bag.add(nil, codeMaxReplicasRequired)
+	} else if x, err := toInt(maxNode.Value); !isScalarInt(maxNode) || err != nil || x < 1 {
+		bag.add(maxNode, codeHPAReplicas, "maxReplicas")
+	} else if minOK && minReplicas > x {
+		bag.add(maxNode, codeHPAReplicasRange, minReplicas, x)
+	}
+
+	if metrics, ok := m.get("metrics"); ok {
+		if metrics.Kind != yaml.SequenceNode {
+			bag.add(metrics, codeMetricsType)
+		} else {
+			for _, mt := range metrics.Content {
+				validateMetric(mt, bag)
+			}
+		}
+	}
+}
+
+func validateMetric(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeMetricType)
+		return
+	}
+	typ, ok := m.get("type")
+	switch {
+	case !ok:
+		bag.add(n, codeMetricTypeRequired)
+		bag.help("allowed: %s", strings.Join(metricTypes, ", "))
+		return
+	case !isScalarString(typ) || !contains(metricTypes, typ.Value):
+		unsupported(bag, typ, codeMetricTypeUnsupported, codeMetricTypeCase, metricTypes)
+		return
+	}
+	// источник метрики — поле, названное по type: resource, pods, ...
+	field := metricSources[typ.Value]
+	src, ok := m.get(field)
+	if !ok {
+		bag.add(n, codeMetricSourceRequired, field, typ.Value)
+		return
+	}
+	sm, node := getMap(src)
+	if sm == nil {
+		bag.add(node, codeMetricSourceType, field)
+		return
+	}
+	if typ.Value == "Resource" || typ.Value == "ContainerResource" {
+		if name, ok := sm.get("name"); !ok || !isScalarString(name) || name.Value == "" {
+			bag.add(src, codeMetricSourceField, field, "name")
+		}
+	} else if metric, ok := sm.get("metric"); !ok || metric.Kind != yaml.MappingNode {
+		bag.add(src, codeMetricSourceField, field, "metric")
+	}
+	if typ.Value == "ContainerResource" {
+		if c, ok := sm.get("container"); !ok || !isScalarString(c) || c.Value == "" {
+			bag.add(src, codeMetricSourceField, field, "container")
+		}
+	}
+
+	target, ok := sm.get("target")
+	if !ok {
+		bag.add(src, codeMetricSourceField, field, "target")
+		return
+	}
+	tm, node := getMap(target)
+	if tm == nil {
+		bag.add(node, codeMetricSourceType, "target")
+		return
+	}
+	tt, ok := tm.get("type")
+	switch {
+	case !ok:
+		bag.add(target, codeMetricSourceField, "target", "type")
+	case !isScalarString(tt) || !contains(metricTargetTypes, tt.Value):
+		unsupported(bag, tt, codeMetricTargetUnsupported, codeMetricTargetCase, metricTargetTypes)
+	default:
+		validateMetricTarget(tm, target, tt.Value, bag)
+	}
+}
+
+// validateMetricTarget — значение под type цели: averageUtilization —
+// положительный процент, value и averageValue — количество.
+func validateMetricTarget(tm fields, target *yaml.Node, typ string, bag *errBag) {
+	want := metricTargetFields[typ]
+	v, ok := tm.get(want)
+	if !ok {
+		bag.add(target, codeMetricSourceField, "target", want)
+		return
+	}
+	if typ == "Utilization" {
+		if x, err := toInt(v.Value); !isScalarInt(v) || err != nil || x < 1 {
+			bag.add(v, codeMetricTargetValue, want, v.Value)
+		}
+		return
+	}
+	if _, err := ParseQuantity(v.Value); err != nil || v.Kind != yaml.ScalarNode {
+		bag.add(v, codeMetricTargetValue, want, v.Value)
+	}
+}
@@ -0,0 +1,52 @@
+package validator
+
+import "testing"
+
+// hpa — HorizontalPodAutoscaler со строками spec (отступ 2).
+func hpa(spec string) string {
+	return "apiVersion: autoscaling/v2\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: web\nspec:\n" + spec
+}
+
+const scaleTarget = "  scaleTargetRef: {apiVersion: apps/v1, kind: Deployment, name: web}\n"
+
+// metric — HPA на 2–10 реплик с одной метрикой.
+func metric(m string) string {
+	return hpa(scaleTarget + "  minReplicas: 2\n  maxReplicas: 10\n  metrics: [" + m + "]\n")
+}
+
+func TestHPA(t *testing.T) {
+	cpu := "{type: Resource, resource: {name: cpu, target: {type: Utilization, averageUtilization: 70}}}"
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", metric(cpu), ""},
+		{"no metrics", hpa(scaleTarget + "  maxReplicas: 3\n"), ""},
+		{"spec not object", "apiVersion: autoscaling/v2\nkind: HorizontalPodAutoscaler\nmetadata:\n  name: web\nspec: web\n", codeHPASpecType},
+		{"target missing", hpa("  maxReplicas: 3\n"), codeScaleTargetRequired},
+		{"target not object", hpa("  scaleTargetRef: web\n  maxReplicas: 3\n"), codeScaleTargetType},
+		{"target name", hpa("  scaleTargetRef: {kind: Deployment}\n  maxReplicas: 3\n"), codeScaleTargetField},
+		{"target apiVersion", hpa("  scaleTargetRef: {apiVersion: [apps/v1], kind: Deployment, name: web}\n  maxReplicas: 3\n"), codeAPIVersionType},
+		{"maxReplicas missing", hpa(scaleTarget), codeMaxReplicasRequired},
+		{"minReplicas zero", hpa(scaleTarget + "  minReplicas: 0\n  maxReplicas: 3\n"), codeHPAReplicas},
+		{"maxReplicas not int", hpa(scaleTarget + "  maxReplicas: many\n"), codeHPAReplicas},
+		{"range", hpa(scaleTarget + "  minReplicas: 5\n  maxReplicas: 3\n"), codeHPAReplicasRange},
+		{"metrics not array", hpa(scaleTarget + "  maxReplicas: 3\n  metrics: " + cpu + "\n"), codeMetricsType},
+		{"metric not object", metric("cpu"), codeMetricType},
+		{"metric type missing", metric("{resource: {name: cpu}}"), codeMetricTypeRequired},
+		{"metric type unsupported", metric("{type: Custom}"), codeMetricTypeUnsupported},
+		{"metric type case", metric("{type: resource}"), codeMetricTypeCase},
+		{"source missing", metric("{type: Pods}"), codeMetricSourceRequired},
+		{"source not object", metric("{type: Pods, pods: rps}"), codeMetricSourceType},
+		{"resource name", metric("{type: Resource, resource: {target: {type: Utilization, averageUtilization: 70}}}"), codeMetricSourceField},
+		{"pods metric", metric("{type: Pods, pods: {target: {type: AverageValue, averageValue: 100}}}"), codeMetricSourceField},
+		{"container", metric("{type: ContainerResource, containerResource: {name: cpu, target: {type: Utilization, averageUtilization: 70}}}"), codeMetricSourceField},
+		{"external", metric("{type: External, external: {metric: {name: queue}, target: {type: Value, value: 30}}}"), ""},
+		{"target missing", metric("{type: Resource, resource: {name: cpu}}"), codeMetricSourceField},
+		{"target not object", metric("{type: Resource, resource: {name: cpu, target: 70}}"), codeMetricSourceType},
+		{"target type missing", metric("{type: Resource, resource: {name: cpu, target: {averageUtilization: 70}}}"), codeMetricSourceField},
+		{"target type unsupported", metric("{type: Resource, resource: {name: cpu, target: {type: Percent}}}"), codeMetricTargetUnsupported},
+		{"target type case", metric("{type: Resource, resource: {name: cpu, target: {type: utilization}}}"), codeMetricTargetCase},
+		{"target value missing", metric("{type: Resource, resource: {name: cpu, target: {type: Utilization, averageValue: 70}}}"), codeMetricSourceField},
+		{"utilization zero", metric("{type: Resource, resource: {name: cpu, target: {type: Utilization, averageUtilization: 0}}}"), codeMetricTargetValue},
+		{"quantity", metric("{type: Resource, resource: {name: memory, target: {type: AverageValue, averageValue: 512Mi}}}"), ""},
+		{"quantity invalid", metric("{type: Resource, resource: {name: memory, target: {type: AverageValue, averageValue: lots}}}"), codeMetricTargetValue},
+	})
+}
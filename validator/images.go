@@ -0,0 +1,98 @@
+// images.go
+package validator
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ImagePolicy — какие ссылки на образы допустимы: реестр, репозиторий и
+// тег. Нули — значения по умолчанию, с которыми проверка совпадает с
+// исходной: только registry.bigbrother.io и обязательный тег.
+type ImagePolicy struct {
+	// Registries — разрешённые реестры (хост[:порт]).
+	Registries []string `yaml:"registries"`
+	// Repositories — маски репозиториев в стиле path.Match, например
+	// team-a/*; пусто — любой репозиторий.
+	Repositories []string `yaml:"repositories"`
+	// Tag — регулярное выражение для тега целиком.
+	Tag string `yaml:"tag"`
+}
+
+var (
+	defaultRegistries = []string{"registry.bigbrother.io"}
+	defaultImageTag   = `[A-Za-z0-9._-]+`
+)
+
+// reImageDigest — дайджест закреплённого образа: name:tag@sha256:...
+var reImageDigest = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]{32,}$`)
+
+type imagePolicy struct {
+	registries   []string
+	repositories []string
+	tag          *regexp.Regexp
+	tagSrc       string
+}
+
+func compileImagePolicy(p ImagePolicy) (*imagePolicy, error) {
+	out := &imagePolicy{registries: p.Registries, repositories: p.Repositories, tagSrc: p.Tag}
+	if len(out.registries) == 0 {
+		out.registries = defaultRegistries
+	}
+	for _, r := range out.registries {
+		if r == "" || strings.Contains(r, "/") {
+			return nil, fmt.Errorf("registries: '%s' must be a host name", r)
+		}
+	}
+	for _, r := range out.repositories {
+		if _, err := path.Match(r, ""); err != nil {
+			return nil, fmt.Errorf("repositories: bad pattern '%s'", r)
+		}
+	}
+	if out.tagSrc == "" {
+		out.tagSrc = defaultImageTag
+	}
+	var err error
+	if out.tag, err = regexp.Compile(`^(?:` + out.tagSrc + `)$`); err != nil {
+		return nil, fmt.Errorf("tag: %v", err)
+	}
+	return out, nil
+}
+
+// check возвращает пустую строку для допустимого образа, иначе подсказку,
+// что именно не так. Ссылка — реестр/репозиторий:тег, репозиторий
+// без двоеточий; после тега может стоять @дайджест (pin-images).
+func (p *imagePolicy) check(ref string) string {
+	if i := strings.IndexByte(ref, '@'); i >= 0 {
+		if !reImageDigest.MatchString(ref[i+1:]) {
+			return "expected digest <algorithm>:<hex> after '@'"
+		}
+		ref = ref[:i]
+	}
+	registry, rest, _ := strings.Cut(ref, "/")
+	allowed := false
+	for _, r := range p.registries {
+		if r == registry {
+			allowed = true
+			break
+		}
+	}
+	if !allowed || rest == "" {
+		return "allowed registries: " + strings.Join(p.registries, ", ")
+	}
+	repo, tag, ok := strings.Cut(rest, ":")
+	if !ok || repo == "" || !p.tag.MatchString(tag) {
+		return fmt.Sprintf("expected %s/<repository>:<tag>, tag matching %s", registry, p.tagSrc)
+	}
+	if len(p.repositories) == 0 {
+		return ""
+	}
+	for _, pat := range p.repositories {
+		if ok, _ := path.Match(pat, repo); ok {
+			return ""
+		}
+	}
+	return "allowed repositories: " + strings.Join(p.repositories, ", ")
+}
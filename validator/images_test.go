@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImagePolicy(t *testing.T) {
+	digest := "@sha256:" + "0123456789abcdef0123456789abcdef"
+	tests := []struct {
+		name   string
+		policy ImagePolicy
+		ref    string
+		help   string // "" — образ допустим
+	}{
+		{"default", ImagePolicy{}, "registry.bigbrother.io/web:1.0", ""},
+		{"default pinned", ImagePolicy{}, "registry.bigbrother.io/web:1.0" + digest, ""},
+		{"bad digest", ImagePolicy{}, "registry.bigbrother.io/web:1.0@sha256:xyz", "expected digest <algorithm>:<hex> after '@'"},
+		{"other registry", ImagePolicy{}, "docker.io/nginx:1.25", "allowed registries: registry.bigbrother.io"},
+		{"no repository", ImagePolicy{}, "registry.bigbrother.io", "allowed registries: registry.bigbrother.io"},
+		{"no tag", ImagePolicy{}, "registry.bigbrother.io/web", "expected registry.bigbrother.io/<repository>:<tag>, tag matching [A-Za-z0-9._-]+"},
+		{"registry list", ImagePolicy{Registries: []string{"ghcr.io", "registry.local:5000"}}, "registry.local:5000/web:1.0", ""},
+		{"semver tag", ImagePolicy{Tag: `v\d+\.\d+\.\d+`}, "registry.bigbrother.io/web:v1.2.3", ""},
+		{"tag mismatch", ImagePolicy{Tag: `v\d+\.\d+\.\d+`}, "registry.bigbrother.io/web:latest", `expected registry.bigbrother.io/<repository>:<tag>, tag matching v\d+\.\d+\.\d+`},
+		{"repository match", ImagePolicy{Repositories: []string{"team-a/*"}}, "registry.bigbrother.io/team-a/web:1.0", ""},
+		{"repository mismatch", ImagePolicy{Repositories: []string{"team-a/*"}}, "registry.bigbrother.io/team-b/web:1.0", "allowed repositories: team-a/*"},
+	}
+	for _, tt := range tests {
+		p, err := compileImagePolicy(tt.policy)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := p.check(tt.ref); got != tt.help {
+			t.Errorf("%s: check(%q) = %q, want %q", tt.name, tt.ref, got, tt.help)
+		}
+	}
+}
+
+func TestImagePolicyFinding(t *testing.T) {
+	ghcr := strings.Replace(pod("", ""), "registry.bigbrother.io/web:1.0", "ghcr.io/org/web:1.0", 1)
+	checkRules(t, Config{Images: ImagePolicy{Registries: []string{"ghcr.io"}}}, []ruleCase{
+		{"allowed", ghcr, ""},
+		{"default registry not allowed", pod("", ""), codeImageFormat},
+	})
+}
+
+func TestImagePolicyConfig(t *testing.T) {
+	for name, p := range map[string]ImagePolicy{
+		"registry with path": {Registries: []string{"ghcr.io/org"}},
+		"empty registry":     {Registries: []string{""}},
+		"repository pattern": {Repositories: []string{"team-[a"}},
+		"tag regexp":         {Tag: "v("},
+	} {
+		if _, err := New(Config{Images: p}); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
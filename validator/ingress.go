@@ -0,0 +1,220 @@
+// ingress.go
+package validator
+
+import (
+	"net"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var pathTypes = []string{"Exact", "ImplementationSpecific", "Prefix"}
+
+func validateIngressSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIngressSpecType)
+		return
+	}
+	if cls, ok := m.get("ingressClassName"); ok {
+		if !isScalarString(cls) || !reDNSSubdomain.MatchString(cls.Value) {
+			bag.add(cls, codeIngressClassFormat, cls.Value)
+		}
+	}
+
+	// без defaultBackend и rules Ingress ничего не маршрутизирует
+	def, hasDefault := m.get("defaultBackend")
+	if hasDefault {
+		validateIngressBackend(def, bag)
+	}
+	rules, ok := m.get("rules")
+	switch {
+	case !ok:
+		if !hasDefault {
+			bag.add(nil, codeIngressRulesRequired)
+		}
+	case rules.Kind != yaml.SequenceNode:
+		bag.add(rules, codeIngressRulesType)
+	default:
+		for _, r := range rules.Content {
+			validateIngressRule(r, bag)
+		}
+	}
+
+	if tls, ok := m.get("tls"); ok {
+		if tls.Kind != yaml.SequenceNode {
+			bag.add(tls, codeIngressTLSType)
+		} else {
+			for _, t := range tls.Content {
+				validateIngressTLS(t, bag)
+			}
+		}
+	}
+}
+
+// validIngressHost — DNS-имя, допускается одна звёздочка первой меткой
+// (*.example.com); IP-адрес хостом быть не может.
+func validIngressHost(s string) bool {
+	if net.ParseIP(s) != nil {
+		return false
+	}
+	return len(s) <= 253 && reDNSSubdomain.MatchString(strings.TrimPrefix(s, "*."))
+}
+
+func validateIngressRule(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIngressRuleType)
+		return
+	}
+	if host, ok := m.get("host"); ok {
+		if !isScalarString(host) || !validIngressHost(host.Value) {
+			bag.add(host, codeIngressHostFormat, host.Value)
+			if net.ParseIP(host.Value) != nil {
+				bag.help("host must be DNS name, not IP address")
+			}
+		}
+	}
+	// rule без http — только host, трафик уходит в defaultBackend
+	http, ok := m.get("http")
+	if !ok {
+		return
+	}
+	hm, node := getMap(http)
+	if hm == nil {
+		bag.add(node, codeIngressHTTPType)
+		return
+	}
+	paths, ok := hm.get("paths")
+	switch {
+	case !ok:
+		bag.add(nil, codeIngressPathsRequired)
+	case paths.Kind != yaml.SequenceNode || len(paths.Content) == 0:
+		bag.add(paths, codeIngressPathsType)
+	default:
+		for _, p := range paths.Content {
+			validateIngressPath(p, bag)
+		}
+	}
+}
+
+func validateIngressPath(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIngressPathType)
+		return
+	}
+	// pathType (required): у Exact и Prefix путь абсолютный
+	pt, ok := m.get("pathType")
+	switch {
+	case !ok:
+		bag.add(n, codePathTypeRequired)
+		bag.help("allowed: %s", strings.Join(pathTypes, ", "))
+	case !isScalarString(pt) || !contains(pathTypes, pt.Value):
+		unsupported(bag, pt, codePathTypeUnsupported, codePathTypeCase, pathTypes)
+		pt = nil
+	}
+	if p, ok := m.get("path"); ok {
+		switch {
+		case !isScalarString(p):
+			bag.add(p, codeIngressPathFormat, p.Value)
+		case pt != nil && pt.Value != "ImplementationSpecific" && !strings.HasPrefix(p.Value, "/"):
+			bag.add(p, codeIngressPathFormat, p.Value)
+			bag.help("path must be absolute with pathType %s", pt.Value)
+		}
+	}
+	if b, ok := m.get("backend"); !ok {
+		bag.add(nil, codeIngressBackendRequired)
+	} else {
+		validateIngressBackend(b, bag)
+	}
+}
+
+// validateIngressBackend — ровно один из service и resource.
+func validateIngressBackend(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIngressBackendType)
+		return
+	}
+	svc, hasService := m.get("service")
+	res, hasResource := m.get("resource")
+	switch {
+	case hasService && hasResource:
+		bag.add(res, codeIngressBackendOneOf)
+		return
+	case !hasService && !hasResource:
+		bag.add(n, codeIngressBackendOneOf)
+		return
+	case hasResource:
+		rm, node := getMap(res)
+		if rm == nil {
+			bag.add(node, codeIngressBackendType)
+			return
+		}
+		for _, field := range []string{"kind", "name"} {
+			if v, ok := rm.get(field); !ok || !isScalarString(v) || v.Value == "" {
+				bag.add(res, codeIngressBackendField, "resource."+field)
+			}
+		}
+		return
+	}
+
+	sm, node := getMap(svc)
+	if sm == nil {
+		bag.add(node, codeIngressBackendType)
+		return
+	}
+	if name, ok := sm.get("name"); !ok {
+		bag.add(svc, codeIngressBackendField, "service.name")
+	} else if !isScalarString(name) || !reDNSLabel.MatchString(name.Value) {
+		bag.add(name, codeIngressServiceName, name.Value)
+	}
+	// port: number или name, не оба
+	port, ok := sm.get("port")
+	if !ok {
+		bag.add(svc, codeIngressBackendField, "service.port")
+		return
+	}
+	pm, node := getMap(port)
+	if pm == nil {
+		bag.add(node, codeIngressServicePort)
+		return
+	}
+	num, hasNumber := pm.get("number")
+	name, hasName := pm.get("name")
+	switch {
+	case hasNumber == hasName:
+		bag.add(port, codeIngressServicePort)
+	case hasNumber:
+		validatePort(num, bag, "number")
+	case !isScalarString(name) || !validPortName(name.Value):
+		bag.add(name, codeIngressPortName, name.Value)
+	}
+}
+
+func validateIngressTLS(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIngressTLSItemType)
+		return
+	}
+	if hosts, ok := m.get("hosts"); ok {
+		if hosts.Kind != yaml.SequenceNode {
+			bag.add(hosts, codeIngressTLSHostsType)
+		} else {
+			for _, h := range hosts.Content {
+				if !isScalarString(h) || !validIngressHost(h.Value) {
+					bag.add(h, codeIngressHostFormat, h.Value)
+				}
+			}
+		}
+	}
+	// secretName (optional): без него контроллер берёт сертификат по
+	// умолчанию
+	if s, ok := m.get("secretName"); ok {
+		if !isScalarString(s) || !reDNSSubdomain.MatchString(s.Value) {
+			bag.add(s, codeIngressSecretName, s.Value)
+		}
+	}
+}
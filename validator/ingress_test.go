@@ -0,0 +1,64 @@
+package validator
+
+import "testing"
+
+// ingress — Ingress со строками spec (отступ 2).
+func ingress(spec string) string {
+	return "apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: shop\nspec:\n" + spec
+}
+
+const webBackend = "{service: {name: web, port: {number: 80}}}"
+
+// ingressPath — правило для shop.example.com с одним элементом paths.
+func ingressPath(path string) string {
+	return ingress("  rules:\n    - host: shop.example.com\n      http:\n        paths: [" + path + "]\n")
+}
+
+// ingressBackend — путь / с бэкендом backend.
+func ingressBackend(backend string) string {
+	return ingressPath("{path: /, pathType: Prefix, backend: " + backend + "}")
+}
+
+func TestIngress(t *testing.T) {
+	rule := func(r string) string { return ingress("  rules: [" + r + "]\n") }
+	tls := func(s string) string { return ingress("  defaultBackend: " + webBackend + "\n  tls: " + s + "\n") }
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", ingressBackend(webBackend), ""},
+		{"default backend only", ingress("  ingressClassName: nginx\n  defaultBackend: " + webBackend + "\n"), ""},
+		{"spec not object", "apiVersion: networking.k8s.io/v1\nkind: Ingress\nmetadata:\n  name: shop\nspec: web\n", codeIngressSpecType},
+		{"class format", ingress("  ingressClassName: Nginx_Public\n  defaultBackend: " + webBackend + "\n"), codeIngressClassFormat},
+		{"nothing routed", ingress("  ingressClassName: nginx\n"), codeIngressRulesRequired},
+		{"rules not array", ingress("  rules: {host: shop.example.com}\n"), codeIngressRulesType},
+		{"rule not object", rule("shop.example.com"), codeIngressRuleType},
+		{"host only", rule("{host: \"*.example.com\"}") + "  defaultBackend: " + webBackend + "\n", ""},
+		{"host format", rule("{host: Shop_Example}"), codeIngressHostFormat},
+		{"host IP", rule("{host: 10.0.0.1}"), codeIngressHostFormat},
+		{"http not object", rule("{http: [a]}"), codeIngressHTTPType},
+		{"paths missing", rule("{http: {}}"), codeIngressPathsRequired},
+		{"paths empty", rule("{http: {paths: []}}"), codeIngressPathsType},
+		{"path not object", ingressPath("/"), codeIngressPathType},
+		{"pathType missing", ingressPath("{path: /, backend: " + webBackend + "}"), codePathTypeRequired},
+		{"pathType unsupported", ingressPath("{path: /, pathType: Regex, backend: " + webBackend + "}"), codePathTypeUnsupported},
+		{"pathType case", ingressPath("{path: /, pathType: prefix, backend: " + webBackend + "}"), codePathTypeCase},
+		{"relative path", ingressPath("{path: api, pathType: Exact, backend: " + webBackend + "}"), codeIngressPathFormat},
+		{"relative implementation specific", ingressPath("{path: api, pathType: ImplementationSpecific, backend: " + webBackend + "}"), ""},
+		{"backend missing", ingressPath("{path: /, pathType: Prefix}"), codeIngressBackendRequired},
+		{"backend not object", ingressBackend("web"), codeIngressBackendType},
+		{"backend both", ingressBackend("{service: {name: web, port: {number: 80}}, resource: {kind: Bucket, name: static}}"), codeIngressBackendOneOf},
+		{"backend neither", ingressBackend("{}"), codeIngressBackendOneOf},
+		{"resource", ingressBackend("{resource: {apiGroup: k8s.example.com, kind: Bucket, name: static}}"), ""},
+		{"resource name", ingressBackend("{resource: {kind: Bucket}}"), codeIngressBackendField},
+		{"service port missing", ingressBackend("{service: {name: web}}"), codeIngressBackendField},
+		{"service name format", ingressBackend("{service: {name: Web.Svc, port: {number: 80}}}"), codeIngressServiceName},
+		{"service port both", ingressBackend("{service: {name: web, port: {number: 80, name: http}}}"), codeIngressServicePort},
+		{"service port name", ingressBackend("{service: {name: web, port: {name: http}}}"), ""},
+		{"service port name format", ingressBackend("{service: {name: web, port: {name: HTTP_PORT}}}"), codeIngressPortName},
+		{"service port number", ingressBackend("{service: {name: web, port: {number: 70000}}}"), codePortTooLarge},
+		{"tls", tls("[{hosts: [shop.example.com], secretName: shop-tls}]"), ""},
+		{"tls not array", tls("{secretName: shop-tls}"), codeIngressTLSType},
+		{"tls item not object", tls("[shop-tls]"), codeIngressTLSItemType},
+		{"tls hosts not array", tls("[{hosts: shop.example.com}]"), codeIngressTLSHostsType},
+		{"tls host format", tls("[{hosts: [\"shop example\"]}]"), codeIngressHostFormat},
+		{"tls secretName", tls("[{secretName: Shop_TLS}]"), codeIngressSecretName},
+	})
+}
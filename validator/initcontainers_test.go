@@ -0,0 +1,23 @@
+package validator
+
+import "testing"
+
+// initContainer — spec пода с одним initContainer name и строками
+// контейнера fields (отступ 6).
+func initContainer(name, fields string) string {
+	return "  initContainers:\n    - name: " + name + "\n" + fields
+}
+
+const initImage = "      image: registry.bigbrother.io/migrate:1.0\n" +
+	"      resources: {requests: {cpu: 1, memory: 64Mi}, limits: {cpu: 1, memory: 64Mi}}\n"
+
+func TestInitContainers(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", pod("", initContainer("migrate", initImage)), ""},
+		{"empty", pod("", "  initContainers: []\n"), ""},
+		{"not array", pod("", "  initContainers: {name: migrate}\n"), codeInitContainersType},
+		{"container rules", pod("", initContainer("migrate", "")), codeImageRequired},
+		{"port rules", pod("", initContainer("migrate", initImage+"      ports: [{containerPort: 70000}]\n")), codePortTooLarge},
+		{"name shared with containers", pod("", initContainer("web", initImage)), codeContainerNameDup},
+	})
+}
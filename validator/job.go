@@ -0,0 +1,56 @@
+// job.go
+package validator
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// jobRestartPolicies — Always у Job запрещён: контроллер сам
+// перезапускает упавшие поды по backoffLimit.
+var jobRestartPolicies = []string{"Never", "OnFailure"}
+
+func validateJobSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeWorkloadSpecType)
+		return
+	}
+	for _, field := range []string{"backoffLimit", "completions", "parallelism"} {
+		if v, ok := m.get(field); ok {
+			if x, err := toInt(v.Value); !isScalarInt(v) || err != nil || x < 0 {
+				bag.add(v, codeJobCount, field)
+			}
+		}
+	}
+
+	// selector у Job необязателен: контроллер генерирует его сам
+	sel, hasSelector := m.get("selector")
+	var selector labelSelector
+	if hasSelector {
+		selector = validateLabelSelector(sel, bag)
+	}
+	labels := validatePodTemplate(m, bag)
+	if selector != nil && labels != nil && !selector.matches(labels) {
+		bag.add(sel, codeSelectorMismatch)
+	}
+
+	// restartPolicy шаблона: по умолчанию Always, а он Job'у не подходит
+	tmpl, _ := m.get("template")
+	if tmpl == nil {
+		return
+	}
+	spec, _ := child(tmpl, "spec")
+	if spec == nil || spec.Kind != yaml.MappingNode {
+		return
+	}
+	rp, ok := child(spec, "restartPolicy")
+	switch {
+	case !ok:
+		bag.add(spec, codeRestartPolicyRequired)
+		bag.help("allowed: %s", strings.Join(jobRestartPolicies, ", "))
+	case !isScalarString(rp) || !contains(jobRestartPolicies, rp.Value):
+		unsupported(bag, rp, codeRestartPolicy, codeRestartPolicyCase, jobRestartPolicies)
+	}
+}
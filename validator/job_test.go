@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// jobTemplate — шаблон пода с restartPolicy policy; "" — без поля.
+func jobTemplate(policy string) string {
+	if policy == "" {
+		return webTemplate
+	}
+	return strings.Replace(webTemplate, "    spec:\n", "    spec:\n      restartPolicy: "+policy+"\n", 1)
+}
+
+// job — Job со строками spec и шаблоном с restartPolicy policy.
+func job(spec, policy string) string {
+	return "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\nspec:\n" + spec + jobTemplate(policy)
+}
+
+func TestJob(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", job("  backoffLimit: 3\n  completions: 5\n  parallelism: 2\n", "Never"), ""},
+		{"selector matches", job(webSelector, "OnFailure"), ""},
+		{"selector mismatch", job("  selector: {matchLabels: {app: api}}\n", "Never"), codeSelectorMismatch},
+		{"count negative", job("  backoffLimit: -1\n", "Never"), codeJobCount},
+		{"count not int", job("  parallelism: two\n", "Never"), codeJobCount},
+		{"template missing", "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\nspec:\n  backoffLimit: 3\n", codeTemplateRequired},
+		{"restartPolicy missing", job("", ""), codeRestartPolicyRequired},
+		{"restartPolicy Always", job("", "Always"), codeRestartPolicy},
+		{"restartPolicy case", job("", "never"), codeRestartPolicyCase},
+	})
+}
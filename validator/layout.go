@@ -0,0 +1,188 @@
+// layout.go
+package validator
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- file name ----------
+
+// reTemplateVar — подстановки в шаблонах путей: {name}, {namespace}, {kind}.
+var reTemplateVar = regexp.MustCompile(`\{[^{}]*\}`)
+
+var templateVars = map[string]bool{"{name}": true, "{namespace}": true, "{kind}": true}
+
+// compileTemplate проверяет, что в шаблоне только известные подстановки.
+func compileTemplate(tmpl string) error {
+	for _, v := range reTemplateVar.FindAllString(tmpl, -1) {
+		if !templateVars[v] {
+			return fmt.Errorf("unknown placeholder '%s' in '%s'", v, tmpl)
+		}
+	}
+	return nil
+}
+
+// templateVarsOf подставляет поля документа; namespace по умолчанию —
+// default, kind приводится к нижнему регистру, как принято в именах
+// файлов. Второе значение — узел metadata.name для номера строки, nil,
+// если имени нет: об этом сообщат проверки metadata.
+func templateVarsOf(doc *yaml.Node) (*strings.Replacer, *yaml.Node) {
+	var name *yaml.Node
+	namespace, kind := "default", ""
+	if meta, _ := child(doc, "metadata"); meta != nil {
+		if n, _ := child(meta, "name"); n != nil && isScalarString(n) {
+			name = n
+		}
+		if ns, _ := child(meta, "namespace"); ns != nil && isScalarString(ns) && ns.Value != "" {
+			namespace = ns.Value
+		}
+	}
+	if k, _ := child(doc, "kind"); k != nil && isScalarString(k) {
+		kind = strings.ToLower(k.Value)
+	}
+	nameValue := ""
+	if name != nil {
+		nameValue = name.Value
+	}
+	return strings.NewReplacer("{name}", nameValue, "{namespace}", namespace, "{kind}", kind), name
+}
+
+// checkFileName сверяет имя файла с шаблоном. В файле из нескольких
+// документов достаточно совпадения с любым из них: обычно файл называют
+// по главному объекту, а рядом лежат его Service и ConfigMap.
+func checkFileName(path string, docs []*yaml.Node, bag *errBag) {
+	tmpl := bag.rules.fileName
+	if tmpl == "" {
+		return
+	}
+	base := filepath.Base(path)
+	var want string
+	var at *yaml.Node
+	for _, doc := range docs {
+		vars, name := templateVarsOf(doc)
+		if name == nil {
+			continue
+		}
+		expected := vars.Replace(tmpl)
+		if expected == base {
+			return
+		}
+		if want == "" {
+			want, at = expected, name
+		}
+	}
+	if want != "" {
+		bag.add(at, codeFileName, base, want)
+	}
+}
+
+// ---------- directory layout ----------
+
+// compileLayout делит шаблоны каталогов на сегменты и проверяет их
+// синтаксис заранее, чтобы опечатка в конфиге не превращалась в находки
+// на каждом файле.
+func compileLayout(templates []string) ([][]string, error) {
+	out := make([][]string, 0, len(templates))
+	for _, tmpl := range templates {
+		if err := compileTemplate(tmpl); err != nil {
+			return nil, err
+		}
+		segs := splitPath(tmpl)
+		for _, seg := range segs {
+			if _, err := path.Match(reTemplateVar.ReplaceAllString(seg, "x"), ""); err != nil {
+				return nil, fmt.Errorf("bad pattern '%s' in '%s'", seg, tmpl)
+			}
+		}
+		out = append(out, segs)
+	}
+	return out, nil
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(path.Clean(filepath.ToSlash(p)), "/")
+	if p == "." || p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// checkLayout проверяет каталог файла по шаблонам layout: каждый документ
+// должен подойти хотя бы под один. Шаблон сравнивается с концом пути,
+// поэтому одинаково работают абсолютные и относительные пути.
+func checkLayout(file string, docs []*yaml.Node, bag *errBag) {
+	layout := bag.rules.layout
+	if len(layout) == 0 {
+		return
+	}
+	dir := splitPath(filepath.Dir(file))
+	for _, doc := range docs {
+		if isNullDocument(doc) || !looksLikeManifest(doc) {
+			continue
+		}
+		matched := false
+		for _, tmpl := range layout {
+			if matchLayout(tmpl, dir, doc) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			bag.add(layoutNode(doc), codeLayout, filepath.ToSlash(filepath.Dir(file)), strings.Join(bag.rules.layoutSrc, ", "))
+		}
+	}
+}
+
+// matchLayout ищет суффикс dir, подходящий под шаблон.
+func matchLayout(tmpl, dir []string, doc *yaml.Node) bool {
+	vars, _ := templateVarsOf(doc)
+	segs := make([]string, len(tmpl))
+	for i, seg := range tmpl {
+		segs[i] = vars.Replace(seg)
+	}
+	for start := 0; start <= len(dir); start++ {
+		if matchSegments(segs, dir[start:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments сопоставляет сегменты как path.Match, ** — любое число
+// каталогов, в том числе ноль.
+func matchSegments(segs, dir []string) bool {
+	if len(segs) == 0 {
+		return len(dir) == 0
+	}
+	if segs[0] == "**" {
+		for i := 0; i <= len(dir); i++ {
+			if matchSegments(segs[1:], dir[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(dir) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segs[0], dir[0]); !ok {
+		return false
+	}
+	return matchSegments(segs[1:], dir[1:])
+}
+
+// layoutNode — узел, к которому привязать находку: namespace, если он
+// задан, иначе начало документа.
+func layoutNode(doc *yaml.Node) *yaml.Node {
+	if meta, _ := child(doc, "metadata"); meta != nil {
+		if ns, _ := child(meta, "namespace"); ns != nil {
+			return ns
+		}
+	}
+	return doc
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFileName(t *testing.T) {
+	api := strings.Replace(pod("", ""), "  name: web\nspec", "  name: api\nspec", 1)
+	cfg := Config{FileName: "{name}-{kind}.yaml"}
+	checkRulesAt(t, cfg, "k8s/web-pod.yaml", []ruleCase{
+		{"matches", pod("", ""), ""},
+		{"other name", api, codeFileName},
+		// достаточно совпадения с любым документом файла
+		{"any document", api + "---\n" + pod("", ""), ""},
+	})
+	checkRulesAt(t, cfg, "k8s/api.yaml", []ruleCase{{"mismatch", pod("", ""), codeFileName}})
+	// без metadata.name сравнивать не с чем
+	checkRulesAt(t, Config{FileName: "{name}.yaml"}, "k8s/app.yaml", []ruleCase{
+		{"no name", "apiVersion: v1\nkind: ConfigMap\nmetadata: {}\n", codeNameRequired},
+	})
+}
+
+func TestLayout(t *testing.T) {
+	cfg := Config{Layout: []string{"apps/{namespace}/**", "base"}}
+	withNamespace := strings.Replace(pod("", ""), "  name: web\n", "  name: web\n  namespace: shop\n", 1)
+	checkRulesAt(t, cfg, "repo/apps/shop/web/pod.yaml", []ruleCase{
+		{"namespace dir", withNamespace, ""},
+		{"default namespace", pod("", ""), codeLayout},
+	})
+	checkRulesAt(t, cfg, "repo/base/pod.yaml", []ruleCase{{"literal dir", pod("", ""), ""}})
+	checkRulesAt(t, cfg, "repo/misc/pod.yaml", []ruleCase{{"outside layout", withNamespace, codeLayout}})
+}
+
+func TestLayoutConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"file name placeholder", Config{FileName: "{app}.yaml"}},
+		{"layout placeholder", Config{Layout: []string{"apps/{team}"}}},
+		{"layout pattern", Config{Layout: []string{"apps/[a-"}}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.cfg); err == nil {
+			t.Errorf("%s: config accepted", tt.name)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// limits.go
+package validator
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Лимиты kube-apiserver и etcd. Превышение обнаруживается только при apply,
+// поэтому предупреждаем заранее.
+const (
+	maxObjectBytes      = 3 << 19 // 1.5MiB — лимит запроса etcd по умолчанию
+	maxAnnotationsBytes = 256 << 10
+	maxNameLen          = 253 // DNS-1123 subdomain
+	maxNamespaceLen     = 63  // DNS-1123 label
+	maxLabelValueLen    = 63
+	maxLabelNameLen     = 63
+	maxLabelPrefixLen   = 253
+)
+
+func validateSizeLimits(ctx *ValidationContext, bag *errBag) {
+	doc := ctx.Doc
+	// etcd хранит объект сериализованным, поэтому меряем JSON, а не YAML
+	if size := jsonSize(doc); size > maxObjectBytes {
+		bag.warn(doc, codeObjectSize, size, maxObjectBytes)
+	}
+
+	meta, ok := child(doc, "metadata")
+	if !ok || meta.Kind != yaml.MappingNode {
+		return
+	}
+	if name, ok := child(meta, "name"); ok && isScalarString(name) && len(name.Value) > maxNameLen {
+		bag.warn(name, codeNameLength, maxNameLen)
+	}
+	if ns, ok := child(meta, "namespace"); ok && isScalarString(ns) && len(ns.Value) > maxNamespaceLen {
+		bag.warn(ns, codeNamespaceLength, maxNamespaceLen)
+	}
+	if labels, ok := child(meta, "labels"); ok && labels.Kind == yaml.MappingNode {
+		for i := 0; i < len(labels.Content); i += 2 {
+			k, v := labels.Content[i], labels.Content[i+1]
+			prefix, name := splitLabelKey(k.Value)
+			if len(prefix) > maxLabelPrefixLen || len(name) > maxLabelNameLen {
+				bag.warn(k, codeLabelKeyLength, k.Value)
+			}
+			if len(v.Value) > maxLabelValueLen {
+				bag.warn(v, codeLabelValueLen, k.Value, maxLabelValueLen)
+			}
+		}
+	}
+	if ann, ok := child(meta, "annotations"); ok && ann.Kind == yaml.MappingNode {
+		total := 0
+		for i := 0; i < len(ann.Content); i += 2 {
+			total += len(ann.Content[i].Value) + len(ann.Content[i+1].Value)
+		}
+		if total > maxAnnotationsBytes {
+			bag.warn(ann, codeAnnotationsSize, total, maxAnnotationsBytes)
+		}
+	}
+}
+
+// jsonSize — длина компактного JSON для узла, посчитанная обходом дерева
+// без декодирования и сериализации: проверка идёт на каждом документе,
+// а аллокации на валидных документах нам не нужны.
+func jsonSize(n *yaml.Node) int {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return 4 // null
+		}
+		return jsonSize(n.Content[0])
+	case yaml.AliasNode:
+		return jsonSize(n.Alias)
+	case yaml.MappingNode, yaml.SequenceNode:
+		size := 2 // {} или []
+		for i, c := range n.Content {
+			if i > 0 {
+				size++ // , или :
+			}
+			if n.Kind == yaml.MappingNode && i%2 == 0 {
+				size += jsonStringSize(c.Value)
+				continue
+			}
+			size += jsonSize(c)
+		}
+		return size
+	default:
+		switch n.Tag {
+		case "!!null":
+			return 4
+		case "!!int", "!!float", "!!bool":
+			return len(n.Value)
+		}
+		return jsonStringSize(n.Value)
+	}
+}
+
+func jsonStringSize(s string) int {
+	size := 2
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"', c == '\\', c == '\n', c == '\r', c == '\t':
+			size += 2
+		case c < 0x20, c == '<', c == '>', c == '&':
+			size += 6 // \u00XX, encoding/json экранирует и HTML-символы
+		default:
+			size++
+		}
+	}
+	return size
+}
+
+// splitLabelKey делит ключ метки на необязательный префикс и имя.
+func splitLabelKey(key string) (prefix, name string) {
+	if p, n, ok := strings.Cut(key, "/"); ok {
+		return p, n
+	}
+	return "", key
+}
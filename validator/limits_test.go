@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestSizeLimits(t *testing.T) {
+	meta := func(field string) string {
+		return strings.Replace(pod("", ""), "  name: web\n", "  name: web\n"+field, 1)
+	}
+	long := strings.Repeat("a", 254)
+	checkRules(t, Config{}, []ruleCase{
+		{"name", strings.Replace(pod("", ""), "name: web\n", "name: "+long+"\n", 1), codeNameLength},
+		{"namespace", meta("  namespace: " + long[:64] + "\n"), codeNamespaceLength},
+		{"label key", meta("  labels:\n    example.com/" + long[:64] + ": x\n"), codeLabelKeyLength},
+		{"label prefix", meta("  labels:\n    " + long + "/name: x\n"), codeLabelKeyLength},
+		{"label value", meta("  labels:\n    app: " + long[:64] + "\n"), codeLabelValueLen},
+		{"at limits", meta("  namespace: " + long[:63] + "\n  labels:\n    app: " + long[:63] + "\n"), ""},
+	})
+}
+
+// Превышения — предупреждения: прогон из-за них не падает.
+func TestSizeLimitsWarn(t *testing.T) {
+	big := strings.Replace(pod("", ""), "  name: web\n", "  name: web\n  annotations:\n    note: "+strings.Repeat("a", 3<<19)+"\n", 1)
+	res := validate(t, Config{}, big)
+	if len(res.Issues) != 2 {
+		t.Fatalf("codes %q, want object and annotations size", codes(res))
+	}
+	for _, is := range res.Issues {
+		if is.Severity != SeverityWarning {
+			t.Errorf("%q is not a warning", is.Message())
+		}
+	}
+}
+
+// jsonSize обязан совпадать с длиной encoding/json, иначе предупреждение
+// о размере объекта сработает не там.
+func TestJSONSize(t *testing.T) {
+	docs := []string{
+		"a: 1\nb: [true, null, 1.5]\nc: {d: \"x<y>&z\"}\n",
+		"s: \"tab\\there\\nquote\\\" back\\\\slash \\x01\"\n",
+		"base: &b {x: 1}\nref: *b\n",
+		"empty: {}\nlist: []\nnone:\n",
+	}
+	for _, src := range docs {
+		var n yaml.Node
+		if err := yaml.Unmarshal([]byte(src), &n); err != nil {
+			t.Fatal(err)
+		}
+		var v any
+		if err := n.Decode(&v); err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := json.NewEncoder(&buf).Encode(v); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := jsonSize(&n), buf.Len()-1; got != want {
+			t.Errorf("jsonSize(%q) = %d, encoding/json %d", src, got, want)
+		}
+	}
+}
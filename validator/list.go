@@ -0,0 +1,60 @@
+// list.go
+package validator
+
+import (
+	yaml "gopkg.in/yaml.v3"
+)
+
+// isList — документ kind: List, как его выдают kubectl get -o yaml и
+// генераторы: объекты лежат в items.
+func isList(doc *yaml.Node) bool {
+	return kindOf(doc) == "List"
+}
+
+// validateList проверяет обёртку List и каждый элемент items как
+// отдельный документ; находки элемента помечены его номером.
+func (v *Validator) validateList(doc *yaml.Node, scanned bool, groupWorkers int) *errBag {
+	bag := &errBag{rules: v.rules}
+	if api, ok := child(doc, "apiVersion"); !ok {
+		bag.add(nil, codeAPIVersionRequired)
+	} else if !isScalarString(api) {
+		bag.add(api, codeAPIVersionType)
+	} else if api.Value != "v1" {
+		bag.add(api, codeAPIVersionUnsupported, api.Value)
+	}
+	items, ok := child(doc, "items")
+	switch {
+	case !ok:
+		bag.add(nil, codeListItemsRequired)
+		return bag
+	case items.Kind != yaml.SequenceNode:
+		bag.add(items, codeListItemsType)
+		return bag
+	}
+	for i, item := range items.Content {
+		b := v.validateDocument(item, scanned, groupWorkers)
+		for j := range b.list {
+			b.list[j].Item = i + 1
+		}
+		for j := range b.facts {
+			b.facts[j].Item = i + 1
+		}
+		bag.merge(b)
+	}
+	return bag
+}
+
+// expandLists — документы с элементами List вместо самих List: правила
+// уровня файла считают объекты, а не обёртки.
+func expandLists(docs []*yaml.Node) []*yaml.Node {
+	out := make([]*yaml.Node, 0, len(docs))
+	for _, doc := range docs {
+		items, _ := child(doc, "items")
+		if isList(doc) && items != nil && items.Kind == yaml.SequenceNode {
+			out = append(out, items.Content...)
+			continue
+		}
+		out = append(out, doc)
+	}
+	return out
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// list — kind: List с элементами items (отступ 2).
+func list(items string) string {
+	return "apiVersion: v1\nkind: List\nitems:\n" + items
+}
+
+// listItem — документ doc элементом items.
+func listItem(doc string) string {
+	pad := "  "
+	lines := strings.Split(strings.TrimSuffix(doc, "\n"), "\n")
+	for i := range lines {
+		if i == 0 {
+			lines[i] = pad + "- " + lines[i]
+		} else {
+			lines[i] = pad + "  " + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func TestList(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", list(listItem(pod("", "")) + listItem(configMap("data: {a: b}\n"))), ""},
+		{"empty", list("  []\n"), ""},
+		{"items missing", "apiVersion: v1\nkind: List\n", codeListItemsRequired},
+		{"items not array", list("  pod: web\n"), codeListItemsType},
+		{"apiVersion", strings.Replace(list("  []\n"), "v1", "apps/v1", 1), codeAPIVersionUnsupported},
+		{"item rules", list(listItem(pod("      ports: [{containerPort: 70000}]\n", ""))), codePortTooLarge},
+	})
+}
+
+// Находка элемента помечена его номером.
+func TestListItem(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := list(listItem(pod("", "")) + listItem(pod("      ports: [{containerPort: 70000}]\n", "")))
+	res, err := v.Validate([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	is := findIssue(res, codePortTooLarge)
+	if is == nil {
+		t.Fatalf("no %s in %s", codePortTooLarge, codes(res))
+	}
+	if is.Item != 2 || is.Line == 0 {
+		t.Errorf("item %d line %d, want item 2 with line", is.Item, is.Line)
+	}
+}
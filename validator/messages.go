@@ -0,0 +1,789 @@
+// messages.go
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Коды находок. Код определяет шаблон сообщения, а аргументы хранятся
+// в Issue и подставляются только при выводе: для подавленных и
+// задублированных находок fmt.Sprintf не вызывается вовсе.
+const (
+	// объект целиком
+	codeRootType              = "OBJ001"
+	codeAPIVersionRequired    = "OBJ002"
+	codeAPIVersionType        = "OBJ003"
+	codeAPIVersionUnsupported = "OBJ004"
+	codeKindRequired          = "OBJ005"
+	codeKindType              = "OBJ006"
+	codeKindUnsupported       = "OBJ007"
+	codeMetadataRequired      = "OBJ008"
+	codeSpecRequired          = "OBJ009"
+	codeAPIVersionCase        = "OBJ010"
+	codeKindCase              = "OBJ011"
+
+	// metadata
+	codeMetadataType = "MET001"
+	codeNameRequired = "MET002"
+	codeNameType     = "MET003"
+	codeNamespace    = "MET004"
+	codeLabelsType   = "MET005"
+
+	// spec пода
+	codeSpecType            = "POD001"
+	codeContainersRequired  = "POD002"
+	codeContainersType      = "POD003"
+	codeContainersEmpty     = "POD004"
+	codeContainerNameDup    = "POD005"
+	codeOSType              = "POD006"
+	codeOSUnsupported       = "POD007"
+	codeOSNameRequired      = "POD008"
+	codeOSNameType          = "POD009"
+	codePriorityClassType   = "POD010"
+	codePriorityClassFormat = "POD011"
+	codeOSCase              = "POD012"
+	codeInitContainersType  = "POD013"
+	codeContainerType       = "CNT001"
+	codeContainerNameReq    = "CNT002"
+	codeContainerNameType   = "CNT003"
+	codeContainerNameFormat = "CNT004"
+	codeImageRequired       = "CNT005"
+	codeImageType           = "CNT006"
+	codeImageFormat         = "CNT007"
+	codePortsType           = "CNT008"
+	codeResourcesRequired   = "CNT009"
+
+	// порты: «не число», «не положительное» и «больше 65535» различаются,
+	// чтобы их можно было отфильтровать по отдельности
+	codePortNotInt          = "PRT001"
+	codePortNotPositive     = "PRT002"
+	codePortTooLarge        = "PRT003"
+	codePortItemType        = "PRT004"
+	codeContainerPortReq    = "PRT005"
+	codeProtocolType        = "PRT006"
+	codeProtocolUnsupported = "PRT007"
+	codeProtocolCase        = "PRT008"
+
+	// пробы
+	codeProbeType       = "PRB001"
+	codeHTTPGetRequired = "PRB002"
+	codeHTTPGetType     = "PRB003"
+	codePathRequired    = "PRB004"
+	codePathType        = "PRB005"
+	codePathFormat      = "PRB006"
+	codeProbePortReq    = "PRB007"
+	codeProbeTimeout    = "PRB008"
+
+	// ресурсы
+	codeResourcesType = "RES001"
+	codeResourceMap   = "RES002"
+	codeCPUType       = "RES003"
+	codeMemoryType    = "RES004"
+	codeMemoryFormat  = "RES005"
+
+	// env контейнера
+	codeEnvType                = "ENV001"
+	codeEnvItemType            = "ENV002"
+	codeEnvNameRequired        = "ENV003"
+	codeEnvNameFormat          = "ENV004"
+	codeEnvValueExclusive      = "ENV005"
+	codeEnvValueType           = "ENV006"
+	codeValueFromType          = "ENV007"
+	codeValueFromSource        = "ENV008"
+	codeEnvRefType             = "ENV009"
+	codeEnvRefField            = "ENV010"
+	codeEnvRefFieldType        = "ENV011"
+	codeEnvResourceUnsupported = "ENV012"
+	codeEnvResourceCase        = "ENV013"
+	codeEnvDivisor             = "ENV014"
+
+	// тома пода и volumeMounts контейнеров
+	codeVolumeMountsType     = "VOL001"
+	codeVolumeMountType      = "VOL002"
+	codeVolumeMountName      = "VOL003"
+	codeVolumeMountFieldType = "VOL004"
+	codeMountPathRequired    = "VOL005"
+	codeMountPathAbsolute    = "VOL006"
+	codeMountPathDup         = "VOL007"
+	codeVolumeUndefined      = "VOL008"
+	codeVolumeUnused         = "VOL009"
+	codeVolumesType          = "VOL010"
+	codeVolumeType           = "VOL011"
+	codeVolumeNameRequired   = "VOL012"
+	codeVolumeNameType       = "VOL013"
+	codeVolumeNameDup        = "VOL014"
+
+	// источники томов
+	codeVolumeSources           = "VOL015"
+	codeVolumeSourceType        = "VOL016"
+	codeVolumeSourceField       = "VOL017"
+	codeVolumeSourceFieldType   = "VOL018"
+	codeVolumeMediumUnsupported = "VOL019"
+	codeVolumeMediumCase        = "VOL020"
+	codeVolumeSizeLimit         = "VOL021"
+	codeHostPathRelative        = "VOL022"
+	codeHostPathTypeUnsupported = "VOL023"
+	codeHostPathTypeCase        = "VOL024"
+	codeVolumeItemsType         = "VOL025"
+	codeVolumeItemType          = "VOL026"
+	codeVolumeItemPath          = "VOL027"
+
+	// контроллеры: replicas, селектор и шаблон пода
+	codeWorkloadSpecType          = "WRK001"
+	codeReplicasType              = "WRK002"
+	codeReplicasNegative          = "WRK003"
+	codeSelectorRequired          = "WRK004"
+	codeSelectorType              = "WRK005"
+	codeSelectorEmpty             = "WRK006"
+	codeMatchLabelsType           = "WRK007"
+	codeMatchExpressionsType      = "WRK008"
+	codeMatchExpressionType       = "WRK009"
+	codeMatchExpressionKey        = "WRK010"
+	codeMatchExpressionOpRequired = "WRK011"
+	codeMatchExpressionOp         = "WRK012"
+	codeMatchExpressionValues     = "WRK013"
+	codeTemplateRequired          = "WRK014"
+	codeTemplateType              = "WRK015"
+	codeSelectorMismatch          = "WRK016"
+
+	// Deployment
+	codeStrategyType          = "DEP001"
+	codeStrategyUnsupported   = "DEP002"
+	codeStrategyCase          = "DEP003"
+	codeRollingUpdateRecreate = "DEP004"
+	codeIntOrPercent          = "DEP005"
+	codeRollingUpdateZero     = "DEP006"
+
+	// StatefulSet
+	codeServiceNameRequired       = "STS001"
+	codeServiceNameFormat         = "STS002"
+	codePodManagementPolicy       = "STS003"
+	codePodManagementCase         = "STS004"
+	codeUpdateStrategyType        = "STS005"
+	codeUpdateStrategyUnsupported = "STS006"
+	codeUpdateStrategyCase        = "STS007"
+	codeRollingUpdateOnDelete     = "STS008"
+	codePartition                 = "STS009"
+	codeClaimTemplatesType        = "STS010"
+	codeClaimTemplateType         = "STS011"
+	codeClaimTemplateName         = "STS012"
+	codeClaimTemplateDup          = "STS013"
+
+	// DaemonSet
+	codeDaemonSetReplicas = "DMS001"
+
+	// Job
+	codeJobCount              = "JOB001"
+	codeRestartPolicyRequired = "JOB002"
+	codeRestartPolicy         = "JOB003"
+	codeRestartPolicyCase     = "JOB004"
+
+	// CronJob
+	codeScheduleRequired      = "CRJ001"
+	codeScheduleInvalid       = "CRJ002"
+	codeConcurrencyPolicy     = "CRJ003"
+	codeConcurrencyPolicyCase = "CRJ004"
+	codeCronJobCount          = "CRJ005"
+	codeJobTemplateRequired   = "CRJ006"
+	codeJobTemplateType       = "CRJ007"
+
+	// spec PersistentVolumeClaim, в том числе в volumeClaimTemplates
+	codeClaimSpecType         = "PVC001"
+	codeAccessModesRequired   = "PVC002"
+	codeAccessModesType       = "PVC003"
+	codeAccessModeUnsupported = "PVC004"
+	codeAccessModeCase        = "PVC005"
+	codeStorageRequired       = "PVC006"
+	codeStorageFormat         = "PVC007"
+	codeStorageClassType      = "PVC008"
+	codeStorageClassFormat    = "PVC009"
+	codeVolumeModeUnsupported = "PVC010"
+	codeVolumeModeCase        = "PVC011"
+
+	// Service
+	codeServiceSpecType         = "SVC001"
+	codeServiceTypeUnsupported  = "SVC002"
+	codeServiceTypeCase         = "SVC003"
+	codeServicePortsRequired    = "SVC004"
+	codeServicePortNameRequired = "SVC005"
+	codeServicePortName         = "SVC006"
+	codeServicePortNameDup      = "SVC007"
+	codeTargetPortFormat        = "SVC008"
+	codeNodePortNotAllowed      = "SVC009"
+	codeNodePortRange           = "SVC010"
+	codeServiceSelectorType     = "SVC011"
+	codeClusterIPFormat         = "SVC012"
+	codeExternalNameRequired    = "SVC013"
+	codeServicePortsType        = "SVC014"
+	codeServicePortType         = "SVC015"
+	codeServicePortRequired     = "SVC016"
+
+	// Ingress
+	codeIngressSpecType        = "ING001"
+	codeIngressClassFormat     = "ING002"
+	codeIngressRulesRequired   = "ING003"
+	codeIngressRulesType       = "ING004"
+	codeIngressRuleType        = "ING005"
+	codeIngressHostFormat      = "ING006"
+	codeIngressHTTPType        = "ING007"
+	codeIngressPathsRequired   = "ING008"
+	codeIngressPathsType       = "ING009"
+	codeIngressPathType        = "ING010"
+	codePathTypeRequired       = "ING011"
+	codePathTypeUnsupported    = "ING012"
+	codePathTypeCase           = "ING013"
+	codeIngressPathFormat      = "ING014"
+	codeIngressBackendRequired = "ING015"
+	codeIngressBackendType     = "ING016"
+	codeIngressBackendOneOf    = "ING017"
+	codeIngressBackendField    = "ING018"
+	codeIngressServiceName     = "ING019"
+	codeIngressServicePort     = "ING020"
+	codeIngressPortName        = "ING021"
+	codeIngressTLSType         = "ING022"
+	codeIngressTLSItemType     = "ING023"
+	codeIngressTLSHostsType    = "ING024"
+	codeIngressSecretName      = "ING025"
+
+	// HorizontalPodAutoscaler
+	codeHPASpecType             = "HPA001"
+	codeScaleTargetRequired     = "HPA002"
+	codeScaleTargetType         = "HPA003"
+	codeScaleTargetField        = "HPA004"
+	codeMaxReplicasRequired     = "HPA005"
+	codeHPAReplicas             = "HPA006"
+	codeHPAReplicasRange        = "HPA007"
+	codeMetricsType             = "HPA008"
+	codeMetricType              = "HPA009"
+	codeMetricTypeRequired      = "HPA010"
+	codeMetricTypeUnsupported   = "HPA011"
+	codeMetricTypeCase          = "HPA012"
+	codeMetricSourceRequired    = "HPA013"
+	codeMetricSourceType        = "HPA014"
+	codeMetricSourceField       = "HPA015"
+	codeMetricTargetUnsupported = "HPA016"
+	codeMetricTargetCase        = "HPA017"
+	codeMetricTargetValue       = "HPA018"
+
+	// NetworkPolicy
+	codeNetPolSpecType        = "NPL001"
+	codePodSelectorRequired   = "NPL002"
+	codeNetPolListType        = "NPL003"
+	codeNetPolItemType        = "NPL004"
+	codePolicyTypeUnsupported = "NPL005"
+	codePolicyTypeCase        = "NPL006"
+	codePeerEmpty             = "NPL007"
+	codePeerIPBlockMixed      = "NPL008"
+	codeIPBlockType           = "NPL009"
+	codeCIDRRequired          = "NPL010"
+	codeCIDRFormat            = "NPL011"
+	codeExceptOutsideCIDR     = "NPL012"
+	codeNetPolPortName        = "NPL013"
+	codeEndPort               = "NPL014"
+
+	// PodDisruptionBudget
+	codePDBSpecType               = "PDB001"
+	codePDBBudgetRequired         = "PDB002"
+	codePDBBudgetExclusive        = "PDB003"
+	codePDBPercent                = "PDB004"
+	codeEvictionPolicyUnsupported = "PDB005"
+	codeEvictionPolicyCase        = "PDB006"
+
+	// kind: List — обёртка kubectl get над несколькими объектами
+	codeListItemsRequired = "LST001"
+	codeListItemsType     = "LST002"
+
+	// ConfigMap
+	codeConfigMapDataType  = "CFG001"
+	codeConfigMapValueType = "CFG002"
+	codeBinaryDataType     = "CFG003"
+	codeBinaryDataValue    = "CFG004"
+	codeConfigMapKey       = "CFG005"
+	codeConfigMapKeyDup    = "CFG006"
+	codeConfigMapSize      = "CFG007"
+	codeImmutableType      = "CFG008"
+
+	// Secret
+	codeSecretTypeType      = "SEC001"
+	codeSecretTypeUnknown   = "SEC002"
+	codeSecretTypeCase      = "SEC003"
+	codeSecretDataType      = "SEC004"
+	codeSecretDataValue     = "SEC005"
+	codeStringDataType      = "SEC006"
+	codeStringDataValue     = "SEC007"
+	codeSecretKey           = "SEC008"
+	codeSecretKeyDup        = "SEC009"
+	codeSecretKeyRequired   = "SEC010"
+	codeSecretSize          = "SEC011"
+	codePlaintextCredential = "SEC012"
+
+	// скаляры, которые YAML 1.1 и YAML 1.2 читают по-разному
+	codeYAML11Bool        = "YML001"
+	codeYAML11Sexagesimal = "YML002"
+	codeYAML11Number      = "YML003"
+
+	// rule pack recommended-labels
+	codeRecommendedLabel       = "LBL001"
+	codeRecommendedLabelFormat = "LBL002"
+
+	// лимиты apiserver/etcd
+	codeObjectSize      = "LIM001"
+	codeNameLength      = "LIM002"
+	codeNamespaceLength = "LIM003"
+	codeLabelKeyLength  = "LIM004"
+	codeLabelValueLen   = "LIM005"
+	codeAnnotationsSize = "LIM006"
+
+	// входные данные
+	codeBinarySkipped = "DOC001"
+	codeNotManifest   = "DOC002"
+	codeUnknownKind   = "DOC003"
+	codeEmptyFile     = "DOC004"
+	codeCommentsOnly  = "DOC005"
+	codeEmptyDocument = "DOC006"
+
+	// файл целиком
+	codeTooFewDocuments  = "FIL001"
+	codeTooManyDocuments = "FIL002"
+	codeKindMissing      = "FIL003"
+	codeFileName         = "FIL004"
+	codeLayout           = "FIL005"
+
+	// архитектуры образов
+	codeImageArchUnknown  = "ARC001"
+	codeImageArchMismatch = "ARC002"
+
+	// планирование: инвентарь кластера и PriorityClass'ы
+	codeSelectorNoPool         = "SCH001"
+	codeTolerationNoTaint      = "SCH002"
+	codeTolerationNotTargeted  = "SCH003"
+	codePoolTaintNotTolerated  = "SCH004"
+	codePriorityClassUnknown   = "SCH005"
+	codePriorityClassSystem    = "SCH006"
+	codeStorageClassUnknown    = "SCH007"
+	codeStorageClassAccessMode = "SCH008"
+
+	// правила аннотаций из конфига
+	codeAnnotationRequired  = "ANN001"
+	codeAnnotationFormat    = "ANN002"
+	codeAnnotationReference = "ANN003"
+
+	// podman play kube
+	codePodmanKind           = "PDM001"
+	codePodmanPodField       = "PDM002"
+	codePodmanContainerField = "PDM003"
+
+	// rule pack best-practices
+	codeImageLatestTag = "BPR001"
+	codeLimitsMissing  = "BPR002"
+)
+
+// Коды проверок, которые живут в CLI (онлайн-проверки образов, квоты по
+// набору файлов), а сообщения — в общем каталоге.
+const (
+	CodeImageNotFound    = "IMG001"
+	CodeImageCheckFailed = "IMG002"
+	CodeImageVulnerable  = "IMG003"
+
+	// квоты неймспейсов и ссылки из аннотаций проверяются по всему набору
+	// файлов в CLI
+	CodeQuotaExceeded    = "QTA001"
+	CodeReferenceMissing = "ANN004"
+
+	// --frozen: файл разошёлся с дайджестом в lockfile
+	CodeDigestMismatch = "LCK001"
+	CodeNotLocked      = "LCK002"
+
+	// файл не прочитан или не разобран: такие находки валят прогон с кодом 2
+	CodeReadFailed  = "IO001"
+	CodeParseFailed = "IO002"
+	// проверка файла зависла или упала: остальные файлы проверяются дальше
+	CodeValidationTimeout = "IO003"
+	CodeValidationPanic   = "IO004"
+)
+
+// CodeServerField — поле, которое заполняет API-сервер; правку этой
+// находки применяет подкоманда clean.
+const CodeServerField = "MET006"
+
+// Catalog — шаблоны сообщений в стиле printf по кодам находок.
+type Catalog map[string]string
+
+// English — каталог по умолчанию; в других каталогах можно перевести
+// только часть кодов, остальные берутся отсюда.
+var English = Catalog{
+	codeRootType:              "root must be object",
+	codeAPIVersionRequired:    "apiVersion is required",
+	codeAPIVersionType:        "apiVersion must be string",
+	codeAPIVersionUnsupported: "apiVersion has unsupported value '%s'",
+	codeKindRequired:          "kind is required",
+	codeKindType:              "kind must be string",
+	codeKindUnsupported:       "kind has unsupported value '%s'",
+	codeMetadataRequired:      "metadata is required",
+	codeSpecRequired:          "spec is required",
+	codeAPIVersionCase:        "apiVersion has unsupported value '%s'",
+	codeKindCase:              "kind has unsupported value '%s'",
+
+	codeMetadataType: "metadata must be object",
+	codeNameRequired: "name is required",
+	codeNameType:     "name must be string",
+	codeNamespace:    "namespace must be string",
+	codeLabelsType:   "labels must be object",
+	CodeServerField:  "%s is set by the API server and should not be in a manifest",
+
+	codeSpecType:            "spec must be object",
+	codeContainersRequired:  "containers is required",
+	codeContainersType:      "containers must be array",
+	codeContainersEmpty:     "containers must be non-empty array",
+	codeContainerNameDup:    "name has invalid format '%s'",
+	codeOSType:              "os must be string",
+	codeOSUnsupported:       "os has unsupported value '%s'",
+	codeOSNameRequired:      "os.name is required",
+	codeOSNameType:          "name must be string",
+	codePriorityClassType:   "priorityClassName must be string",
+	codePriorityClassFormat: "priorityClassName has invalid format '%s'",
+	codeOSCase:              "os has unsupported value '%s'",
+	codeInitContainersType:  "initContainers must be array",
+	codeContainerType:       "container must be object",
+	codeContainerNameReq:    "name is required",
+	codeContainerNameType:   "name must be string",
+	codeContainerNameFormat: "name has invalid format '%s'",
+	codeImageRequired:       "image is required",
+	codeImageType:           "image must be string",
+	codeImageFormat:         "image has invalid format '%s'",
+	codePortsType:           "ports must be array",
+	codeResourcesRequired:   "resources is required",
+
+	codePortNotInt:          "%s must be int",
+	codePortNotPositive:     "%s must be positive",
+	codePortTooLarge:        "%s must not exceed 65535",
+	codePortItemType:        "ports item must be object",
+	codeContainerPortReq:    "containerPort is required",
+	codeProtocolType:        "protocol must be string",
+	codeProtocolUnsupported: "protocol has unsupported value '%s'",
+	codeProtocolCase:        "protocol has unsupported value '%s'",
+
+	codeProbeType:       "%s must be object",
+	codeHTTPGetRequired: "httpGet is required",
+	codeHTTPGetType:     "httpGet must be object",
+	codePathRequired:    "path is required",
+	codePathType:        "path must be string",
+	codePathFormat:      "path has invalid format '%s'",
+	codeProbePortReq:    "port is required",
+	codeProbeTimeout:    "timeoutSeconds %d exceeds periodSeconds %d",
+
+	codeResourcesType: "resources must be object",
+	codeResourceMap:   "%s must be object",
+	codeCPUType:       "cpu must be int",
+	codeMemoryType:    "memory must be string",
+	codeMemoryFormat:  "memory has invalid format '%s'",
+
+	codeEnvType:                "env must be array",
+	codeEnvItemType:            "env item must be object",
+	codeEnvNameRequired:        "env name is required",
+	codeEnvNameFormat:          "env name has invalid format '%s'",
+	codeEnvValueExclusive:      "value and valueFrom are mutually exclusive",
+	codeEnvValueType:           "value of %s must be string",
+	codeValueFromType:          "valueFrom must be object",
+	codeValueFromSource:        "valueFrom must set exactly one of %s",
+	codeEnvRefType:             "%s must be object",
+	codeEnvRefField:            "%s is required",
+	codeEnvRefFieldType:        "%s must be %s",
+	codeEnvResourceUnsupported: "resourceFieldRef.resource has unsupported value '%s'",
+	codeEnvResourceCase:        "resourceFieldRef.resource has unsupported value '%s'",
+	codeEnvDivisor:             "resourceFieldRef.divisor has invalid format '%s'",
+
+	codeVolumeMountsType:     "volumeMounts must be array",
+	codeVolumeMountType:      "volumeMounts item must be object",
+	codeVolumeMountName:      "volumeMounts name is required",
+	codeVolumeMountFieldType: "volumeMounts %s must be string",
+	codeMountPathRequired:    "volumeMounts mountPath is required",
+	codeMountPathAbsolute:    "mountPath '%s' must be absolute",
+	codeMountPathDup:         "mountPath '%s' is duplicated",
+	codeVolumeUndefined:      "volume '%s' is not defined in volumes",
+	codeVolumeUnused:         "volume '%s' is not mounted by any container",
+	codeVolumesType:          "volumes must be array",
+	codeVolumeType:           "volumes item must be object",
+	codeVolumeNameRequired:   "volumes name is required",
+	codeVolumeNameType:       "volumes name must be string",
+	codeVolumeNameDup:        "volume name '%s' is duplicated",
+
+	codeVolumeSources:           "volume must have exactly one source, got %s",
+	codeVolumeSourceType:        "%s must be object",
+	codeVolumeSourceField:       "%s is required",
+	codeVolumeSourceFieldType:   "%s must be %s",
+	codeVolumeMediumUnsupported: "emptyDir.medium has unsupported value '%s'",
+	codeVolumeMediumCase:        "emptyDir.medium has unsupported value '%s'",
+	codeVolumeSizeLimit:         "emptyDir.sizeLimit has invalid format '%s'",
+	codeHostPathRelative:        "hostPath.path '%s' must be absolute",
+	codeHostPathTypeUnsupported: "hostPath.type has unsupported value '%s'",
+	codeHostPathTypeCase:        "hostPath.type has unsupported value '%s'",
+	codeVolumeItemsType:         "%s.items must be array",
+	codeVolumeItemType:          "%s.items item must be object",
+	codeVolumeItemPath:          "%s.items path '%s' must be relative and stay inside the volume",
+
+	codeWorkloadSpecType:          "spec must be object",
+	codeReplicasType:              "replicas must be int",
+	codeReplicasNegative:          "replicas must not be negative",
+	codeSelectorRequired:          "selector is required",
+	codeSelectorType:              "selector must be object",
+	codeSelectorEmpty:             "selector must have matchLabels or matchExpressions",
+	codeMatchLabelsType:           "matchLabels must be object of strings",
+	codeMatchExpressionsType:      "matchExpressions must be array",
+	codeMatchExpressionType:       "matchExpressions item must be object",
+	codeMatchExpressionKey:        "matchExpressions key is required",
+	codeMatchExpressionOpRequired: "matchExpressions operator is required",
+	codeMatchExpressionOp:         "operator has unsupported value '%s'",
+	codeMatchExpressionValues:     "values do not fit operator %s: In and NotIn need a non-empty array, Exists and DoesNotExist none",
+	codeTemplateRequired:          "template is required",
+	codeTemplateType:              "template must be object",
+	codeSelectorMismatch:          "selector does not match template labels",
+
+	codeStrategyType:          "strategy must be object",
+	codeStrategyUnsupported:   "strategy type has unsupported value '%s'",
+	codeStrategyCase:          "strategy type has unsupported value '%s'",
+	codeRollingUpdateRecreate: "rollingUpdate is not allowed with strategy type Recreate",
+	codeIntOrPercent:          "%s must be int or percentage",
+	codeRollingUpdateZero:     "maxSurge and maxUnavailable must not both be zero",
+
+	codeServiceNameRequired:       "serviceName is required",
+	codeServiceNameFormat:         "serviceName has invalid format '%s'",
+	codePodManagementPolicy:       "podManagementPolicy has unsupported value '%s'",
+	codePodManagementCase:         "podManagementPolicy has unsupported value '%s'",
+	codeUpdateStrategyType:        "updateStrategy must be object",
+	codeUpdateStrategyUnsupported: "updateStrategy type has unsupported value '%s'",
+	codeUpdateStrategyCase:        "updateStrategy type has unsupported value '%s'",
+	codeRollingUpdateOnDelete:     "rollingUpdate is not allowed with updateStrategy type OnDelete",
+	codePartition:                 "partition must be non-negative int",
+	codeClaimTemplatesType:        "volumeClaimTemplates must be array",
+	codeClaimTemplateType:         "volumeClaimTemplates item must be object",
+	codeClaimTemplateName:         "volumeClaimTemplates metadata.name is required",
+	codeClaimTemplateDup:          "volumeClaimTemplates name '%s' is duplicated",
+
+	codeDaemonSetReplicas: "replicas is not allowed in DaemonSet",
+
+	codeJobCount:              "%s must be non-negative int",
+	codeRestartPolicyRequired: "restartPolicy is required",
+	codeRestartPolicy:         "restartPolicy has unsupported value '%s'",
+	codeRestartPolicyCase:     "restartPolicy has unsupported value '%s'",
+
+	codeScheduleRequired:      "schedule is required",
+	codeScheduleInvalid:       "schedule has invalid format '%s'",
+	codeConcurrencyPolicy:     "concurrencyPolicy has unsupported value '%s'",
+	codeConcurrencyPolicyCase: "concurrencyPolicy has unsupported value '%s'",
+	codeCronJobCount:          "%s must be non-negative int",
+	codeJobTemplateRequired:   "jobTemplate is required",
+	codeJobTemplateType:       "jobTemplate must be object",
+
+	codeClaimSpecType:         "spec must be object",
+	codeAccessModesRequired:   "accessModes is required",
+	codeAccessModesType:       "accessModes must be non-empty array",
+	codeAccessModeUnsupported: "accessModes has unsupported value '%s'",
+	codeAccessModeCase:        "accessModes has unsupported value '%s'",
+	codeStorageRequired:       "resources.requests.storage is required",
+	codeStorageFormat:         "storage has invalid format '%s'",
+	codeStorageClassType:      "storageClassName must be string",
+	codeStorageClassFormat:    "storageClassName has invalid format '%s'",
+	codeVolumeModeUnsupported: "volumeMode has unsupported value '%s'",
+	codeVolumeModeCase:        "volumeMode has unsupported value '%s'",
+
+	codeServiceSpecType:         "spec must be object",
+	codeServiceTypeUnsupported:  "type has unsupported value '%s'",
+	codeServiceTypeCase:         "type has unsupported value '%s'",
+	codeServicePortsRequired:    "ports is required",
+	codeServicePortNameRequired: "ports name is required when there are several ports",
+	codeServicePortName:         "ports name has invalid format '%s'",
+	codeServicePortNameDup:      "ports name '%s' is duplicated",
+	codeTargetPortFormat:        "targetPort has invalid format '%s'",
+	codeNodePortNotAllowed:      "nodePort is not allowed for type %s",
+	codeNodePortRange:           "nodePort %s is outside the range %d-%d",
+	codeServiceSelectorType:     "selector must be object of strings",
+	codeClusterIPFormat:         "clusterIP has invalid format '%s'",
+	codeExternalNameRequired:    "externalName is required for type ExternalName",
+	codeServicePortsType:        "ports must be array",
+	codeServicePortType:         "ports item must be object",
+	codeServicePortRequired:     "port is required",
+
+	codeIngressSpecType:        "spec must be object",
+	codeIngressClassFormat:     "ingressClassName has invalid format '%s'",
+	codeIngressRulesRequired:   "rules or defaultBackend is required",
+	codeIngressRulesType:       "rules must be array",
+	codeIngressRuleType:        "rules item must be object",
+	codeIngressHostFormat:      "host has invalid format '%s'",
+	codeIngressHTTPType:        "http must be object",
+	codeIngressPathsRequired:   "http.paths is required",
+	codeIngressPathsType:       "http.paths must be non-empty array",
+	codeIngressPathType:        "http.paths item must be object",
+	codePathTypeRequired:       "pathType is required",
+	codePathTypeUnsupported:    "pathType has unsupported value '%s'",
+	codePathTypeCase:           "pathType has unsupported value '%s'",
+	codeIngressPathFormat:      "path has invalid format '%s'",
+	codeIngressBackendRequired: "backend is required",
+	codeIngressBackendType:     "backend must be object",
+	codeIngressBackendOneOf:    "backend must have exactly one of service and resource",
+	codeIngressBackendField:    "backend %s is required",
+	codeIngressServiceName:     "backend service name has invalid format '%s'",
+	codeIngressServicePort:     "backend service port must have exactly one of number and name",
+	codeIngressPortName:        "backend service port name has invalid format '%s'",
+	codeIngressTLSType:         "tls must be array",
+	codeIngressTLSItemType:     "tls item must be object",
+	codeIngressTLSHostsType:    "tls hosts must be array",
+	codeIngressSecretName:      "tls secretName has invalid format '%s'",
+
+	codeHPASpecType:             "spec must be object",
+	codeScaleTargetRequired:     "scaleTargetRef is required",
+	codeScaleTargetType:         "scaleTargetRef must be object",
+	codeScaleTargetField:        "scaleTargetRef %s is required",
+	codeMaxReplicasRequired:     "maxReplicas is required",
+	codeHPAReplicas:             "%s must be positive int",
+	codeHPAReplicasRange:        "minReplicas %d is greater than maxReplicas %d",
+	codeMetricsType:             "metrics must be array",
+	codeMetricType:              "metrics item must be object",
+	codeMetricTypeRequired:      "metric type is required",
+	codeMetricTypeUnsupported:   "metric type has unsupported value '%s'",
+	codeMetricTypeCase:          "metric type has unsupported value '%s'",
+	codeMetricSourceRequired:    "%s is required for metric type %s",
+	codeMetricSourceType:        "%s must be object",
+	codeMetricSourceField:       "%s.%s is required",
+	codeMetricTargetUnsupported: "target type has unsupported value '%s'",
+	codeMetricTargetCase:        "target type has unsupported value '%s'",
+	codeMetricTargetValue:       "%s has invalid value '%s'",
+
+	codeNetPolSpecType:        "spec must be object",
+	codePodSelectorRequired:   "podSelector is required",
+	codeNetPolListType:        "%s must be array",
+	codeNetPolItemType:        "%s item must be object",
+	codePolicyTypeUnsupported: "policyTypes has unsupported value '%s'",
+	codePolicyTypeCase:        "policyTypes has unsupported value '%s'",
+	codePeerEmpty:             "peer must set podSelector, namespaceSelector or ipBlock",
+	codePeerIPBlockMixed:      "ipBlock cannot be combined with podSelector or namespaceSelector",
+	codeIPBlockType:           "ipBlock must be object",
+	codeCIDRRequired:          "ipBlock cidr is required",
+	codeCIDRFormat:            "cidr has invalid format '%s'",
+	codeExceptOutsideCIDR:     "except '%s' is not within cidr %s",
+	codeNetPolPortName:        "port has invalid format '%s'",
+	codeEndPort:               "endPort needs a numeric port and must not be less than it",
+
+	codePDBSpecType:               "spec must be object",
+	codePDBBudgetRequired:         "one of minAvailable or maxUnavailable is required",
+	codePDBBudgetExclusive:        "minAvailable and maxUnavailable are mutually exclusive",
+	codePDBPercent:                "%s must not exceed 100%%",
+	codeEvictionPolicyUnsupported: "unhealthyPodEvictionPolicy has unsupported value '%s'",
+	codeEvictionPolicyCase:        "unhealthyPodEvictionPolicy has unsupported value '%s'",
+
+	codeListItemsRequired: "items is required",
+	codeListItemsType:     "items must be array",
+
+	codeConfigMapDataType:  "data must be object",
+	codeConfigMapValueType: "data value for key '%s' must be string",
+	codeBinaryDataType:     "binaryData must be object",
+	codeBinaryDataValue:    "binaryData value for key '%s' must be base64 string",
+	codeConfigMapKey:       "%s key '%s' has invalid format",
+	codeConfigMapKeyDup:    "key '%s' is set in both data and binaryData",
+	codeConfigMapSize:      "data size %d bytes exceeds limit of %d bytes",
+	codeImmutableType:      "immutable must be bool",
+
+	codeSecretTypeType:      "type must be string",
+	codeSecretTypeUnknown:   "type '%s' is not a built-in Secret type",
+	codeSecretTypeCase:      "type has unsupported value '%s'",
+	codeSecretDataType:      "data must be object",
+	codeSecretDataValue:     "data value for key '%s' must be base64 string",
+	codeStringDataType:      "stringData must be object",
+	codeStringDataValue:     "stringData value for key '%s' must be string",
+	codeSecretKey:           "%s key '%s' has invalid format",
+	codeSecretKeyDup:        "key '%s' is set in both data and stringData, stringData wins",
+	codeSecretKeyRequired:   "type %s requires key '%s'",
+	codeSecretSize:          "data size %d bytes exceeds limit of %d bytes",
+	codePlaintextCredential: "stringData '%s' holds a plaintext credential; keep it out of the repository",
+
+	codeYAML11Bool:        "'%s' is boolean %s in YAML 1.1 but a string in YAML 1.2",
+	codeYAML11Sexagesimal: "'%s' is a base-60 number in YAML 1.1 but a string in YAML 1.2",
+	codeYAML11Number:      "'%s' is read differently by YAML 1.1 and YAML 1.2",
+
+	codeRecommendedLabel:       "%s is required",
+	codeRecommendedLabelFormat: "%s has invalid format '%s'",
+
+	codeObjectSize:      "object size %d bytes exceeds etcd limit of %d bytes",
+	codeNameLength:      "name is longer than %d characters",
+	codeNamespaceLength: "namespace is longer than %d characters",
+	codeLabelKeyLength:  "label key '%s' exceeds length limits",
+	codeLabelValueLen:   "label '%s' value is longer than %d characters",
+	codeAnnotationsSize: "annotations total size %d bytes exceeds limit of %d bytes",
+
+	codeBinarySkipped: "binary file skipped",
+	codeNotManifest:   "not a Kubernetes manifest (no apiVersion and kind), skipped",
+	codeUnknownKind:   "kind '%s' has no validator, skipped",
+	codeEmptyFile:     "file is empty",
+	codeCommentsOnly:  "file contains only comments",
+	codeEmptyDocument: "document is empty",
+
+	codeTooFewDocuments:  "file has %d documents, expected at least %d",
+	codeTooManyDocuments: "file has %d documents, expected at most %d",
+	codeKindMissing:      "file has no document of kind '%s'",
+	codeFileName:         "file name '%s' does not match metadata.name, expected '%s'",
+	codeLayout:           "directory '%s' does not match layout %s",
+
+	codeImageArchUnknown:  "image '%s' has no declared architectures",
+	codeImageArchMismatch: "image '%s' is not built for %s required by nodeSelector",
+
+	codeSelectorNoPool:         "nodeSelector and affinity match no node pool in cluster inventory",
+	codeTolerationNoTaint:      "toleration '%s' matches no taint in cluster inventory",
+	codeTolerationNotTargeted:  "toleration '%s' is not backed by nodeSelector or affinity for its node pool",
+	codePoolTaintNotTolerated:  "node pool '%s' is selected but its taint '%s' is not tolerated",
+	codePriorityClassUnknown:   "priorityClassName '%s' is not a known PriorityClass",
+	codePriorityClassSystem:    "priority class '%s' is reserved for system pods, used in namespace '%s'",
+	codeStorageClassUnknown:    "storageClassName '%s' is not a storage class in cluster inventory",
+	codeStorageClassAccessMode: "storage class '%s' does not support access mode '%s'",
+
+	codeAnnotationRequired:  "annotation %s is required",
+	codeAnnotationFormat:    "annotation %s has invalid format '%s'",
+	codeAnnotationReference: "annotation %s has invalid reference '%s', expected Kind/name or Kind/namespace/name",
+
+	codePodmanKind:           "kind '%s' is not supported by podman play kube",
+	codePodmanPodField:       "%s is ignored by podman play kube",
+	codePodmanContainerField: "container %s is ignored by podman play kube",
+
+	codeImageLatestTag: "image '%s' uses a floating tag, pin a version or digest",
+	codeLimitsMissing:  "container '%s' has no resources.limits",
+
+	CodeImageNotFound:     "image '%s' not found in registry",
+	CodeImageCheckFailed:  "cannot check image '%s': %s",
+	CodeImageVulnerable:   "image '%s' has %d critical vulnerabilities: %s",
+	CodeReferenceMissing:  "annotation %s refers to %s, which is not in the validated set",
+	CodeQuotaExceeded:     "namespace '%s' requests %s %s, exceeding quota of %s",
+	CodeDigestMismatch:    "file digest %s does not match lockfile, regenerate it instead of editing by hand",
+	CodeNotLocked:         "file is not in lockfile, add it through the generator",
+	CodeReadFailed:        "cannot read file content: %s",
+	CodeParseFailed:       "cannot unmarshal file content: %s",
+	CodeValidationTimeout: "validation timed out after %s, skipped",
+	CodeValidationPanic:   "internal error during validation: %s",
+}
+
+// Message — текст находки по английскому каталогу.
+func (is Issue) Message() string { return is.Render(English) }
+
+// Render подставляет аргументы в шаблон из каталога c.
+func (is Issue) Render(c Catalog) string {
+	tmpl, ok := c[is.Code]
+	if !ok {
+		if tmpl, ok = English[is.Code]; !ok {
+			return is.Code
+		}
+	}
+	if len(is.Args) == 0 {
+		return tmpl
+	}
+	return fmt.Sprintf(tmpl, is.Args...)
+}
+
+// MarshalJSON добавляет имя проверки и отрендеренное сообщение, чтобы
+// потребителям JSON не нужен был каталог.
+func (is Issue) MarshalJSON() ([]byte, error) {
+	type plain Issue
+	return json.Marshal(struct {
+		plain
+		Name    string `json:"name,omitempty"`
+		Message string `json:"message"`
+	}{plain(is), RuleName(is.Code), is.Message()})
+}
@@ -0,0 +1,191 @@
+// networkpolicy.go
+package validator
+
+import (
+	"net"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var policyTypes = []string{"Egress", "Ingress"}
+
+func validateNetworkPolicySpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeNetPolSpecType)
+		return
+	}
+	// podSelector (required): {} — все поды неймспейса
+	if sel, ok := m.get("podSelector"); !ok {
+		bag.add(nil, codePodSelectorRequired)
+	} else {
+		checkLabelSelector(sel, bag)
+	}
+
+	if pt, ok := m.get("policyTypes"); ok {
+		if pt.Kind != yaml.SequenceNode {
+			bag.add(pt, codeNetPolListType, "policyTypes")
+		} else {
+			for _, t := range pt.Content {
+				if !isScalarString(t) || !contains(policyTypes, t.Value) {
+					unsupported(bag, t, codePolicyTypeUnsupported, codePolicyTypeCase, policyTypes)
+				}
+			}
+		}
+	}
+
+	// ingress берёт пиров из from, egress — из to
+	for _, dir := range []struct{ field, peers string }{{"ingress", "from"}, {"egress", "to"}} {
+		rules, ok := m.get(dir.field)
+		if !ok {
+			continue
+		}
+		if rules.Kind != yaml.SequenceNode {
+			bag.add(rules, codeNetPolListType, dir.field)
+			continue
+		}
+		for _, r := range rules.Content {
+			validateNetPolRule(r, dir.field, dir.peers, bag)
+		}
+	}
+}
+
+func validateNetPolRule(n *yaml.Node, field, peersField string, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeNetPolItemType, field)
+		return
+	}
+	if peers, ok := m.get(peersField); ok {
+		if peers.Kind != yaml.SequenceNode {
+			bag.add(peers, codeNetPolListType, peersField)
+		} else {
+			for _, p := range peers.Content {
+				validateNetPolPeer(p, peersField, bag)
+			}
+		}
+	}
+	if ports, ok := m.get("ports"); ok {
+		if ports.Kind != yaml.SequenceNode {
+			bag.add(ports, codeNetPolListType, "ports")
+		} else {
+			for _, p := range ports.Content {
+				validateNetPolPort(p, bag)
+			}
+		}
+	}
+}
+
+// validateNetPolPeer — пир задаёт селекторы или ipBlock, но не то и
+// другое вместе.
+func validateNetPolPeer(n *yaml.Node, field string, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeNetPolItemType, field)
+		return
+	}
+	pods, hasPods := m.get("podSelector")
+	ns, hasNS := m.get("namespaceSelector")
+	block, hasBlock := m.get("ipBlock")
+	switch {
+	case !hasPods && !hasNS && !hasBlock:
+		bag.add(n, codePeerEmpty)
+		return
+	case hasBlock && (hasPods || hasNS):
+		bag.add(block, codePeerIPBlockMixed)
+		return
+	}
+	if hasPods {
+		checkLabelSelector(pods, bag)
+	}
+	if hasNS {
+		checkLabelSelector(ns, bag)
+	}
+	if hasBlock {
+		validateIPBlock(block, bag)
+	}
+}
+
+// validateIPBlock — cidr обязателен, except — подсети внутри cidr.
+func validateIPBlock(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeIPBlockType)
+		return
+	}
+	c, ok := m.get("cidr")
+	if !ok {
+		bag.add(n, codeCIDRRequired)
+		return
+	}
+	_, cidr, err := net.ParseCIDR(c.Value)
+	if !isScalarString(c) || err != nil {
+		bag.add(c, codeCIDRFormat, c.Value)
+		cidr = nil
+	}
+	except, ok := m.get("except")
+	if !ok {
+		return
+	}
+	if except.Kind != yaml.SequenceNode {
+		bag.add(except, codeNetPolListType, "except")
+		return
+	}
+	for _, e := range except.Content {
+		ip, sub, err := net.ParseCIDR(e.Value)
+		switch {
+		case !isScalarString(e) || err != nil:
+			bag.add(e, codeCIDRFormat, e.Value)
+		case cidr == nil:
+		case !cidr.Contains(ip) || subnetBits(sub) < subnetBits(cidr):
+			bag.add(e, codeExceptOutsideCIDR, e.Value, c.Value)
+		}
+	}
+}
+
+func subnetBits(n *net.IPNet) int {
+	ones, _ := n.Mask.Size()
+	return ones
+}
+
+// validateNetPolPort — port числом или именем, endPort — только после
+// числового port и не меньше его.
+func validateNetPolPort(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codePortItemType)
+		return
+	}
+	if proto, ok := m.get("protocol"); ok {
+		if !isScalarString(proto) {
+			bag.add(proto, codeProtocolType)
+		} else if !bag.rules.enums["protocol"].has(proto.Value) {
+			unsupported(bag, proto, codeProtocolUnsupported, codeProtocolCase, bag.rules.enums["protocol"].values())
+		}
+	}
+	port, hasPort := m.get("port")
+	numeric := false
+	if hasPort {
+		if isScalarString(port) {
+			if !validPortName(port.Value) {
+				bag.add(port, codeNetPolPortName, port.Value)
+			}
+		} else {
+			validatePort(port, bag, "port")
+			numeric = isScalarInt(port)
+		}
+	}
+	end, ok := m.get("endPort")
+	if !ok {
+		return
+	}
+	if !numeric {
+		bag.add(end, codeEndPort)
+		return
+	}
+	validatePort(end, bag, "endPort")
+	p, _ := toInt(port.Value)
+	if e, err := toInt(end.Value); isScalarInt(end) && err == nil && e < p {
+		bag.add(end, codeEndPort)
+	}
+}
@@ -0,0 +1,55 @@
+package validator
+
+import "testing"
+
+// netpol — NetworkPolicy со строками spec (отступ 2).
+func netpol(spec string) string {
+	return "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: web\nspec:\n" + spec
+}
+
+// ingressFrom — политика на все поды с одним ingress-правилом.
+func ingressFrom(rule string) string {
+	return netpol("  podSelector: {}\n  ingress: [" + rule + "]\n")
+}
+
+// peer — ingress-правило с одним пиром.
+func peer(p string) string { return ingressFrom("{from: [" + p + "]}") }
+
+// netpolPort — ingress-правило с одним портом.
+func netpolPort(p string) string { return ingressFrom("{ports: [" + p + "]}") }
+
+func TestNetworkPolicy(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"deny all", netpol("  podSelector: {}\n  policyTypes: [Ingress, Egress]\n"), ""},
+		{"allow from app", peer("{podSelector: {matchLabels: {app: api}}, namespaceSelector: {matchLabels: {team: shop}}}"), ""},
+		{"egress", netpol("  podSelector: {matchLabels: {app: web}}\n  egress: [{to: [{ipBlock: {cidr: 10.0.0.0/8}}], ports: [{port: 53, protocol: UDP}]}]\n"), ""},
+		{"spec not object", "apiVersion: networking.k8s.io/v1\nkind: NetworkPolicy\nmetadata:\n  name: web\nspec: deny\n", codeNetPolSpecType},
+		{"podSelector missing", netpol("  policyTypes: [Ingress]\n"), codePodSelectorRequired},
+		{"podSelector invalid", netpol("  podSelector: {matchLabels: [app]}\n"), codeMatchLabelsType},
+		{"policyTypes not array", netpol("  podSelector: {}\n  policyTypes: Ingress\n"), codeNetPolListType},
+		{"policyTypes unsupported", netpol("  podSelector: {}\n  policyTypes: [Both]\n"), codePolicyTypeUnsupported},
+		{"policyTypes case", netpol("  podSelector: {}\n  policyTypes: [egress]\n"), codePolicyTypeCase},
+		{"ingress not array", netpol("  podSelector: {}\n  ingress: {from: []}\n"), codeNetPolListType},
+		{"rule not object", ingressFrom("all"), codeNetPolItemType},
+		{"from not array", ingressFrom("{from: {podSelector: {}}}"), codeNetPolListType},
+		{"peer not object", peer("api"), codeNetPolItemType},
+		{"peer empty", peer("{}"), codePeerEmpty},
+		{"peer mixed", peer("{podSelector: {}, ipBlock: {cidr: 10.0.0.0/8}}"), codePeerIPBlockMixed},
+		{"ipBlock not object", peer("{ipBlock: 10.0.0.0/8}"), codeIPBlockType},
+		{"cidr missing", peer("{ipBlock: {except: [10.1.0.0/16]}}"), codeCIDRRequired},
+		{"cidr format", peer("{ipBlock: {cidr: 10.0.0.0}}"), codeCIDRFormat},
+		{"except", peer("{ipBlock: {cidr: 10.0.0.0/8, except: [10.1.0.0/16]}}"), ""},
+		{"except not array", peer("{ipBlock: {cidr: 10.0.0.0/8, except: 10.1.0.0/16}}"), codeNetPolListType},
+		{"except format", peer("{ipBlock: {cidr: 10.0.0.0/8, except: [10.1.0.0]}}"), codeCIDRFormat},
+		{"except outside", peer("{ipBlock: {cidr: 10.0.0.0/8, except: [192.168.0.0/16]}}"), codeExceptOutsideCIDR},
+		{"except wider", peer("{ipBlock: {cidr: 10.0.0.0/16, except: [10.0.0.0/8]}}"), codeExceptOutsideCIDR},
+		{"ports not array", ingressFrom("{ports: {port: 80}}"), codeNetPolListType},
+		{"port not object", netpolPort("80"), codePortItemType},
+		{"named port", netpolPort("{port: http}"), ""},
+		{"port name format", netpolPort("{port: HTTP_PORT}"), codeNetPolPortName},
+		{"port range", netpolPort("{port: 8000, endPort: 8080}"), ""},
+		{"endPort with name", netpolPort("{port: http, endPort: 8080}"), codeEndPort},
+		{"endPort below port", netpolPort("{port: 8080, endPort: 8000}"), codeEndPort},
+		{"protocol case", netpolPort("{port: 53, protocol: udp}"), codeProtocolCase},
+	})
+}
@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestIsBinary(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want bool
+	}{
+		{[]byte("apiVersion: v1\nkind: Pod\n"), false},
+		{[]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), true},
+		// NUL дальше первых 8000 байт — не бинарный, как у git
+		{append([]byte(strings.Repeat("a", 8000)), 0), false},
+	}
+	for i, tt := range tests {
+		if got := isBinary(tt.data); got != tt.want {
+			t.Errorf("case %d: isBinary = %v, want %v", i, got, tt.want)
+		}
+	}
+}
+
+func TestLooksLikeManifest(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want bool
+	}{
+		{"apiVersion: v1\nkind: Pod\n", true},
+		{"kind: Pod\n", true},
+		{"apiVersion: v1\n", true},
+		{"name: ci\non: [push]\n", false},
+		// не маппинг — пусть валидатор скажет "root must be object"
+		{"- a\n- b\n", true},
+	}
+	for _, tt := range tests {
+		var root yaml.Node
+		if err := yaml.Unmarshal([]byte(tt.doc), &root); err != nil {
+			t.Fatal(err)
+		}
+		if got := looksLikeManifest(root.Content[0]); got != tt.want {
+			t.Errorf("looksLikeManifest(%q) = %v, want %v", tt.doc, got, tt.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for name, want := range severityNames {
+		if got, err := ParseSeverity(name); err != nil || got != want {
+			t.Errorf("ParseSeverity(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParseSeverity("fatal"); err == nil {
+		t.Error("unknown severity accepted")
+	}
+}
+
+// Посторонний YAML пропускается только при обходе каталога; файл,
+// который назвали явно, проверяется целиком.
+func TestNonManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		scanned bool
+		want    string
+	}{
+		{"scanned config", "foo: 1\n", true, codeNotManifest},
+		{"named config", "foo: 1\n", false, strings.Join([]string{codeAPIVersionRequired, codeKindRequired}, " ")},
+		{"scanned binary", "\x00\x01", true, codeBinarySkipped},
+		{"scanned manifest", "apiVersion: v1\n", true, codeKindRequired},
+	}
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		validate := v.ValidateFile
+		if tt.scanned {
+			validate = v.ValidateScanned
+		}
+		res, err := validate("", []byte(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := codes(res); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: codes %q, want prefix %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Бинарный файл, названный явно, разбирается как есть: ошибку разбора
+// или находки даёт разборщик, но не заметка о пропуске.
+func TestNamedBinaryIsParsed(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.ValidateFile("", []byte("\x00\x01"))
+	if err == nil && strings.Contains(codes(res), codeBinarySkipped) {
+		t.Error("binary file named explicitly was skipped")
+	}
+}
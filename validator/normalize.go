@@ -0,0 +1,156 @@
+// normalize.go
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// NormalizedDocument — документ в каноническом JSON: ключи по алфавиту,
+// алиасы и <<: развёрнуты, регистр значений перечислений исправлен.
+// Такой вывод можно сравнивать diff'ом независимо от стиля YAML.
+type NormalizedDocument struct {
+	Kind      string
+	Namespace string
+	Name      string
+	JSON      []byte
+}
+
+// Normalize переводит документы data в канонический JSON. issues —
+// находки этого файла после Adjust: применяются их правки значений (тот
+// же слой, что у --fix), удаления строк — нет, это уже не нормализация.
+// Пустые документы и документы вне фильтров пропускаются.
+func (v *Validator) Normalize(data []byte, issues []Issue) ([]NormalizedDocument, error) {
+	var values []Issue
+	for _, is := range issues {
+		if is.Fix != nil && is.Fix.Lines == 0 {
+			values = append(values, is)
+		}
+	}
+	data, _ = ApplyFixes(data, values)
+	docs, err := v.rules.engine.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	var out []NormalizedDocument
+	for _, doc := range docs {
+		if isNullDocument(doc) || !v.rules.filter.allows(doc) {
+			continue
+		}
+		val, err := plainValue(doc)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", doc.Line, err)
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(val); err != nil {
+			return nil, fmt.Errorf("line %d: %v", doc.Line, err)
+		}
+		nd := NormalizedDocument{Kind: kindOf(doc), JSON: buf.Bytes()}
+		if meta, _ := child(doc, "metadata"); meta != nil {
+			if ns, _ := child(meta, "namespace"); ns != nil && isScalarString(ns) {
+				nd.Namespace = ns.Value
+			}
+			if name, _ := child(meta, "name"); name != nil && isScalarString(name) {
+				nd.Name = name.Value
+			}
+		}
+		out = append(out, nd)
+	}
+	return out, nil
+}
+
+// plainValue — значение узла для encoding/json, который сам сортирует
+// ключи. Метки времени остаются строками, как их видит Kubernetes.
+func plainValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return plainValue(n.Content[0])
+	case yaml.AliasNode:
+		return plainValue(n.Alias)
+	case yaml.SequenceNode:
+		out := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := plainValue(c)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	case yaml.MappingNode:
+		out := map[string]any{}
+		if err := mergeMapping(out, n); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	switch n.Tag {
+	case "!!null":
+		return nil, nil
+	case "!!bool", "!!int", "!!float":
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, err
+		}
+		if f, ok := v.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return n.Value, nil
+		}
+		return v, nil
+	}
+	return n.Value, nil
+}
+
+// mergeMapping добавляет пары n в out; ключи из <<: не перекрывают
+// собственные ключи маппинга, как в YAML merge.
+func mergeMapping(out map[string]any, n *yaml.Node) error {
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Tag == "!!merge" {
+			merges = append(merges, v)
+			continue
+		}
+		val, err := plainValue(v)
+		if err != nil {
+			return err
+		}
+		out[k.Value] = val
+	}
+	for _, m := range merges {
+		if m.Kind == yaml.AliasNode {
+			m = m.Alias
+		}
+		srcs := []*yaml.Node{m}
+		if m.Kind == yaml.SequenceNode {
+			srcs = m.Content
+		}
+		for _, src := range srcs {
+			if src.Kind == yaml.AliasNode {
+				src = src.Alias
+			}
+			if src.Kind != yaml.MappingNode {
+				return fmt.Errorf("<< must merge a mapping")
+			}
+			merged := map[string]any{}
+			if err := mergeMapping(merged, src); err != nil {
+				return err
+			}
+			for k, v := range merged {
+				if _, ok := out[k]; !ok {
+					out[k] = v
+				}
+			}
+		}
+	}
+	return nil
+}
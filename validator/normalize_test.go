@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := strings.Replace(pod("      ports: [{containerPort: 8080, protocol: tcp}]\n", ""),
+		"  name: web\n", "  namespace: prod\n  name: web\n  labels: &l {app: web}\n  annotations:\n    <<: *l\n    app: own\n", 1) +
+		"---\n---\nkind: ConfigMap\ndata: {b: \"1\", a: yes}\n"
+	res, err := v.Validate([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := v.Normalize([]byte(data), res.Issues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("%d documents, want 2", len(docs))
+	}
+	if d := docs[0]; d.Kind != "Pod" || d.Namespace != "prod" || d.Name != "web" {
+		t.Errorf("pod %q/%q/%q", d.Kind, d.Namespace, d.Name)
+	}
+	got := string(docs[0].JSON)
+	for _, want := range []string{`"protocol": "TCP"`, `"annotations": {
+      "app": "own"
+    }`, `"labels": {
+      "app": "web"
+    }`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("pod JSON lacks %s:\n%s", want, got)
+		}
+	}
+	if want := "{\n  \"data\": {\n    \"a\": \"yes\",\n    \"b\": \"1\"\n  },\n  \"kind\": \"ConfigMap\"\n}\n"; string(docs[1].JSON) != want {
+		t.Errorf("configmap JSON:\n%s\nwant:\n%s", docs[1].JSON, want)
+	}
+}
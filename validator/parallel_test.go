@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Паника правила должна дойти до вызывающего, а не уронить процесс в
+// рабочей горутине: её ловит guard в CLI. Из горутины она приходит как
+// WorkerPanic, при одном ядре — как есть.
+func TestRulePanicReachesCaller(t *testing.T) {
+	saved := ruleGroups
+	ruleGroups = append(append([]func(*ValidationContext, *errBag){}, saved...), func(ctx *ValidationContext, bag *errBag) {
+		panic("rule exploded")
+	})
+	defer func() { ruleGroups = saved }()
+
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n"
+	tests := map[string]string{
+		// один документ: параллельны группы правил
+		"one document": doc,
+		// несколько документов: параллельны документы
+		"many documents": strings.Repeat(doc+"---\n", 8),
+	}
+	for name, data := range tests {
+		func() {
+			defer func() {
+				v := recover()
+				if wp, ok := v.(WorkerPanic); ok {
+					if len(wp.Stack) == 0 {
+						t.Errorf("%s: WorkerPanic without stack", name)
+					}
+					v = wp.Value
+				}
+				if v != "rule exploded" {
+					t.Errorf("%s: recovered %#v, want the rule's panic", name, v)
+				}
+			}()
+			v.ValidateFile("", []byte(data))
+		}()
+	}
+}
+
+func TestParallelRunsAll(t *testing.T) {
+	var hits [50]int
+	parallel(len(hits), 4, func(i int) { hits[i]++ })
+	for i, h := range hits {
+		if h != 1 {
+			t.Fatalf("fn(%d) called %d times", i, h)
+		}
+	}
+}
+
+// Находки документов склеиваются в порядке документов, как бы ни
+// распределились горутины; пустые документы пропускаются.
+func TestDocumentsOrdered(t *testing.T) {
+	var docs []string
+	for i := 0; i < 20; i++ {
+		docs = append(docs, pod("", fmt.Sprintf("  os: {name: os%d}\n", i)))
+	}
+	res := validate(t, Config{}, strings.Join(docs, "---\n")+"---\n")
+	if len(res.Issues) != len(docs) {
+		t.Fatalf("codes %q, want one per document", codes(res))
+	}
+	for i, is := range res.Issues {
+		if want := fmt.Sprintf("'os%d'", i); !strings.Contains(is.Message(), want) {
+			t.Errorf("finding %d: %q, want %s", i, is.Message(), want)
+		}
+	}
+}
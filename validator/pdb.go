@@ -0,0 +1,58 @@
+// pdb.go
+package validator
+
+import (
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var evictionPolicies = []string{"AlwaysAllow", "IfHealthyBudget"}
+
+// validatePDBSpec — бюджет задаётся ровно одним из minAvailable и
+// maxUnavailable: числом или процентом не больше 100%.
+func validatePDBSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codePDBSpecType)
+		return
+	}
+	minAvailable, hasMin := m.get("minAvailable")
+	maxUnavailable, hasMax := m.get("maxUnavailable")
+	if hasMin {
+		validateBudget(minAvailable, "minAvailable", bag)
+	}
+	if hasMax {
+		validateBudget(maxUnavailable, "maxUnavailable", bag)
+	}
+	switch {
+	case !hasMin && !hasMax:
+		bag.add(nil, codePDBBudgetRequired)
+	case hasMin && hasMax:
+		bag.add(maxUnavailable, codePDBBudgetExclusive)
+	}
+
+	// selector (optional): в policy/v1 {} выбирает все поды неймспейса
+	if sel, ok := m.get("selector"); ok {
+		checkLabelSelector(sel, bag)
+	}
+
+	if p, ok := m.get("unhealthyPodEvictionPolicy"); ok {
+		if !isScalarString(p) || !contains(evictionPolicies, p.Value) {
+			unsupported(bag, p, codeEvictionPolicyUnsupported, codeEvictionPolicyCase, evictionPolicies)
+		}
+	}
+}
+
+func validateBudget(v *yaml.Node, field string, bag *errBag) {
+	if !intOrPercent(v) {
+		bag.add(v, codeIntOrPercent, field)
+		return
+	}
+	if isScalarString(v) {
+		if pct, err := strconv.Atoi(strings.TrimSuffix(v.Value, "%")); err != nil || pct > 100 {
+			bag.add(v, codePDBPercent, field)
+		}
+	}
+}
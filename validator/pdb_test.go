@@ -0,0 +1,25 @@
+package validator
+
+import "testing"
+
+// pdb — PodDisruptionBudget со строками spec (отступ 2).
+func pdb(spec string) string {
+	return "apiVersion: policy/v1\nkind: PodDisruptionBudget\nmetadata:\n  name: web\nspec:\n" + spec
+}
+
+func TestPDB(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"minAvailable", pdb("  minAvailable: 2\n  selector: {matchLabels: {app: web}}\n"), ""},
+		{"maxUnavailable percent", pdb("  maxUnavailable: 25%\n"), ""},
+		{"all pods", pdb("  minAvailable: 100%\n  selector: {}\n"), ""},
+		{"eviction policy", pdb("  maxUnavailable: 1\n  unhealthyPodEvictionPolicy: AlwaysAllow\n"), ""},
+		{"spec not object", "apiVersion: policy/v1\nkind: PodDisruptionBudget\nmetadata:\n  name: web\nspec: [minAvailable]\n", codePDBSpecType},
+		{"budget missing", pdb("  selector: {matchLabels: {app: web}}\n"), codePDBBudgetRequired},
+		{"both budgets", pdb("  minAvailable: 1\n  maxUnavailable: 1\n"), codePDBBudgetExclusive},
+		{"percent over 100", pdb("  maxUnavailable: 150%\n"), codePDBPercent},
+		{"budget not int or percent", pdb("  minAvailable: half\n"), codeIntOrPercent},
+		{"selector invalid", pdb("  minAvailable: 1\n  selector: {matchLabels: [app]}\n"), codeMatchLabelsType},
+		{"eviction policy unsupported", pdb("  maxUnavailable: 1\n  unhealthyPodEvictionPolicy: Never\n"), codeEvictionPolicyUnsupported},
+		{"eviction policy case", pdb("  maxUnavailable: 1\n  unhealthyPodEvictionPolicy: ifhealthybudget\n"), codeEvictionPolicyCase},
+	})
+}
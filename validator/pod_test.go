@@ -0,0 +1,123 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObjectRules(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", pod("", ""), ""},
+		{"root not object", "- a\n- b\n", codeRootType},
+		{"apiVersion missing", strings.Replace(pod("", ""), "apiVersion: v1\n", "", 1), codeAPIVersionRequired},
+		{"apiVersion not string", strings.Replace(pod("", ""), "apiVersion: v1", "apiVersion: [v1]", 1), codeAPIVersionType},
+		{"apiVersion unsupported", strings.Replace(pod("", ""), "apiVersion: v1", "apiVersion: apps/v1", 1), codeAPIVersionUnsupported},
+		{"kind missing", strings.Replace(pod("", ""), "kind: Pod\n", "", 1), codeKindRequired},
+		{"kind not string", strings.Replace(pod("", ""), "kind: Pod", "kind: {a: 1}", 1), codeKindType},
+		{"kind unsupported", strings.Replace(pod("", ""), "kind: Pod", "kind: Widget", 1), codeKindUnsupported},
+		{"metadata missing", "apiVersion: v1\nkind: Pod\nspec:\n  containers: []\n", codeMetadataRequired},
+		{"spec missing", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\n", codeSpecRequired},
+	})
+}
+
+func TestMetadataRules(t *testing.T) {
+	meta := func(m string) string {
+		return strings.Replace(pod("", ""), "metadata:\n  name: web\n", m, 1)
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"metadata not object", meta("metadata: web\n"), codeMetadataType},
+		{"name missing", meta("metadata:\n  namespace: prod\n"), codeNameRequired},
+		{"name not string", meta("metadata:\n  name: [web]\n"), codeNameType},
+		{"namespace not string", meta("metadata:\n  name: web\n  namespace: 42\n"), codeNamespace},
+		{"labels not object", meta("metadata:\n  name: web\n  labels: [app]\n"), codeLabelsType},
+	})
+}
+
+func TestPodSpecRules(t *testing.T) {
+	spec := func(s string) string {
+		return "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:" + s
+	}
+	second := "    - name: web\n      image: registry.bigbrother.io/web:1.0\n" +
+		"      resources:\n        requests: {cpu: 1, memory: 64Mi}\n        limits: {cpu: 1, memory: 64Mi}\n"
+	checkRules(t, Config{}, []ruleCase{
+		{"spec not object", spec(" [web]\n"), codeSpecType},
+		{"containers missing", spec("\n  restartPolicy: Always\n"), codeContainersRequired},
+		{"containers not array", spec("\n  containers: web\n"), codeContainersType},
+		{"containers empty", spec("\n  containers: []\n"), codeContainersEmpty},
+		{"duplicate name", pod(second, ""), codeContainerNameDup},
+		{"os scalar", pod("", "  os: linux\n"), ""},
+		{"os not string", pod("", "  os: 42\n"), codeOSType},
+		{"os array", pod("", "  os: [linux]\n"), codeOSType},
+		{"os unsupported", pod("", "  os: {name: plan9}\n"), codeOSUnsupported},
+		{"os name missing", pod("", "  os: {}\n"), codeOSNameRequired},
+		{"os name not string", pod("", "  os: {name: [linux]}\n"), codeOSNameType},
+	})
+}
+
+func TestContainerRules(t *testing.T) {
+	container := func(c string) string {
+		return "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n" + c
+	}
+	res := "      resources:\n        requests: {cpu: 1, memory: 64Mi}\n        limits: {cpu: 1, memory: 64Mi}\n"
+	checkRules(t, Config{}, []ruleCase{
+		{"not object", container("    - web\n"), codeContainerType},
+		{"name missing", container("    - image: registry.bigbrother.io/web:1.0\n" + res), codeContainerNameReq},
+		{"name not string", container("    - name: [web]\n      image: registry.bigbrother.io/web:1.0\n" + res), codeContainerNameType},
+		{"name format", container("    - name: Web_1\n      image: registry.bigbrother.io/web:1.0\n" + res), codeContainerNameFormat},
+		{"image missing", container("    - name: web\n" + res), codeImageRequired},
+		{"image not string", container("    - name: web\n      image: [web]\n" + res), codeImageType},
+		{"image format", container("    - name: web\n      image: nginx\n" + res), codeImageFormat},
+		{"resources missing", container("    - name: web\n      image: registry.bigbrother.io/web:1.0\n"), codeResourcesRequired},
+		{"ports not array", pod("      ports: 8080\n", ""), codePortsType},
+		{"port item not object", pod("      ports: [8080]\n", ""), codePortItemType},
+		{"containerPort missing", pod("      ports:\n        - name: http\n", ""), codeContainerPortReq},
+		{"protocol not string", pod("      ports:\n        - containerPort: 8080\n          protocol: [TCP]\n", ""), codeProtocolType},
+		{"protocol unsupported", pod("      ports:\n        - containerPort: 8080\n          protocol: HTTP\n", ""), codeProtocolUnsupported},
+	})
+}
+
+func TestProbeRules(t *testing.T) {
+	probe := func(p string) string { return pod("      livenessProbe:"+p, "") }
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", probe("\n        httpGet: {path: /healthz, port: 8080}\n"), ""},
+		{"not object", probe(" /healthz\n"), codeProbeType},
+		{"httpGet missing", probe("\n        periodSeconds: 5\n"), codeHTTPGetRequired},
+		{"httpGet not object", probe("\n        httpGet: /healthz\n"), codeHTTPGetType},
+		{"path missing", probe("\n        httpGet: {port: 8080}\n"), codePathRequired},
+		{"path not string", probe("\n        httpGet: {path: [a], port: 8080}\n"), codePathType},
+		{"path relative", probe("\n        httpGet: {path: healthz, port: 8080}\n"), codePathFormat},
+		{"port missing", probe("\n        httpGet: {path: /healthz}\n"), codeProbePortReq},
+	})
+}
+
+func TestResourceRules(t *testing.T) {
+	resources := func(r string) string {
+		return strings.Replace(pod("", ""), "      resources:\n        requests: {cpu: 1, memory: 64Mi}\n        limits: {cpu: 1, memory: 64Mi}\n", "      resources:"+r, 1)
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"not object", resources(" 1Gi\n"), codeResourcesType},
+		{"requests not object", resources("\n        requests: 1Gi\n"), codeResourceMap},
+		{"cpu not int", resources("\n        requests: {cpu: 100m}\n"), codeCPUType},
+		{"memory not string", resources("\n        limits: {memory: 64}\n"), codeMemoryType},
+		{"memory format", resources("\n        limits: {memory: 64MB}\n"), codeMemoryFormat},
+	})
+}
+
+// Шаблон сообщения подставляется только при выводе и берётся из
+// каталога; код без шаблона печатается как есть.
+func TestIssueRender(t *testing.T) {
+	is := Issue{Code: codePortTooLarge, Args: []any{"hostPort"}}
+	if got := is.Message(); got != "hostPort must not exceed 65535" {
+		t.Errorf("Message() = %q", got)
+	}
+	ru := Catalog{codePortTooLarge: "%s не больше 65535"}
+	if got := is.Render(ru); got != "hostPort не больше 65535" {
+		t.Errorf("Render(ru) = %q", got)
+	}
+	if got := (Issue{Code: codeSpecType}).Render(ru); got != "spec must be object" {
+		t.Errorf("fallback to English: %q", got)
+	}
+	if got := (Issue{Code: "XYZ999"}).Message(); got != "XYZ999" {
+		t.Errorf("unknown code: %q", got)
+	}
+}
@@ -0,0 +1,21 @@
+package validator
+
+import "testing"
+
+func TestPortValues(t *testing.T) {
+	port := func(field, value string) string {
+		return pod("      ports:\n        - containerPort: 8080\n          "+field+": "+value+"\n", "")
+	}
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", pod("      ports:\n        - containerPort: 8080\n", ""), ""},
+		{"hex", pod("      ports:\n        - containerPort: 0x1F90\n", ""), ""},
+		{"not int", pod("      ports:\n        - containerPort: http\n", ""), codePortNotInt},
+		{"float", pod("      ports:\n        - containerPort: 80.5\n", ""), codePortNotInt},
+		{"zero", pod("      ports:\n        - containerPort: 0\n", ""), codePortNotPositive},
+		{"negative", pod("      ports:\n        - containerPort: -80\n", ""), codePortNotPositive},
+		{"too large", pod("      ports:\n        - containerPort: 65536\n", ""), codePortTooLarge},
+		{"hostPort zero", port("hostPort", "0"), codePortNotPositive},
+		{"hostPort too large", port("hostPort", "70000"), codePortTooLarge},
+		{"probe port", pod("      livenessProbe:\n        httpGet: {path: /healthz, port: 0}\n", ""), codePortNotPositive},
+	})
+}
@@ -0,0 +1,24 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPriorityClass(t *testing.T) {
+	inKubeSystem := strings.Replace(pod("", "  priorityClassName: system-node-critical\n"),
+		"  name: web\n", "  name: web\n  namespace: kube-system\n", 1)
+	checkRules(t, Config{}, []ruleCase{
+		{"any class", pod("", "  priorityClassName: high\n"), ""},
+		{"not string", pod("", "  priorityClassName: [high]\n"), codePriorityClassType},
+		{"format", pod("", "  priorityClassName: High_Priority\n"), codePriorityClassFormat},
+		{"system outside kube-system", pod("", "  priorityClassName: system-cluster-critical\n"), codePriorityClassSystem},
+		{"system in kube-system", inKubeSystem, ""},
+	})
+	checkRules(t, Config{PriorityClasses: []string{"high", "low"}}, []ruleCase{
+		{"known", pod("", "  priorityClassName: low\n"), ""},
+		{"unknown", pod("", "  priorityClassName: urgent\n"), codePriorityClassUnknown},
+		// встроенные классы есть в любом кластере
+		{"builtin", inKubeSystem, ""},
+	})
+}
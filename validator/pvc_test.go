@@ -0,0 +1,22 @@
+package validator
+
+import "testing"
+
+// pvc — PersistentVolumeClaim на 1Gi ReadWriteOnce со строками spec.
+func pvc(spec string) string {
+	return "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: data\nspec:\n" +
+		"  accessModes: [ReadWriteOnce]\n  resources: {requests: {storage: 1Gi}}\n" + spec
+}
+
+func TestPersistentVolumeClaim(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", pvc("  storageClassName: fast-ssd\n  volumeMode: Block\n"), ""},
+		{"no class", pvc("  storageClassName: \"\"\n"), ""},
+		{"spec not object", "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: data\nspec: 1Gi\n", codeClaimSpecType},
+		{"storage missing", "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: data\nspec:\n  accessModes: [ReadWriteOnce]\n", codeStorageRequired},
+		{"class not string", pvc("  storageClassName: [fast]\n"), codeStorageClassType},
+		{"class format", pvc("  storageClassName: Fast_SSD\n"), codeStorageClassFormat},
+		{"volumeMode", pvc("  volumeMode: Raw\n"), codeVolumeModeUnsupported},
+		{"volumeMode case", pvc("  volumeMode: filesystem\n"), codeVolumeModeCase},
+	})
+}
@@ -0,0 +1,159 @@
+// quota.go
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Квоты считаются по всему набору файлов, поэтому ядро только собирает
+// данные: requests подов и лимиты ResourceQuota. Суммирует и сравнивает CLI.
+
+// Workload — суммарные requests одного пода: CPU в ядрах, Memory в байтах.
+type Workload struct {
+	Line      int     `json:"line"`
+	Namespace string  `json:"namespace"`
+	CPU       float64 `json:"cpu"`
+	Memory    float64 `json:"memory"`
+}
+
+// Quota — лимиты requests из ResourceQuota или файла бюджетов; 0 — без
+// лимита.
+type Quota struct {
+	Namespace string  `json:"namespace"`
+	CPU       float64 `json:"cpu,omitempty"`
+	Memory    float64 `json:"memory,omitempty"`
+}
+
+var quantitySuffixes = map[string]float64{
+	"m": 1e-3, "": 1, "k": 1e3, "M": 1e6, "G": 1e9, "T": 1e12,
+	"Ki": 1 << 10, "Mi": 1 << 20, "Gi": 1 << 30, "Ti": 1 << 40,
+}
+
+// ParseQuantity разбирает количество ресурса Kubernetes (500m, 2, 1.5Gi)
+// в число базовых единиц.
+func ParseQuantity(s string) (float64, error) {
+	num := strings.TrimRightFunc(s, func(r rune) bool { return r >= 'A' && r <= 'z' })
+	mult, ok := quantitySuffixes[s[len(num):]]
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity '%s'", s)
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid quantity '%s'", s)
+	}
+	return f * mult, nil
+}
+
+func namespaceOf(doc *yaml.Node) string {
+	if meta, _ := child(doc, "metadata"); meta != nil {
+		if ns, _ := child(meta, "namespace"); ns != nil && isScalarString(ns) && ns.Value != "" {
+			return ns.Value
+		}
+	}
+	return "default"
+}
+
+// collectWorkload — requests пода по правилу kube-scheduler: сумма по
+// контейнерам, но не меньше самого большого init-контейнера; у
+// контроллеров — умноженные на replicas.
+func collectWorkload(ctx *ValidationContext, bag *errBag) {
+	if ctx.PodSpec == nil {
+		return
+	}
+	doc := ctx.Doc
+	w := Workload{Line: doc.Line, Namespace: namespaceOf(doc)}
+	w.CPU, w.Memory = podResources(ctx, "requests")
+	replicas := float64(replicasOf(doc))
+	w.CPU, w.Memory = w.CPU*replicas, w.Memory*replicas
+	if w.CPU > 0 || w.Memory > 0 {
+		bag.workloads = append(bag.workloads, w)
+	}
+}
+
+// podResources — requests или limits (field) одного пода: сумма по
+// контейнерам, но не меньше самого большого init-контейнера.
+func podResources(ctx *ValidationContext, field string) (cpu, mem float64) {
+	var initCPU, initMem float64
+	for _, c := range ctx.Containers {
+		ccpu, cmem := containerResources(c.Node, field)
+		if c.Init {
+			initCPU, initMem = max(initCPU, ccpu), max(initMem, cmem)
+			continue
+		}
+		cpu += ccpu
+		mem += cmem
+	}
+	return max(cpu, initCPU), max(mem, initMem)
+}
+
+// containerResources — cpu и memory контейнера из resources.requests
+// или resources.limits (field).
+func containerResources(c *yaml.Node, field string) (cpu, mem float64) {
+	res, _ := child(c, "resources")
+	if res == nil {
+		return 0, 0
+	}
+	req, _ := child(res, field)
+	if req == nil {
+		return 0, 0
+	}
+	if v, ok := child(req, "cpu"); ok {
+		cpu, _ = ParseQuantity(v.Value)
+	}
+	if v, ok := child(req, "memory"); ok {
+		mem, _ = ParseQuantity(v.Value)
+	}
+	return cpu, mem
+}
+
+// collectQuota читает spec.hard у ResourceQuota; вызывается до проверки
+// kind, чтобы квоты учитывались и с --skip-unknown-kinds.
+func collectQuota(doc *yaml.Node, bag *errBag) {
+	spec, _ := child(doc, "spec")
+	if spec == nil {
+		return
+	}
+	hard, _ := child(spec, "hard")
+	if hard == nil {
+		return
+	}
+	q, err := QuotaFromHard(namespaceOf(doc), func(key string) (string, bool) {
+		v, ok := child(hard, key)
+		if !ok || !isScalarString(v) && !isScalarInt(v) {
+			return "", false
+		}
+		return v.Value, true
+	})
+	if err == nil {
+		bag.quotas = append(bag.quotas, q)
+	}
+}
+
+// QuotaFromHard собирает Quota из ключей spec.hard: requests.cpu или cpu,
+// requests.memory или memory.
+func QuotaFromHard(namespace string, get func(key string) (string, bool)) (Quota, error) {
+	q := Quota{Namespace: namespace}
+	for _, f := range []struct {
+		keys []string
+		dst  *float64
+	}{
+		{[]string{"requests.cpu", "cpu"}, &q.CPU},
+		{[]string{"requests.memory", "memory"}, &q.Memory},
+	} {
+		for _, key := range f.keys {
+			if s, ok := get(key); ok {
+				v, err := ParseQuantity(s)
+				if err != nil {
+					return q, fmt.Errorf("%s: %v", key, err)
+				}
+				*f.dst = v
+				break
+			}
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,25 @@
+package validator
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		ok   bool
+	}{
+		{"500m", 0.5, true},
+		{"2", 2, true},
+		{"1.5Gi", 1.5 * (1 << 30), true},
+		{"1k", 1000, true},
+		{"1Xi", 0, false},
+		{"-1", 0, false},
+		{"Mi", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuantity(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseQuantity(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
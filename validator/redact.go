@@ -0,0 +1,130 @@
+// redact.go
+package validator
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// redacted — чем заменяется секрет в сообщениях и строках исходника.
+const redacted = "***"
+
+// minSecretLen — значения короче не маскируем: это не пароли, а замена
+// "1" или "yes" по всему сообщению сделала бы его нечитаемым.
+const minSecretLen = 4
+
+// reSecretEnvName — переменные окружения, в значениях которых обычно
+// лежат учётные данные.
+var reSecretEnvName = regexp.MustCompile(`(?i)(passw(or)?d|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|credential|auth)`)
+
+// reSecretValue — значения, похожие на учётные данные при любом имени
+// переменной: ключи AWS, токены GitHub и Slack, JWT, PEM-ключи и URL с
+// паролем.
+var reSecretValue = regexp.MustCompile(`AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36}|xox[abprs]-[A-Za-z0-9-]{10,}|eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+|-----BEGIN [A-Z ]*PRIVATE KEY-----|://[^/:@\s]+:[^/@\s]+@`)
+
+// collectSecrets запоминает чувствительные значения документа: data и
+// stringData у Secret и env контейнеров с секретными именами или
+// значениями. Сами проверки их не трогают — по ним CLI маскирует вывод.
+func collectSecrets(ctx *ValidationContext, bag *errBag) {
+	if ctx.Kind == "Secret" {
+		for _, field := range []string{"data", "stringData"} {
+			data, _ := child(ctx.Doc, field)
+			if data == nil || data.Kind != yaml.MappingNode {
+				continue
+			}
+			for i := 1; i < len(data.Content); i += 2 {
+				v := data.Content[i]
+				bag.secret(v.Value)
+				if field == "data" {
+					if raw, err := base64.StdEncoding.DecodeString(v.Value); err == nil {
+						bag.secret(string(raw))
+					}
+				}
+			}
+		}
+	}
+	for _, c := range ctx.Containers {
+		env, _ := child(c.Node, "env")
+		if env == nil || env.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, e := range env.Content {
+			name, _ := child(e, "name")
+			value, _ := child(e, "value")
+			if value == nil || value.Kind != yaml.ScalarNode {
+				continue
+			}
+			if name != nil && reSecretEnvName.MatchString(name.Value) || reSecretValue.MatchString(value.Value) {
+				bag.secret(value.Value)
+			}
+		}
+	}
+}
+
+// secret запоминает значение; у многострочного — ещё и каждую строку:
+// в исходнике блочный скаляр разбит отступами.
+func (e *errBag) secret(s string) {
+	s = strings.TrimSpace(s)
+	if len(s) < minSecretLen {
+		return
+	}
+	e.secrets = append(e.secrets, s)
+	if strings.Contains(s, "\n") {
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); len(line) >= minSecretLen {
+				e.secrets = append(e.secrets, line)
+			}
+		}
+	}
+}
+
+// Redact заменяет чувствительные значения в аргументах, подсказках и
+// правках находок на ***. Повторный вызов ничего не меняет.
+func (r *Result) Redact() {
+	if len(r.Secrets) == 0 {
+		return
+	}
+	mask := r.masker()
+	for i := range r.Issues {
+		is := &r.Issues[i]
+		for j, a := range is.Args {
+			if s, ok := a.(string); ok {
+				is.Args[j] = mask.Replace(s)
+			} else if str, ok := a.(fmt.Stringer); ok {
+				is.Args[j] = mask.Replace(str.String())
+			}
+		}
+		is.Help = mask.Replace(is.Help)
+		if is.Fix != nil {
+			fix := *is.Fix
+			fix.Old, fix.New = mask.Replace(fix.Old), mask.Replace(fix.New)
+			is.Fix = &fix
+		}
+	}
+}
+
+// RedactSource — исходник с замаскированными значениями, для строк с
+// кареткой в --show-source.
+func (r *Result) RedactSource(data []byte) []byte {
+	if len(r.Secrets) == 0 {
+		return data
+	}
+	return []byte(r.masker().Replace(string(data)))
+}
+
+// masker заменяет сначала длинные значения: короткий секрет может быть
+// частью длинного.
+func (r *Result) masker() *strings.Replacer {
+	secrets := append([]string(nil), r.Secrets...)
+	sort.SliceStable(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	pairs := make([]string, 0, 2*len(secrets))
+	for _, s := range secrets {
+		pairs = append(pairs, s, redacted)
+	}
+	return strings.NewReplacer(pairs...)
+}
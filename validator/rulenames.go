@@ -0,0 +1,379 @@
+// rulenames.go
+package validator
+
+import "sort"
+
+// ruleNames — стабильные имена проверок рядом с кодами: код короткий и
+// удобен в фильтрах, имя читается без каталога. Ни коды, ни имена не
+// переименовываются — на них завязаны подавления, переопределения
+// severity и внешние отчёты; устаревшая проверка оставляет свой код
+// занятым.
+var ruleNames = map[string]string{
+	codeRootType:              "root-type",
+	codeAPIVersionRequired:    "apiVersion-required",
+	codeAPIVersionType:        "apiVersion-type",
+	codeAPIVersionUnsupported: "apiVersion-unsupported",
+	codeKindRequired:          "kind-required",
+	codeKindType:              "kind-type",
+	codeKindUnsupported:       "kind-unsupported",
+	codeMetadataRequired:      "metadata-required",
+	codeSpecRequired:          "spec-required",
+	codeAPIVersionCase:        "apiVersion-wrong-case",
+	codeKindCase:              "kind-wrong-case",
+
+	codeMetadataType: "metadata-type",
+	codeNameRequired: "name-required",
+	codeNameType:     "name-type",
+	codeNamespace:    "namespace-type",
+	codeLabelsType:   "labels-type",
+	CodeServerField:  "server-populated-field",
+
+	codeSpecType:            "pod-spec-type",
+	codeContainersRequired:  "containers-required",
+	codeContainersType:      "containers-type",
+	codeContainersEmpty:     "containers-empty",
+	codeContainerNameDup:    "container-name-duplicate",
+	codeOSType:              "os-type",
+	codeOSUnsupported:       "os-unsupported",
+	codeOSNameRequired:      "os-name-required",
+	codeOSNameType:          "os-name-type",
+	codePriorityClassType:   "priorityClassName-type",
+	codePriorityClassFormat: "priorityClassName-format",
+	codeOSCase:              "os-wrong-case",
+	codeInitContainersType:  "initContainers-type",
+	codeContainerType:       "container-type",
+	codeContainerNameReq:    "container-name-required",
+	codeContainerNameType:   "container-name-type",
+	codeContainerNameFormat: "container-name-format",
+	codeImageRequired:       "image-required",
+	codeImageType:           "image-type",
+	codeImageFormat:         "image-format",
+	codePortsType:           "ports-type",
+	codeResourcesRequired:   "resources-required",
+
+	codePortNotInt:          "port-not-int",
+	codePortNotPositive:     "port-not-positive",
+	codePortTooLarge:        "port-too-large",
+	codePortItemType:        "port-item-type",
+	codeContainerPortReq:    "containerPort-required",
+	codeProtocolType:        "protocol-type",
+	codeProtocolUnsupported: "protocol-unsupported",
+	codeProtocolCase:        "protocol-wrong-case",
+
+	codeProbeType:       "probe-type",
+	codeHTTPGetRequired: "httpGet-required",
+	codeHTTPGetType:     "httpGet-type",
+	codePathRequired:    "httpGet-path-required",
+	codePathType:        "httpGet-path-type",
+	codePathFormat:      "httpGet-path-format",
+	codeProbePortReq:    "httpGet-port-required",
+	codeProbeTimeout:    "probe-timeout-exceeds-period",
+
+	codeResourcesType: "resources-type",
+	codeResourceMap:   "resource-list-type",
+	codeCPUType:       "cpu-type",
+	codeMemoryType:    "memory-type",
+	codeMemoryFormat:  "memory-format",
+
+	codeEnvType:                "env-type",
+	codeEnvItemType:            "env-item-type",
+	codeEnvNameRequired:        "env-name-required",
+	codeEnvNameFormat:          "env-name-format",
+	codeEnvValueExclusive:      "env-value-exclusive",
+	codeEnvValueType:           "env-value-type",
+	codeValueFromType:          "valueFrom-type",
+	codeValueFromSource:        "valueFrom-source",
+	codeEnvRefType:             "env-ref-type",
+	codeEnvRefField:            "env-ref-field-required",
+	codeEnvRefFieldType:        "env-ref-field-type",
+	codeEnvResourceUnsupported: "env-resource-unsupported",
+	codeEnvResourceCase:        "env-resource-wrong-case",
+	codeEnvDivisor:             "env-divisor-format",
+
+	codeVolumeMountsType:     "volumeMounts-type",
+	codeVolumeMountType:      "volumeMounts-item-type",
+	codeVolumeMountName:      "volumeMounts-name-required",
+	codeVolumeMountFieldType: "volumeMounts-field-type",
+	codeMountPathRequired:    "mountPath-required",
+	codeMountPathAbsolute:    "mountPath-not-absolute",
+	codeMountPathDup:         "mountPath-duplicate",
+	codeVolumeUndefined:      "volume-undefined",
+	codeVolumeUnused:         "volume-unused",
+	codeVolumesType:          "volumes-type",
+	codeVolumeType:           "volumes-item-type",
+	codeVolumeNameRequired:   "volumes-name-required",
+	codeVolumeNameType:       "volumes-name-type",
+	codeVolumeNameDup:        "volumes-name-duplicate",
+
+	codeVolumeSources:           "volume-multiple-sources",
+	codeVolumeSourceType:        "volume-source-type",
+	codeVolumeSourceField:       "volume-source-field-required",
+	codeVolumeSourceFieldType:   "volume-source-field-type",
+	codeVolumeMediumUnsupported: "emptyDir-medium-unsupported",
+	codeVolumeMediumCase:        "emptyDir-medium-wrong-case",
+	codeVolumeSizeLimit:         "emptyDir-sizeLimit-format",
+	codeHostPathRelative:        "hostPath-path-not-absolute",
+	codeHostPathTypeUnsupported: "hostPath-type-unsupported",
+	codeHostPathTypeCase:        "hostPath-type-wrong-case",
+	codeVolumeItemsType:         "volume-items-type",
+	codeVolumeItemType:          "volume-items-item-type",
+	codeVolumeItemPath:          "volume-items-path",
+
+	codeWorkloadSpecType:          "workload-spec-type",
+	codeReplicasType:              "replicas-type",
+	codeReplicasNegative:          "replicas-negative",
+	codeSelectorRequired:          "selector-required",
+	codeSelectorType:              "selector-type",
+	codeSelectorEmpty:             "selector-empty",
+	codeMatchLabelsType:           "matchLabels-type",
+	codeMatchExpressionsType:      "matchExpressions-type",
+	codeMatchExpressionType:       "matchExpressions-item-type",
+	codeMatchExpressionKey:        "matchExpressions-key-required",
+	codeMatchExpressionOpRequired: "matchExpressions-operator-required",
+	codeMatchExpressionOp:         "matchExpressions-operator-unsupported",
+	codeMatchExpressionValues:     "matchExpressions-values",
+	codeTemplateRequired:          "template-required",
+	codeTemplateType:              "template-type",
+	codeSelectorMismatch:          "selector-template-mismatch",
+
+	codeStrategyType:          "strategy-type",
+	codeStrategyUnsupported:   "strategy-unsupported",
+	codeStrategyCase:          "strategy-wrong-case",
+	codeRollingUpdateRecreate: "rollingUpdate-with-recreate",
+	codeIntOrPercent:          "int-or-percent",
+	codeRollingUpdateZero:     "rollingUpdate-zero",
+
+	codeServiceNameRequired:       "serviceName-required",
+	codeServiceNameFormat:         "serviceName-format",
+	codePodManagementPolicy:       "podManagementPolicy-unsupported",
+	codePodManagementCase:         "podManagementPolicy-wrong-case",
+	codeUpdateStrategyType:        "updateStrategy-type",
+	codeUpdateStrategyUnsupported: "updateStrategy-unsupported",
+	codeUpdateStrategyCase:        "updateStrategy-wrong-case",
+	codeRollingUpdateOnDelete:     "rollingUpdate-with-ondelete",
+	codePartition:                 "partition-type",
+	codeClaimTemplatesType:        "volumeClaimTemplates-type",
+	codeClaimTemplateType:         "volumeClaimTemplates-item-type",
+	codeClaimTemplateName:         "volumeClaimTemplates-name-required",
+	codeClaimTemplateDup:          "volumeClaimTemplates-name-duplicate",
+
+	codeDaemonSetReplicas: "daemonset-replicas",
+
+	codeJobCount:              "job-count",
+	codeRestartPolicyRequired: "restartPolicy-required",
+	codeRestartPolicy:         "restartPolicy-unsupported",
+	codeRestartPolicyCase:     "restartPolicy-wrong-case",
+
+	codeScheduleRequired:      "schedule-required",
+	codeScheduleInvalid:       "schedule-format",
+	codeConcurrencyPolicy:     "concurrencyPolicy-unsupported",
+	codeConcurrencyPolicyCase: "concurrencyPolicy-wrong-case",
+	codeCronJobCount:          "cronjob-count",
+	codeJobTemplateRequired:   "jobTemplate-required",
+	codeJobTemplateType:       "jobTemplate-type",
+
+	codeClaimSpecType:         "claim-spec-type",
+	codeAccessModesRequired:   "accessModes-required",
+	codeAccessModesType:       "accessModes-type",
+	codeAccessModeUnsupported: "accessModes-unsupported",
+	codeAccessModeCase:        "accessModes-wrong-case",
+	codeStorageRequired:       "storage-required",
+	codeStorageFormat:         "storage-format",
+	codeStorageClassType:      "storageClassName-type",
+	codeStorageClassFormat:    "storageClassName-format",
+	codeVolumeModeUnsupported: "volumeMode-unsupported",
+	codeVolumeModeCase:        "volumeMode-wrong-case",
+
+	codeServiceSpecType:         "service-spec-type",
+	codeServiceTypeUnsupported:  "service-type-unsupported",
+	codeServiceTypeCase:         "service-type-wrong-case",
+	codeServicePortsRequired:    "service-ports-required",
+	codeServicePortNameRequired: "service-port-name-required",
+	codeServicePortName:         "service-port-name-format",
+	codeServicePortNameDup:      "service-port-name-duplicate",
+	codeTargetPortFormat:        "targetPort-format",
+	codeNodePortNotAllowed:      "nodePort-not-allowed",
+	codeNodePortRange:           "nodePort-range",
+	codeServiceSelectorType:     "service-selector-type",
+	codeClusterIPFormat:         "clusterIP-format",
+	codeExternalNameRequired:    "externalName-required",
+	codeServicePortsType:        "service-ports-type",
+	codeServicePortType:         "service-port-item-type",
+	codeServicePortRequired:     "service-port-required",
+
+	codeIngressSpecType:        "ingress-spec-type",
+	codeIngressClassFormat:     "ingressClassName-format",
+	codeIngressRulesRequired:   "ingress-rules-required",
+	codeIngressRulesType:       "ingress-rules-type",
+	codeIngressRuleType:        "ingress-rule-type",
+	codeIngressHostFormat:      "ingress-host-format",
+	codeIngressHTTPType:        "ingress-http-type",
+	codeIngressPathsRequired:   "ingress-paths-required",
+	codeIngressPathsType:       "ingress-paths-type",
+	codeIngressPathType:        "ingress-path-item-type",
+	codePathTypeRequired:       "pathType-required",
+	codePathTypeUnsupported:    "pathType-unsupported",
+	codePathTypeCase:           "pathType-wrong-case",
+	codeIngressPathFormat:      "ingress-path-format",
+	codeIngressBackendRequired: "ingress-backend-required",
+	codeIngressBackendType:     "ingress-backend-type",
+	codeIngressBackendOneOf:    "ingress-backend-one-of",
+	codeIngressBackendField:    "ingress-backend-field",
+	codeIngressServiceName:     "ingress-service-name",
+	codeIngressServicePort:     "ingress-service-port",
+	codeIngressPortName:        "ingress-port-name",
+	codeIngressTLSType:         "ingress-tls-type",
+	codeIngressTLSItemType:     "ingress-tls-item-type",
+	codeIngressTLSHostsType:    "ingress-tls-hosts-type",
+	codeIngressSecretName:      "ingress-tls-secretName",
+
+	codeHPASpecType:             "hpa-spec-type",
+	codeScaleTargetRequired:     "scaleTargetRef-required",
+	codeScaleTargetType:         "scaleTargetRef-type",
+	codeScaleTargetField:        "scaleTargetRef-field",
+	codeMaxReplicasRequired:     "maxReplicas-required",
+	codeHPAReplicas:             "hpa-replicas",
+	codeHPAReplicasRange:        "hpa-replicas-range",
+	codeMetricsType:             "metrics-type",
+	codeMetricType:              "metric-item-type",
+	codeMetricTypeRequired:      "metric-type-required",
+	codeMetricTypeUnsupported:   "metric-type-unsupported",
+	codeMetricTypeCase:          "metric-type-wrong-case",
+	codeMetricSourceRequired:    "metric-source-required",
+	codeMetricSourceType:        "metric-source-type",
+	codeMetricSourceField:       "metric-field-required",
+	codeMetricTargetUnsupported: "metric-target-unsupported",
+	codeMetricTargetCase:        "metric-target-wrong-case",
+	codeMetricTargetValue:       "metric-target-value",
+
+	codeNetPolSpecType:        "networkpolicy-spec-type",
+	codePodSelectorRequired:   "podSelector-required",
+	codeNetPolListType:        "networkpolicy-list-type",
+	codeNetPolItemType:        "networkpolicy-item-type",
+	codePolicyTypeUnsupported: "policyTypes-unsupported",
+	codePolicyTypeCase:        "policyTypes-wrong-case",
+	codePeerEmpty:             "networkpolicy-peer-empty",
+	codePeerIPBlockMixed:      "networkpolicy-peer-ipblock-mixed",
+	codeIPBlockType:           "ipBlock-type",
+	codeCIDRRequired:          "cidr-required",
+	codeCIDRFormat:            "cidr-format",
+	codeExceptOutsideCIDR:     "ipBlock-except-outside",
+	codeNetPolPortName:        "networkpolicy-port-name",
+	codeEndPort:               "networkpolicy-endPort",
+
+	codePDBSpecType:               "pdb-spec-type",
+	codePDBBudgetRequired:         "pdb-budget-required",
+	codePDBBudgetExclusive:        "pdb-budget-exclusive",
+	codePDBPercent:                "pdb-percent-range",
+	codeEvictionPolicyUnsupported: "unhealthyPodEvictionPolicy-unsupported",
+	codeEvictionPolicyCase:        "unhealthyPodEvictionPolicy-wrong-case",
+
+	codeListItemsRequired: "list-items-required",
+	codeListItemsType:     "list-items-type",
+
+	codeConfigMapDataType:  "configmap-data-type",
+	codeConfigMapValueType: "configmap-value-type",
+	codeBinaryDataType:     "binaryData-type",
+	codeBinaryDataValue:    "binaryData-value",
+	codeConfigMapKey:       "configmap-key-format",
+	codeConfigMapKeyDup:    "configmap-key-duplicate",
+	codeConfigMapSize:      "configmap-size",
+	codeImmutableType:      "immutable-type",
+
+	codeSecretTypeType:      "secret-type-type",
+	codeSecretTypeUnknown:   "secret-type-unknown",
+	codeSecretTypeCase:      "secret-type-wrong-case",
+	codeSecretDataType:      "secret-data-type",
+	codeSecretDataValue:     "secret-data-base64",
+	codeStringDataType:      "stringData-type",
+	codeStringDataValue:     "stringData-value-type",
+	codeSecretKey:           "secret-key-format",
+	codeSecretKeyDup:        "secret-key-duplicate",
+	codeSecretKeyRequired:   "secret-key-required",
+	codeSecretSize:          "secret-size",
+	codePlaintextCredential: "plaintext-credential",
+
+	codeYAML11Bool:        "yaml11-boolean",
+	codeYAML11Sexagesimal: "yaml11-sexagesimal",
+	codeYAML11Number:      "yaml11-number",
+
+	codeRecommendedLabel:       "recommended-label-missing",
+	codeRecommendedLabelFormat: "recommended-label-format",
+
+	codeObjectSize:      "object-size",
+	codeNameLength:      "name-length",
+	codeNamespaceLength: "namespace-length",
+	codeLabelKeyLength:  "label-key-length",
+	codeLabelValueLen:   "label-value-length",
+	codeAnnotationsSize: "annotations-size",
+
+	codeBinarySkipped: "binary-file",
+	codeNotManifest:   "not-a-manifest",
+	codeUnknownKind:   "unknown-kind-skipped",
+	codeEmptyFile:     "empty-file",
+	codeCommentsOnly:  "comments-only",
+	codeEmptyDocument: "empty-document",
+
+	codeTooFewDocuments:  "too-few-documents",
+	codeTooManyDocuments: "too-many-documents",
+	codeKindMissing:      "required-kind-missing",
+	codeFileName:         "file-name",
+	codeLayout:           "directory-layout",
+
+	codeImageArchUnknown:  "image-arch-unknown",
+	codeImageArchMismatch: "image-arch-mismatch",
+
+	codeSelectorNoPool:         "selector-no-node-pool",
+	codeTolerationNoTaint:      "toleration-no-taint",
+	codeTolerationNotTargeted:  "toleration-not-targeted",
+	codePoolTaintNotTolerated:  "pool-taint-not-tolerated",
+	codePriorityClassUnknown:   "priority-class-unknown",
+	codePriorityClassSystem:    "priority-class-system",
+	codeStorageClassUnknown:    "storage-class-unknown",
+	codeStorageClassAccessMode: "storage-class-access-mode",
+
+	codeAnnotationRequired:  "annotation-required",
+	codeAnnotationFormat:    "annotation-format",
+	codeAnnotationReference: "annotation-reference-format",
+
+	codePodmanKind:           "podman-kind-unsupported",
+	codePodmanPodField:       "podman-pod-field-ignored",
+	codePodmanContainerField: "podman-container-field-ignored",
+
+	codeImageLatestTag: "image-floating-tag",
+	codeLimitsMissing:  "resource-limits-missing",
+
+	CodeImageNotFound:     "image-not-found",
+	CodeImageCheckFailed:  "image-check-failed",
+	CodeImageVulnerable:   "image-vulnerable",
+	CodeReferenceMissing:  "annotation-reference-missing",
+	CodeQuotaExceeded:     "namespace-quota-exceeded",
+	CodeDigestMismatch:    "lockfile-digest-mismatch",
+	CodeNotLocked:         "lockfile-missing-file",
+	CodeReadFailed:        "read-failed",
+	CodeParseFailed:       "parse-failed",
+	CodeValidationTimeout: "validation-timeout",
+	CodeValidationPanic:   "validation-panic",
+}
+
+// RuleName — стабильное имя проверки по коду, например
+// apiVersion-unsupported для OBJ004; пусто для неизвестного кода.
+func RuleName(code string) string { return ruleNames[code] }
+
+// Rule — код находки с именем проверки: «OBJ004 apiVersion-unsupported».
+func (is Issue) Rule() string {
+	if name := ruleNames[is.Code]; name != "" {
+		return is.Code + " " + name
+	}
+	return is.Code
+}
+
+// RuleCodes — коды всех проверок по порядку.
+func RuleCodes() []string {
+	codes := make([]string, 0, len(ruleNames))
+	for code := range ruleNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"regexp"
+	"testing"
+)
+
+// Каталог сообщений и имена проверок ведутся вместе: у каждого кода есть
+// текст и имя, имена уникальны.
+func TestRuleNames(t *testing.T) {
+	reCode := regexp.MustCompile(`^[A-Z]{2,3}[0-9]{3}$`)
+	reName := regexp.MustCompile(`^[a-zA-Z0-9]+(-[a-zA-Z0-9]+)*$`)
+	seen := map[string]string{}
+	for _, code := range RuleCodes() {
+		name := RuleName(code)
+		if !reCode.MatchString(code) {
+			t.Errorf("code %q has invalid format", code)
+		}
+		if !reName.MatchString(name) {
+			t.Errorf("%s: rule name %q is not kebab-case", code, name)
+		}
+		if other, ok := seen[name]; ok {
+			t.Errorf("rule name %q used by %s and %s", name, other, code)
+		}
+		seen[name] = code
+		if English[code] == "" {
+			t.Errorf("%s has no message", code)
+		}
+	}
+	for code := range English {
+		if RuleName(code) == "" {
+			t.Errorf("%s has no rule name", code)
+		}
+	}
+}
+
+func TestIssueRule(t *testing.T) {
+	if got := (Issue{Code: codeAPIVersionUnsupported}).Rule(); got != "OBJ004 apiVersion-unsupported" {
+		t.Errorf("Rule() = %q", got)
+	}
+	if got := (Issue{Code: "XYZ999"}).Rule(); got != "XYZ999" {
+		t.Errorf("unknown code Rule() = %q", got)
+	}
+}
@@ -0,0 +1,144 @@
+// rulepacks.go
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- recommended-labels ----------
+
+// https://kubernetes.io/docs/concepts/overview/working-with-objects/common-labels/
+var recommendedLabels = []struct {
+	key string
+	re  *regexp.Regexp
+}{
+	{"app.kubernetes.io/name", reLabelDNS},
+	{"app.kubernetes.io/instance", reLabelDNS},
+	{"app.kubernetes.io/version", reLabelValue},
+	{"app.kubernetes.io/component", reLabelDNS},
+	{"app.kubernetes.io/part-of", reLabelDNS},
+	{"app.kubernetes.io/managed-by", reLabelValue},
+}
+
+// reLabelValue — синтаксис значения метки (до 63 символов), reLabelDNS —
+// более строгий вариант для имён: дашборды склеивают их в идентификаторы.
+var reLabelValue = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]{0,61}[A-Za-z0-9])?$`)
+var reLabelDNS = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+func validateRecommendedLabels(ctx *ValidationContext, bag *errBag) {
+	meta, _ := child(ctx.Doc, "metadata")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		return // о самой metadata уже сообщил validateObjectMeta
+	}
+	labels, _ := child(meta, "labels")
+	if labels != nil && labels.Kind != yaml.MappingNode {
+		return
+	}
+	for _, rl := range recommendedLabels {
+		var v *yaml.Node
+		if labels != nil {
+			v, _ = child(labels, rl.key)
+		}
+		switch {
+		case v == nil:
+			bag.add(nil, codeRecommendedLabel, rl.key)
+		case !isScalarString(v):
+			// формат значений меток проверяет validateObjectMeta
+		case !rl.re.MatchString(v.Value):
+			bag.add(v, codeRecommendedLabelFormat, rl.key, v.Value)
+		}
+	}
+}
+
+// ---------- podman ----------
+
+// Поля, которые podman play kube принимает, но игнорирует: манифест
+// запускается локально, а поведение отличается от кластера. Список — по
+// docs/kubernetes_support.md в репозитории podman.
+var podmanIgnoredPodFields = map[string]bool{
+	"activeDeadlineSeconds": true, "affinity": true,
+	"automountServiceAccountToken": true, "dnsPolicy": true,
+	"enableServiceLinks": true, "ephemeralContainers": true,
+	"imagePullSecrets": true, "nodeName": true, "nodeSelector": true,
+	"os": true, "overhead": true, "preemptionPolicy": true, "priority": true,
+	"priorityClassName": true, "readinessGates": true,
+	"runtimeClassName": true, "schedulerName": true, "serviceAccount": true,
+	"serviceAccountName": true, "setHostnameAsFQDN": true, "subdomain": true,
+	"tolerations": true, "topologySpreadConstraints": true,
+}
+
+var podmanIgnoredContainerFields = map[string]bool{
+	"readinessProbe": true, "stdinOnce": true, "terminationMessagePath": true,
+	"terminationMessagePolicy": true, "volumeDevices": true,
+}
+
+// podmanKinds — kind'ы, которые понимает podman play kube.
+var podmanKinds = map[string]bool{
+	"Pod": true, "Deployment": true, "DaemonSet": true, "Job": true,
+	"ConfigMap": true, "Secret": true, "PersistentVolumeClaim": true,
+}
+
+func validatePodmanCompat(ctx *ValidationContext, bag *errBag) {
+	kind, _ := child(ctx.Doc, "kind")
+	if kind == nil || !isScalarString(kind) {
+		return
+	}
+	if !podmanKinds[kind.Value] {
+		bag.warn(kind, codePodmanKind, kind.Value)
+		return
+	}
+	spec := ctx.PodSpec
+	if spec == nil {
+		return
+	}
+	// строка — у ключа: у значения-объекта она уже следующая
+	for i := 0; i+1 < len(spec.Content); i += 2 {
+		if k := spec.Content[i]; podmanIgnoredPodFields[k.Value] {
+			bag.warn(k, codePodmanPodField, k.Value)
+		}
+	}
+	for _, c := range ctx.Containers {
+		for i := 0; i+1 < len(c.Node.Content); i += 2 {
+			if k := c.Node.Content[i]; podmanIgnoredContainerFields[k.Value] {
+				bag.warn(k, codePodmanContainerField, k.Value)
+			}
+		}
+	}
+}
+
+// ---------- best-practices ----------
+
+// validateBestPractices — то, что не ломает apply, но ломает эксплуатацию:
+// плавающий тег и контейнер без limits. Только предупреждения — сборку
+// они не валят.
+func validateBestPractices(ctx *ValidationContext, bag *errBag) {
+	for _, c := range ctx.Containers {
+		if img, ok := child(c.Node, "image"); ok && isScalarString(img) && floatingTag(img.Value) {
+			bag.warn(img, codeImageLatestTag, img.Value)
+		}
+		// о resources без map или вовсе без них сообщает validateContainer
+		for i := 0; i+1 < len(c.Node.Content); i += 2 {
+			k, v := c.Node.Content[i], c.Node.Content[i+1]
+			if k.Value != "resources" || v.Kind != yaml.MappingNode {
+				continue
+			}
+			if _, ok := child(v, "limits"); !ok {
+				bag.warn(k, codeLimitsMissing, c.Name)
+			}
+		}
+	}
+}
+
+// floatingTag — образ с тегом latest или без тега и дайджеста: что
+// запустится, решает реестр в момент pull.
+func floatingTag(ref string) bool {
+	if strings.Contains(ref, "@") {
+		return false
+	}
+	last := ref[strings.LastIndex(ref, "/")+1:]
+	_, tag, ok := strings.Cut(last, ":")
+	return !ok || tag == "latest"
+}
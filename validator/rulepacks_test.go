@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// labeled — под с метками labels в metadata.
+func labeled(labels string) string {
+	return strings.Replace(pod("", ""), "  name: web\n", "  name: web\n  labels:\n"+labels, 1)
+}
+
+func TestRecommendedLabels(t *testing.T) {
+	all := "    app.kubernetes.io/name: web\n    app.kubernetes.io/instance: web-prod\n" +
+		"    app.kubernetes.io/version: 1.0.0\n    app.kubernetes.io/component: frontend\n" +
+		"    app.kubernetes.io/part-of: shop\n    app.kubernetes.io/managed-by: Helm\n"
+	checkRules(t, Config{RulePacks: []string{"recommended-labels"}}, []ruleCase{
+		{"all labels", labeled(all), ""},
+		{"missing", pod("", ""), codeRecommendedLabel},
+		{"missing one", labeled(strings.Replace(all, "    app.kubernetes.io/part-of: shop\n", "", 1)), codeRecommendedLabel},
+		{"name not DNS", labeled(strings.Replace(all, "name: web", "name: Web_App", 1)), codeRecommendedLabelFormat},
+	})
+	// без набора правил метки не требуются
+	checkRules(t, Config{}, []ruleCase{{"pack off", pod("", ""), ""}})
+}
+
+func TestPodmanCompat(t *testing.T) {
+	svc := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports: [{port: 80}]\n"
+	checkRules(t, Config{RulePacks: []string{"podman"}}, []ruleCase{
+		{"supported", pod("", ""), ""},
+		{"unsupported kind", svc, codePodmanKind},
+		{"ignored pod field", pod("", "  nodeSelector: {disk: ssd}\n"), codePodmanPodField},
+		{"ignored container field", pod("      readinessProbe: {tcpSocket: {port: 8080}}\n", ""), codePodmanContainerField},
+	})
+	checkRules(t, Config{}, []ruleCase{{"pack off", pod("", "  nodeSelector: {disk: ssd}\n"), ""}})
+}
+
+func TestRulePacksUnknown(t *testing.T) {
+	if RulePackKnown("strict") || !RulePackKnown("recommended-labels") {
+		t.Error("RulePackKnown mismatch")
+	}
+	if _, err := New(Config{RulePacks: []string{"strict"}}); err == nil {
+		t.Error("unknown pack in config accepted")
+	}
+}
@@ -0,0 +1,73 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// ruleCase — документ и код, который он должен дать; want "" — документ
+// без находок.
+type ruleCase struct {
+	name, doc, want string
+}
+
+// validate проверяет data валидатором с конфигом cfg.
+func validate(t *testing.T, cfg Config, data string) *Result {
+	t.Helper()
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Validate([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+// codes — коды находок через пробел.
+func codes(res *Result) string {
+	var out []string
+	for _, is := range res.Issues {
+		out = append(out, is.Code)
+	}
+	return strings.Join(out, " ")
+}
+
+// checkRules прогоняет случаи через валидатор с конфигом cfg.
+func checkRules(t *testing.T, cfg Config, tests []ruleCase) {
+	t.Helper()
+	checkRulesAt(t, cfg, "", tests)
+}
+
+// checkRulesAt — checkRules для файла по пути path: для правил имени
+// файла и каталога.
+func checkRulesAt(t *testing.T, cfg Config, path string, tests []ruleCase) {
+	t.Helper()
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		res, err := v.ValidateFile(path, []byte(tt.doc))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		got := codes(res)
+		switch {
+		case tt.want == "" && got != "":
+			t.Errorf("%s: codes %q, want none", tt.name, got)
+		case tt.want != "" && !strings.Contains(" "+got+" ", " "+tt.want+" "):
+			t.Errorf("%s: codes %q, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// pod — корректный под с одним контейнером; spec дописывается в spec
+// пода (отступ 2), container — в контейнер (отступ 6).
+func pod(container, spec string) string {
+	return "apiVersion: v1\nkind: Pod\nmetadata:\n  name: web\nspec:\n" + spec +
+		"  containers:\n    - name: web\n      image: registry.bigbrother.io/web:1.0\n" +
+		"      resources:\n        requests: {cpu: 1, memory: 64Mi}\n        limits: {cpu: 1, memory: 64Mi}\n" + container
+}
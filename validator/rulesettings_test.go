@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleSettings(t *testing.T) {
+	doc := strings.Replace(pod("", ""), "spec:\n", "spec:\n  os: {name: plan9}\n", 1)
+	tests := []struct {
+		name  string
+		rules map[string]string
+		want  string // код и важность находки или "" — находки нет
+	}{
+		{"default", nil, "POD007 error"},
+		{"off by code", map[string]string{"POD007": "off"}, ""},
+		{"off by name", map[string]string{"os-unsupported": "off"}, ""},
+		{"severity", map[string]string{"os-unsupported": "warning"}, "POD007 warning"},
+	}
+	for _, tt := range tests {
+		res := validate(t, Config{Rules: tt.rules}, doc)
+		got := ""
+		for _, is := range res.Issues {
+			got = is.Code + " " + is.Severity.String()
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	for _, rules := range []map[string]string{{"no-such-rule": "off"}, {"POD007": "fatal"}} {
+		if _, err := New(Config{Rules: rules}); err == nil {
+			t.Errorf("%v accepted", rules)
+		}
+	}
+}
+
+// Adjust настраивает находки, добавленные снаружи ядра.
+func TestAdjust(t *testing.T) {
+	v, err := New(Config{Rules: map[string]string{CodeImageNotFound: "off", CodeImageVulnerable: "info"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res := &Result{Issues: []Issue{
+		{Code: CodeImageNotFound, Severity: SeverityError},
+		{Code: CodeImageVulnerable, Severity: SeverityError},
+		{Code: CodeImageCheckFailed, Severity: SeverityWarning},
+	}}
+	v.Adjust(res)
+	v.Adjust(res)
+	if len(res.Issues) != 2 || res.Issues[0].Severity != SeverityInfo || res.Issues[1].Severity != SeverityWarning {
+		t.Errorf("issues %+v", res.Issues)
+	}
+}
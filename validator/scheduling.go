@@ -0,0 +1,367 @@
+// scheduling.go
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- cluster inventory ----------
+
+// ClusterInventory — пулы узлов кластера с метками и taint'ами; по нему
+// сверяются tolerations пода с его nodeSelector и affinity. StorageClasses
+// — классы хранилища с поддерживаемыми accessModes для проверки PVC.
+type ClusterInventory struct {
+	NodePools      []NodePool     `yaml:"nodePools"`
+	StorageClasses []StorageClass `yaml:"storageClasses"`
+}
+
+// StorageClass — класс хранилища кластера, например
+// {name: local-ssd, accessModes: [ReadWriteOnce, ReadWriteOncePod]}.
+// Default — класс по умолчанию для PVC без storageClassName.
+type StorageClass struct {
+	Name        string   `yaml:"name"`
+	AccessModes []string `yaml:"accessModes"`
+	Default     bool     `yaml:"default"`
+}
+
+type NodePool struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels"`
+	Taints []Taint           `yaml:"taints"`
+}
+
+type Taint struct {
+	Key    string `yaml:"key"`
+	Value  string `yaml:"value"`
+	Effect string `yaml:"effect"`
+}
+
+// toleration — разобранная запись spec.tolerations.
+type toleration struct {
+	node                         *yaml.Node
+	key, operator, value, effect string
+}
+
+// tolerates — семантика kube-scheduler: пустой ключ с Exists покрывает
+// всё, пустой effect — любой effect.
+func (t toleration) tolerates(taint Taint) bool {
+	if t.effect != "" && t.effect != taint.Effect {
+		return false
+	}
+	if t.key == "" {
+		return t.operator == "Exists"
+	}
+	if t.key != taint.Key {
+		return false
+	}
+	return t.operator == "Exists" || t.value == taint.Value
+}
+
+// ---------- rules ----------
+
+// validateScheduling предупреждает о подах, которые уедут не в тот пул:
+// toleration без nodeSelector/affinity на пул с этим taint'ом, и наоборот —
+// выбраны только пулы с taint'ами, которые под не терпит.
+func validateScheduling(ctx *ValidationContext, bag *errBag) {
+	pools := ctx.rules.cluster.NodePools
+	spec := ctx.PodSpec
+	if len(pools) == 0 || spec == nil {
+		return
+	}
+	tols := parseTolerations(spec)
+	selector, hasSelector := child(spec, "nodeSelector")
+	terms := requiredNodeTerms(spec)
+	constrained := hasSelector || terms != nil
+
+	targeted := make([]bool, len(pools))
+	anyTargeted := false
+	for i, p := range pools {
+		targeted[i] = matchesSelector(selector, p) && matchesTerms(terms, p)
+		anyTargeted = anyTargeted || targeted[i]
+	}
+	if constrained && !anyTargeted {
+		at := selector
+		if at == nil {
+			at = spec
+		}
+		bag.warn(at, codeSelectorNoPool)
+		return
+	}
+
+	for _, t := range tols {
+		matched, onTarget := false, false
+		for i, p := range pools {
+			for _, taint := range p.Taints {
+				if t.tolerates(taint) {
+					matched = true
+					onTarget = onTarget || (constrained && targeted[i])
+				}
+			}
+		}
+		switch {
+		case !matched:
+			bag.warn(t.node, codeTolerationNoTaint, t.key)
+		case !onTarget:
+			bag.warn(t.node, codeTolerationNotTargeted, t.key)
+		}
+	}
+
+	if !constrained {
+		return
+	}
+	// под не встанет ни на один из выбранных пулов
+	blocked := map[int]Taint{}
+	for i, p := range pools {
+		if !targeted[i] {
+			continue
+		}
+		taint, ok := untolerated(p, tols)
+		if !ok {
+			return
+		}
+		blocked[i] = taint
+	}
+	at := selector
+	if at == nil {
+		at = spec
+	}
+	for i, p := range pools {
+		if taint, ok := blocked[i]; ok {
+			bag.warn(at, codePoolTaintNotTolerated, p.Name, taint.Key)
+		}
+	}
+}
+
+// untolerated — первый запрещающий taint пула без подходящей toleration.
+func untolerated(p NodePool, tols []toleration) (Taint, bool) {
+	for _, taint := range p.Taints {
+		if taint.Effect == "PreferNoSchedule" {
+			continue
+		}
+		tolerated := false
+		for _, t := range tols {
+			if t.tolerates(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return taint, true
+		}
+	}
+	return Taint{}, false
+}
+
+func parseTolerations(spec *yaml.Node) []toleration {
+	list, _ := child(spec, "tolerations")
+	if list == nil || list.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var out []toleration
+	for _, n := range list.Content {
+		if n.Kind != yaml.MappingNode {
+			continue
+		}
+		t := toleration{node: n, operator: "Equal"}
+		for _, f := range []struct {
+			key string
+			dst *string
+		}{{"key", &t.key}, {"operator", &t.operator}, {"value", &t.value}, {"effect", &t.effect}} {
+			if v, ok := child(n, f.key); ok && isScalarString(v) {
+				*f.dst = v.Value
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func matchesSelector(selector *yaml.Node, p NodePool) bool {
+	if selector == nil {
+		return true
+	}
+	for i := 0; i+1 < len(selector.Content); i += 2 {
+		if v, ok := p.Labels[selector.Content[i].Value]; !ok || v != selector.Content[i+1].Value {
+			return false
+		}
+	}
+	return true
+}
+
+// requiredNodeTerms — nodeSelectorTerms из
+// affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution.
+func requiredNodeTerms(spec *yaml.Node) []*yaml.Node {
+	n := spec
+	for _, key := range []string{"affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution", "nodeSelectorTerms"} {
+		var ok bool
+		if n, ok = child(n, key); !ok {
+			return nil
+		}
+	}
+	if n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+// matchesTerms — термы объединяются по ИЛИ, выражения внутри — по И.
+func matchesTerms(terms []*yaml.Node, p NodePool) bool {
+	if terms == nil {
+		return true
+	}
+	for _, term := range terms {
+		exprs, _ := child(term, "matchExpressions")
+		if exprs == nil || exprs.Kind != yaml.SequenceNode {
+			continue
+		}
+		ok := true
+		for _, e := range exprs.Content {
+			if !matchesExpression(e, p) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesExpression(e *yaml.Node, p NodePool) bool {
+	key, op := "", ""
+	if k, ok := child(e, "key"); ok {
+		key = k.Value
+	}
+	if o, ok := child(e, "operator"); ok {
+		op = o.Value
+	}
+	label, has := p.Labels[key]
+	in := false
+	if vs, ok := child(e, "values"); ok {
+		for _, v := range vs.Content {
+			in = in || (has && v.Value == label)
+		}
+	}
+	switch strings.TrimSpace(op) {
+	case "In":
+		return in
+	case "NotIn":
+		return !in
+	case "Exists":
+		return has
+	case "DoesNotExist":
+		return !has
+	}
+	return true // Gt/Lt по инвентарю не проверяем
+}
+
+// ---------- priority classes ----------
+
+// builtinPriorityClasses создаёт сам kube-apiserver.
+var builtinPriorityClasses = []string{"system-cluster-critical", "system-node-critical"}
+
+// validatePriorityClass сверяет priorityClassName со списком классов
+// кластера из конфига; system-* вне kube-system обычно ошибка копипасты:
+// такой под вытеснит системные.
+func validatePriorityClass(ctx *ValidationContext, bag *errBag) {
+	doc, spec := ctx.Doc, ctx.PodSpec
+	if spec == nil {
+		return
+	}
+	pc, _ := child(spec, "priorityClassName")
+	if pc == nil || !isScalarString(pc) || !reDNSSubdomain.MatchString(pc.Value) {
+		return // тип и формат проверяет validatePodSpec
+	}
+	if known := ctx.rules.priorityClasses; known != nil && !known[pc.Value] {
+		bag.add(pc, codePriorityClassUnknown, pc.Value)
+		return
+	}
+	if strings.HasPrefix(pc.Value, "system-") {
+		namespace := "default"
+		if meta, _ := child(doc, "metadata"); meta != nil {
+			if ns, _ := child(meta, "namespace"); ns != nil && isScalarString(ns) {
+				namespace = ns.Value
+			}
+		}
+		if namespace != "kube-system" {
+			bag.warn(pc, codePriorityClassSystem, pc.Value, namespace)
+		}
+	}
+}
+
+// checkStorageClasses проверяет классы инвентаря: имя, известные
+// accessModes и не больше одного класса по умолчанию.
+func checkStorageClasses(classes []StorageClass) error {
+	seen := map[string]bool{}
+	def := ""
+	for _, sc := range classes {
+		if !reDNSSubdomain.MatchString(sc.Name) {
+			return fmt.Errorf("storage class has invalid name '%s'", sc.Name)
+		}
+		if seen[sc.Name] {
+			return fmt.Errorf("storage class '%s' is listed twice", sc.Name)
+		}
+		seen[sc.Name] = true
+		for _, mode := range sc.AccessModes {
+			if !contains(persistentVolumeAccesses, mode) {
+				return fmt.Errorf("storage class '%s' has unknown access mode '%s' (known: %s)", sc.Name, mode, strings.Join(persistentVolumeAccesses, ", "))
+			}
+		}
+		if sc.Default {
+			if def != "" {
+				return fmt.Errorf("storage classes '%s' and '%s' are both default", def, sc.Name)
+			}
+			def = sc.Name
+		}
+	}
+	return nil
+}
+
+// validateClaimStorageClass сверяет класс и accessModes PVC с классами
+// хранилища инвентаря: PVC с режимом, которого класс не умеет, навсегда
+// повиснет в Pending. Без storageClassName берётся класс по умолчанию;
+// storageClassName: "" — статический PV, его режимы неизвестны.
+func validateClaimStorageClass(m fields, bag *errBag) {
+	classes := bag.rules.cluster.StorageClasses
+	if len(classes) == 0 {
+		return
+	}
+	var class *StorageClass
+	sc, ok := m.get("storageClassName")
+	if ok {
+		if !isScalarString(sc) || sc.Value == "" || !reDNSSubdomain.MatchString(sc.Value) {
+			return // тип и формат проверяет validateClaimSpec
+		}
+		for i := range classes {
+			if classes[i].Name == sc.Value {
+				class = &classes[i]
+			}
+		}
+		if class == nil {
+			bag.add(sc, codeStorageClassUnknown, sc.Value)
+			return
+		}
+	} else {
+		for i := range classes {
+			if classes[i].Default {
+				class = &classes[i]
+			}
+		}
+	}
+	if class == nil || len(class.AccessModes) == 0 {
+		return
+	}
+	am, _ := m.get("accessModes")
+	if am == nil || am.Kind != yaml.SequenceNode {
+		return
+	}
+	for _, mode := range am.Content {
+		if isScalarString(mode) && contains(persistentVolumeAccesses, mode.Value) && !contains(class.AccessModes, mode.Value) {
+			bag.add(mode, codeStorageClassAccessMode, class.Name, mode.Value)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package validator
+
+import "testing"
+
+var testPools = []NodePool{
+	{Name: "general", Labels: map[string]string{"pool": "general"}},
+	{Name: "gpu", Labels: map[string]string{"pool": "gpu"}, Taints: []Taint{{Key: "nvidia.com/gpu", Value: "true", Effect: "NoSchedule"}}},
+}
+
+func TestScheduling(t *testing.T) {
+	gpuTol := "  tolerations: [{key: nvidia.com/gpu, operator: Exists, effect: NoSchedule}]\n"
+	affinity := func(op string) string {
+		return "  affinity:\n    nodeAffinity:\n      requiredDuringSchedulingIgnoredDuringExecution:\n" +
+			"        nodeSelectorTerms:\n          - matchExpressions: [{key: pool, operator: " + op + ", values: [gpu]}]\n"
+	}
+	checkRules(t, Config{Cluster: ClusterInventory{NodePools: testPools}}, []ruleCase{
+		{"unconstrained", pod("", ""), ""},
+		{"gpu pool tolerated", pod("", "  nodeSelector: {pool: gpu}\n"+gpuTol), ""},
+		{"affinity tolerated", pod("", affinity("In")+gpuTol), ""},
+		{"affinity other pool", pod("", affinity("NotIn")), ""},
+		{"no pool", pod("", "  nodeSelector: {pool: arm}\n"), codeSelectorNoPool},
+		{"no taint", pod("", "  tolerations: [{key: dedicated, operator: Exists}]\n"), codeTolerationNoTaint},
+		{"not targeted", pod("", gpuTol), codeTolerationNotTargeted},
+		{"taint not tolerated", pod("", "  nodeSelector: {pool: gpu}\n"), codePoolTaintNotTolerated},
+		{"affinity not tolerated", pod("", affinity("In")), codePoolTaintNotTolerated},
+		{"wrong value", pod("", "  nodeSelector: {pool: gpu}\n  tolerations: [{key: nvidia.com/gpu, value: \"false\"}]\n"), codePoolTaintNotTolerated},
+	})
+	// без инвентаря проверка молчит
+	checkRules(t, Config{}, []ruleCase{{"no inventory", pod("", "  nodeSelector: {pool: arm}\n"), ""}})
+}
@@ -0,0 +1,136 @@
+// secret.go
+package validator
+
+import (
+	"encoding/base64"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// secretTypes — встроенные типы Secret и ключи, без которых секрет
+// этого типа apiserver не примет.
+var secretTypes = map[string][]string{
+	"Opaque":                              nil,
+	"kubernetes.io/service-account-token": nil,
+	"kubernetes.io/dockercfg":             {".dockercfg"},
+	"kubernetes.io/dockerconfigjson":      {".dockerconfigjson"},
+	"kubernetes.io/basic-auth":            nil,
+	"kubernetes.io/ssh-auth":              {"ssh-privatekey"},
+	"kubernetes.io/tls":                   {"tls.crt", "tls.key"},
+	"bootstrap.kubernetes.io/token":       {"token-id", "token-secret"},
+}
+
+// validateSecret — у Secret, как у ConfigMap, нет spec. Собственные типы
+// apiserver принимает, поэтому незнакомый type — предупреждение, а
+// отличие от встроенного только регистром — ошибка с правкой.
+func validateSecret(m fields, bag *errBag) {
+	typ := "Opaque"
+	if t, ok := m.get("type"); ok {
+		typ = ""
+		switch {
+		case !isScalarString(t):
+			bag.add(t, codeSecretTypeType)
+		case hasKey(secretTypes, t.Value):
+			typ = t.Value
+		default:
+			validateSecretType(t, bag)
+		}
+	}
+
+	size := 0
+	keys := map[string]bool{}
+	if data, ok := m.get("data"); ok {
+		size += validateSecretData(data, keys, false, bag)
+	}
+	if sd, ok := m.get("stringData"); ok {
+		size += validateSecretData(sd, keys, true, bag)
+	}
+	if size > maxConfigMapSize {
+		bag.add(nil, codeSecretSize, size, maxConfigMapSize)
+	}
+	if im, ok := m.get("immutable"); ok && !(im.Kind == yaml.ScalarNode && im.Tag == "!!bool") {
+		bag.add(im, codeImmutableType)
+	}
+
+	for _, key := range secretTypes[typ] {
+		if !keys[key] {
+			bag.add(nil, codeSecretKeyRequired, typ, key)
+		}
+	}
+}
+
+func validateSecretType(t *yaml.Node, bag *errBag) {
+	known := sortedKeys(secretTypes)
+	for _, k := range known {
+		if strings.EqualFold(k, strings.TrimSpace(t.Value)) {
+			bag.add(t, codeSecretTypeCase, t.Value)
+			bag.help("did you mean '%s'?", k)
+			bag.fixValue(t, k)
+			return
+		}
+	}
+	bag.warn(t, codeSecretTypeUnknown, t.Value)
+	bag.help("known: %s", strings.Join(known, ", "))
+}
+
+func hasKey[V any](m map[string]V, k string) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// validateSecretData проверяет data (значения в base64) или stringData
+// (открытый текст) и возвращает размер в байтах после декодирования.
+func validateSecretData(n *yaml.Node, keys map[string]bool, plain bool, bag *errBag) int {
+	field, typeCode, valueCode := "data", codeSecretDataType, codeSecretDataValue
+	if plain {
+		field, typeCode, valueCode = "stringData", codeStringDataType, codeStringDataValue
+	}
+	if n.Kind != yaml.MappingNode {
+		bag.add(n, typeCode)
+		return 0
+	}
+	size := 0
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		switch {
+		case !isScalarString(k) || len(k.Value) > maxNameLen || !reConfigMapKey.MatchString(k.Value):
+			bag.add(k, codeSecretKey, field, k.Value)
+		case keys[k.Value] && plain:
+			// stringData перекрывает data при записи
+			bag.warn(k, codeSecretKeyDup, k.Value)
+		}
+		keys[k.Value] = true
+		size += len(k.Value)
+		if !isScalarString(v) {
+			bag.add(v, valueCode, k.Value)
+			continue
+		}
+		if plain {
+			size += len(v.Value)
+			if plaintextCredential(k.Value, v.Value) {
+				bag.warn(v, codePlaintextCredential, k.Value)
+			}
+			continue
+		}
+		raw, err := base64.StdEncoding.DecodeString(v.Value)
+		if err != nil {
+			bag.add(v, valueCode, k.Value)
+			continue
+		}
+		size += len(raw)
+	}
+	return size
+}
+
+// plaintextCredential — stringData с паролем или токеном в открытом виде.
+// Подстановки шаблонизаторов (${DB_PASSWORD}, {{ .Values.token }},
+// <password>) — не утечка: значение появится при рендеринге.
+func plaintextCredential(key, value string) bool {
+	v := strings.TrimSpace(value)
+	if len(v) < minSecretLen || strings.Contains(v, "${") || strings.Contains(v, "{{") ||
+		strings.HasPrefix(v, "<") && strings.HasSuffix(v, ">") {
+		return false
+	}
+	return reSecretEnvName.MatchString(key) || reSecretValue.MatchString(v)
+}
@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// secret — Secret с верхнеуровневыми строками body.
+func secret(body string) string {
+	return "apiVersion: v1\nkind: Secret\nmetadata:\n  name: app\n" + body
+}
+
+func TestSecret(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"opaque", secret("data:\n  username: YWRtaW4=\nimmutable: false\n"), ""},
+		{"tls", secret("type: kubernetes.io/tls\ndata:\n  tls.crt: Y2VydA==\n  tls.key: a2V5\n"), ""},
+		{"type not string", secret("type: [Opaque]\n"), codeSecretTypeType},
+		{"custom type", secret("type: example.com/token\n"), codeSecretTypeUnknown},
+		{"type case", secret("type: opaque\n"), codeSecretTypeCase},
+		{"data not object", secret("data: YWRtaW4=\n"), codeSecretDataType},
+		{"data not base64", secret("data:\n  username: admin!\n"), codeSecretDataValue},
+		{"stringData not object", secret("stringData: [a]\n"), codeStringDataType},
+		{"stringData not string", secret("stringData:\n  port: 5432\n"), codeStringDataValue},
+		{"key format", secret("data:\n  \"user name\": YWRtaW4=\n"), codeSecretKey},
+		{"key in both", secret("data:\n  username: YWRtaW4=\nstringData:\n  username: admin\n"), codeSecretKeyDup},
+		{"required key", secret("type: kubernetes.io/tls\ndata:\n  tls.crt: Y2VydA==\n"), codeSecretKeyRequired},
+		{"too large", secret("stringData:\n  blob: " + strings.Repeat("a", 1<<20) + "\n"), codeSecretSize},
+		{"immutable not bool", secret("immutable: yes please\n"), codeImmutableType},
+		{"plaintext password", secret("stringData:\n  DB_PASSWORD: hunter2\n"), codePlaintextCredential},
+		{"plaintext url", secret("stringData:\n  dsn: postgres://app:hunter2@db/app\n"), codePlaintextCredential},
+		{"template placeholder", secret("stringData:\n  DB_PASSWORD: ${DB_PASSWORD}\n"), ""},
+	})
+}
+
+// Опечатку в регистре встроенного типа --fix исправляет.
+func TestSecretTypeFix(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(secret("type: Kubernetes.io/TLS\ndata:\n  tls.crt: Y2VydA==\n  tls.key: a2V5\n"))
+	res, err := v.Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := ApplyFixes(data, res.Issues)
+	if !strings.Contains(string(out), "type: kubernetes.io/tls\n") {
+		t.Errorf("fixed:\n%s", out)
+	}
+}
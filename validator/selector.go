@@ -0,0 +1,166 @@
+// selector.go
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// labelSelector — селектор меток в синтаксисе kubectl -l: через запятую
+// key=value, key==value, key!=value, key in (a,b), key notin (a,b), key
+// (метка есть) и !key (метки нет). Все требования должны выполняться.
+type labelSelector []labelRequirement
+
+type labelRequirement struct {
+	key    string
+	op     string // =, !=, in, notin, exists, !exists
+	values []string
+}
+
+func compileSelector(s string) (labelSelector, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var sel labelSelector
+	for _, part := range splitSelector(s) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("'%s': empty requirement", s)
+		}
+		var req labelRequirement
+		switch {
+		case strings.ContainsRune(part, '('):
+			open := strings.IndexByte(part, '(')
+			fields := strings.Fields(part[:open])
+			if !strings.HasSuffix(part, ")") || len(fields) != 2 || (fields[1] != "in" && fields[1] != "notin") {
+				return nil, fmt.Errorf("'%s': expected key in (values) or key notin (values)", part)
+			}
+			req = labelRequirement{key: fields[0], op: fields[1]}
+			if set := strings.TrimSpace(part[open+1 : len(part)-1]); set != "" {
+				for _, v := range strings.Split(set, ",") {
+					req.values = append(req.values, strings.TrimSpace(v))
+				}
+			}
+			if len(req.values) == 0 {
+				return nil, fmt.Errorf("'%s': values set can't be empty", part)
+			}
+		case strings.HasPrefix(part, "!"):
+			req = labelRequirement{key: strings.TrimSpace(part[1:]), op: "!exists"}
+		case strings.Contains(part, "!="):
+			k, v, _ := strings.Cut(part, "!=")
+			req = labelRequirement{key: strings.TrimSpace(k), op: "!=", values: []string{strings.TrimSpace(v)}}
+		case strings.Contains(part, "="):
+			k, v, _ := strings.Cut(part, "=")
+			v = strings.TrimPrefix(v, "=")
+			req = labelRequirement{key: strings.TrimSpace(k), op: "=", values: []string{strings.TrimSpace(v)}}
+		default:
+			req = labelRequirement{key: part, op: "exists"}
+		}
+		if !validLabelKey(req.key) {
+			return nil, fmt.Errorf("'%s': invalid label key '%s'", part, req.key)
+		}
+		for _, v := range req.values {
+			if v != "" && !reLabelValue.MatchString(v) {
+				return nil, fmt.Errorf("'%s': invalid label value '%s'", part, v)
+			}
+		}
+		sel = append(sel, req)
+	}
+	return sel, nil
+}
+
+// validLabelKey — ключ метки: имя до 63 символов с необязательным
+// префиксом-DNS-поддоменом до 253 символов через /.
+func validLabelKey(key string) bool {
+	prefix, name, ok := strings.Cut(key, "/")
+	if !ok {
+		prefix, name = "", key
+	} else if prefix == "" || len(prefix) > maxNameLen || !reDNSSubdomain.MatchString(prefix) {
+		return false
+	}
+	return reLabelValue.MatchString(name)
+}
+
+// CheckSelector — ошибка разбора селектора меток, nil для корректного.
+func CheckSelector(s string) error {
+	_, err := compileSelector(s)
+	return err
+}
+
+// splitSelector режет по запятым вне скобок: in (a,b) — одно требование.
+func splitSelector(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// labelsOf — metadata.labels объекта или шаблона пода.
+func labelsOf(obj *yaml.Node) map[string]string {
+	labels := map[string]string{}
+	if meta, _ := child(obj, "metadata"); meta != nil {
+		if l, _ := child(meta, "labels"); l != nil && l.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(l.Content); i += 2 {
+				labels[l.Content[i].Value] = l.Content[i+1].Value
+			}
+		}
+	}
+	return labels
+}
+
+// matches сверяет селектор с метками; пустой селектор подходит любым.
+func (sel labelSelector) matches(labels map[string]string) bool {
+	for _, req := range sel {
+		value, ok := labels[req.key]
+		switch req.op {
+		case "exists":
+			if !ok {
+				return false
+			}
+		case "!exists":
+			if ok {
+				return false
+			}
+		case "=":
+			if !ok || value != req.values[0] {
+				return false
+			}
+		case "!=":
+			if ok && value == req.values[0] {
+				return false
+			}
+		case "in":
+			if !ok || !contains(req.values, value) {
+				return false
+			}
+		case "notin":
+			if ok && contains(req.values, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelector(t *testing.T) {
+	labels := map[string]string{"app": "web", "tier": "frontend"}
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"app=web", true},
+		{"app==web,tier=frontend", true},
+		{"app!=web", false},
+		{"app!=db", true},
+		{"tier in (frontend, backend)", true},
+		{"tier notin (frontend)", false},
+		{"app", true},
+		{"!app", false},
+		{"!owner", true},
+		{"owner=team-a", false},
+		{"team.io/owner", false},
+		{"app=", false},
+	}
+	for _, tt := range tests {
+		sel, err := compileSelector(tt.selector)
+		if err != nil {
+			t.Errorf("%q: %v", tt.selector, err)
+			continue
+		}
+		if got := sel.matches(labels); got != tt.want {
+			t.Errorf("%q: matches = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"app=web,", "tier in frontend", "tier maybe (a)", "=web", "ap p",
+		"app=(web", "tier in ()", "tier in (a", "-app", "app=web!", "/app", "Team.IO/owner=a"} {
+		if err := CheckSelector(s); err == nil {
+			t.Errorf("%q accepted", s)
+		}
+	}
+}
+
+// Документ, не выбранный селектором, не проверяется вовсе.
+func TestSelectorSkipsDocuments(t *testing.T) {
+	plan9 := func(doc string) string { return strings.Replace(doc, "spec:\n", "spec:\n  os: {name: plan9}\n", 1) }
+	checkRules(t, Config{Selector: "app=web"}, []ruleCase{
+		{"selected", plan9(labeled("    app: web\n")), codeOSUnsupported},
+		{"not selected", plan9(pod("", "")), ""},
+	})
+}
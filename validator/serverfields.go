@@ -0,0 +1,38 @@
+// serverfields.go
+package validator
+
+import yaml "gopkg.in/yaml.v3"
+
+// serverMetadataFields — поля metadata, которые заполняет API-сервер. В
+// манифест они попадают из kubectl get -o yaml и потом дают шумные диффы,
+// а resourceVersion ещё и ломает apply конфликтом.
+var serverMetadataFields = map[string]bool{
+	"uid": true, "resourceVersion": true, "generation": true,
+	"creationTimestamp": true, "deletionTimestamp": true,
+	"deletionGracePeriodSeconds": true, "managedFields": true, "selfLink": true,
+}
+
+// validateServerFields предупреждает о полях, которые заполняет сервер,
+// и предлагает --fix, который их удаляет.
+func validateServerFields(ctx *ValidationContext, bag *errBag) {
+	doc := ctx.Doc
+	if doc.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		k, v := doc.Content[i], doc.Content[i+1]
+		switch {
+		case k.Value == "status":
+			bag.warn(k, CodeServerField, "status")
+			bag.fixDelete(k, v)
+		case k.Value == "metadata" && v.Kind == yaml.MappingNode:
+			for j := 0; j+1 < len(v.Content); j += 2 {
+				mk, mv := v.Content[j], v.Content[j+1]
+				if serverMetadataFields[mk.Value] {
+					bag.warn(mk, CodeServerField, "metadata."+mk.Value)
+					bag.fixDelete(mk, mv)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// withMeta — под с дополнительными строками meta в metadata.
+func withMeta(meta string) string {
+	return strings.Replace(pod("", ""), "  name: web\n", "  name: web\n"+meta, 1)
+}
+
+func TestServerFields(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"clean", pod("", ""), ""},
+		{"uid", withMeta("  uid: 6f1c0e3a-0000-4000-8000-000000000000\n"), CodeServerField},
+		{"resourceVersion", withMeta("  resourceVersion: \"42\"\n"), CodeServerField},
+		{"managedFields", withMeta("  managedFields:\n    - manager: kubectl\n      operation: Apply\n"), CodeServerField},
+		{"status", pod("", "") + "status:\n  phase: Running\n", CodeServerField},
+	})
+}
+
+// --fix удаляет поле вместе со всеми строками значения.
+func TestServerFieldsFix(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(withMeta("  generation: 3\n  managedFields:\n    - manager: kubectl\n      fieldsV1:\n        f:spec: {}\n") +
+		"status:\n  phase: Running\n  conditions:\n    - type: Ready\n")
+	res, err := v.Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := ApplyFixes(data, res.Issues)
+	if want := pod("", ""); string(out) != want {
+		t.Errorf("fixed:\n%s\nwant:\n%s", out, want)
+	}
+}
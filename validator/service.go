@@ -0,0 +1,152 @@
+// service.go
+package validator
+
+import (
+	"net"
+	"regexp"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var serviceTypes = []string{"ClusterIP", "ExternalName", "LoadBalancer", "NodePort"}
+
+// nodePortMin и nodePortMax — диапазон --service-node-port-range по
+// умолчанию.
+const (
+	nodePortMin = 30000
+	nodePortMax = 32767
+)
+
+// rePortName — имя порта по IANA_SVC_NAME: до 15 символов, строчные
+// буквы, цифры и дефис, хотя бы одна буква, без дефиса по краям и двух
+// подряд.
+var rePortName = regexp.MustCompile(`^[a-z0-9]([a-z0-9]|-[a-z0-9])*$`)
+var reHasLetter = regexp.MustCompile(`[a-z]`)
+
+func validPortName(s string) bool {
+	return len(s) <= 15 && rePortName.MatchString(s) && reHasLetter.MatchString(s)
+}
+
+func validateServiceSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeServiceSpecType)
+		return
+	}
+
+	// type (optional, ClusterIP по умолчанию); при ошибке в type
+	// правила, зависящие от него, молчат
+	typ := "ClusterIP"
+	if t, ok := m.get("type"); ok {
+		typ = t.Value
+		if !isScalarString(t) || !contains(serviceTypes, t.Value) {
+			unsupported(bag, t, codeServiceTypeUnsupported, codeServiceTypeCase, serviceTypes)
+			typ = ""
+		}
+	}
+
+	// clusterIP (optional): адрес, None для headless или пусто
+	headless := false
+	if ip, ok := m.get("clusterIP"); ok {
+		switch {
+		case !isScalarString(ip):
+			bag.add(ip, codeClusterIPFormat, ip.Value)
+		case ip.Value == "None":
+			headless = true
+		case ip.Value != "" && net.ParseIP(ip.Value) == nil:
+			bag.add(ip, codeClusterIPFormat, ip.Value)
+		}
+	}
+
+	if typ == "ExternalName" {
+		if en, ok := m.get("externalName"); !ok || !isScalarString(en) || en.Value == "" {
+			bag.add(en, codeExternalNameRequired)
+		}
+	}
+
+	// selector (optional): без него Endpoints ведут вручную
+	if sel, ok := m.get("selector"); ok && !isStringMap(sel) {
+		bag.add(sel, codeServiceSelectorType)
+	}
+
+	// ports: обязательны, кроме ExternalName и headless
+	ports, ok := m.get("ports")
+	switch {
+	case !ok:
+		if typ != "ExternalName" && !headless {
+			bag.add(nil, codeServicePortsRequired)
+		}
+	case ports.Kind != yaml.SequenceNode:
+		bag.add(ports, codeServicePortsType)
+	default:
+		names := map[string]bool{}
+		for _, p := range ports.Content {
+			validateServicePort(p, typ, len(ports.Content) > 1, names, bag)
+		}
+	}
+}
+
+func validateServicePort(n *yaml.Node, typ string, several bool, names map[string]bool, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeServicePortType)
+		return
+	}
+
+	// name: обязателен, если портов несколько, и уникален
+	name, ok := m.get("name")
+	switch {
+	case !ok:
+		if several {
+			bag.add(n, codeServicePortNameRequired)
+		}
+	case !isScalarString(name) || !validPortName(name.Value):
+		bag.add(name, codeServicePortName, name.Value)
+	case names[name.Value]:
+		bag.add(name, codeServicePortNameDup, name.Value)
+	default:
+		names[name.Value] = true
+	}
+
+	// port (required)
+	if p, ok := m.get("port"); !ok {
+		bag.add(nil, codeServicePortRequired)
+	} else {
+		validatePort(p, bag, "port")
+	}
+
+	// targetPort (optional): номер или имя порта контейнера
+	if tp, ok := m.get("targetPort"); ok {
+		if isScalarString(tp) {
+			if !validPortName(tp.Value) {
+				bag.add(tp, codeTargetPortFormat, tp.Value)
+			}
+		} else {
+			validatePort(tp, bag, "targetPort")
+		}
+	}
+
+	// protocol (optional)
+	if proto, ok := m.get("protocol"); ok {
+		if !isScalarString(proto) {
+			bag.add(proto, codeProtocolType)
+		} else if !bag.rules.enums["protocol"].has(proto.Value) {
+			unsupported(bag, proto, codeProtocolUnsupported, codeProtocolCase, bag.rules.enums["protocol"].values())
+		}
+	}
+
+	// nodePort (optional): только у NodePort и LoadBalancer, в диапазоне
+	if np, ok := m.get("nodePort"); ok {
+		if !isScalarInt(np) {
+			bag.add(np, codePortNotInt, "nodePort")
+			return
+		}
+		if typ != "NodePort" && typ != "LoadBalancer" && typ != "" {
+			bag.add(np, codeNodePortNotAllowed, typ)
+			return
+		}
+		if v, err := toInt(np.Value); err != nil || v < nodePortMin || v > nodePortMax {
+			bag.add(np, codeNodePortRange, np.Value, nodePortMin, nodePortMax)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestServicePorts(t *testing.T) {
+	head := "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  selector: {app: web}\n"
+	tests := []struct {
+		name  string
+		ports string
+		want  string
+	}{
+		{"ports not array", "  ports: 80\n", codeServicePortsType},
+		{"item not object", "  ports: [80]\n", codeServicePortType},
+		{"port missing", "  ports:\n    - targetPort: 8080\n", codeServicePortRequired},
+		{"several without names", "  ports:\n    - port: 80\n    - port: 81\n", codeServicePortNameRequired},
+		{"bad targetPort name", "  ports:\n    - port: 80\n      targetPort: Http_Port\n", codeTargetPortFormat},
+	}
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		res, err := v.Validate([]byte(head + tt.ports))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := codes(res); !strings.Contains(" "+got+" ", " "+tt.want+" ") {
+			t.Errorf("%s: codes %q, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Выключенные проверки контейнеров и проб не должны глушить Service.
+func TestServiceCodesIndependent(t *testing.T) {
+	v, err := New(Config{Rules: map[string]string{
+		codeProbePortReq: "off", codePortsType: "off", codePortItemType: "off",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ports := range []string{"80", "[80]", "[{targetPort: 80}]"} {
+		res, err := v.Validate([]byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n  ports: " + ports + "\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v.Adjust(res)
+		if !strings.Contains(codes(res), "SVC") {
+			t.Errorf("ports %s: no SVC finding left: %q", ports, codes(res))
+		}
+	}
+}
+
+// service — Service со строками spec (отступ 2).
+func service(spec string) string {
+	return "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec:\n" + spec
+}
+
+func TestService(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"cluster ip", service("  selector: {app: web}\n  ports: [{name: http, port: 80, targetPort: http}, {name: https, port: 443, targetPort: 8443}]\n"), ""},
+		{"node port", service("  type: NodePort\n  ports: [{port: 80, nodePort: 30080}]\n"), ""},
+		{"headless", service("  clusterIP: None\n  selector: {app: web}\n"), ""},
+		{"external name", service("  type: ExternalName\n  externalName: db.example.com\n"), ""},
+		{"spec not object", "apiVersion: v1\nkind: Service\nmetadata:\n  name: web\nspec: ClusterIP\n", codeServiceSpecType},
+		{"type unsupported", service("  type: Internal\n  ports: [{port: 80}]\n"), codeServiceTypeUnsupported},
+		{"type case", service("  type: nodePort\n  ports: [{port: 80}]\n"), codeServiceTypeCase},
+		{"ports missing", service("  selector: {app: web}\n"), codeServicePortsRequired},
+		{"port name format", service("  ports: [{name: HTTP, port: 80}]\n"), codeServicePortName},
+		{"port name too long", service("  ports: [{name: http-metrics-port, port: 80}]\n"), codeServicePortName},
+		{"port name digits only", service("  ports: [{name: \"8080\", port: 80}]\n"), codeServicePortName},
+		{"port name duplicate", service("  ports: [{name: http, port: 80}, {name: http, port: 8080}]\n"), codeServicePortNameDup},
+		{"nodePort with ClusterIP", service("  ports: [{port: 80, nodePort: 30080}]\n"), codeNodePortNotAllowed},
+		{"nodePort range", service("  type: LoadBalancer\n  ports: [{port: 80, nodePort: 8080}]\n"), codeNodePortRange},
+		{"selector not map", service("  selector: [app]\n  ports: [{port: 80}]\n"), codeServiceSelectorType},
+		{"selector value not string", service("  selector: {app: {name: web}}\n  ports: [{port: 80}]\n"), codeServiceSelectorType},
+		{"clusterIP format", service("  clusterIP: 10.0.0\n  ports: [{port: 80}]\n"), codeClusterIPFormat},
+		{"externalName missing", service("  type: ExternalName\n"), codeExternalNameRequired},
+		{"externalName empty", service("  type: ExternalName\n  externalName: \"\"\n"), codeExternalNameRequired},
+	})
+}
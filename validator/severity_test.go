@@ -0,0 +1,51 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBestPractices(t *testing.T) {
+	image := func(ref string) string {
+		return strings.Replace(pod("", ""), "registry.bigbrother.io/web:1.0", ref, 1)
+	}
+	requestsOnly := strings.Replace(pod("", ""), "        limits: {cpu: 1, memory: 64Mi}\n", "", 1)
+	checkRules(t, Config{RulePacks: []string{"best-practices"}}, []ruleCase{
+		{"pinned", pod("", ""), ""},
+		{"latest", image("registry.bigbrother.io/web:latest"), codeImageLatestTag},
+		{"no tag", image("registry.bigbrother.io:5000/web"), codeImageLatestTag},
+		{"no limits", requestsOnly, codeLimitsMissing},
+	})
+}
+
+func TestFloatingTag(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"nginx":                              true,
+		"nginx:latest":                       true,
+		"registry.bigbrother.io:5000/web":    true,
+		"registry.bigbrother.io:5000/web:v1": false,
+		"web@sha256:abcd":                    false,
+	} {
+		if got := floatingTag(ref); got != want {
+			t.Errorf("floatingTag(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+// Советы — предупреждения: без --fail-on warning файл проходит.
+func TestBestPracticesSeverity(t *testing.T) {
+	v, err := New(Config{RulePacks: []string{"best-practices"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Validate([]byte(strings.Replace(pod("", ""), "web:1.0", "web:latest", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Issues) != 1 || res.Issues[0].Severity != SeverityWarning {
+		t.Fatalf("issues %+v, want one warning", res.Issues)
+	}
+	if res.FailedAt(SeverityError) || !res.FailedAt(SeverityWarning) || !res.FailedAt(SeverityInfo) {
+		t.Errorf("FailedAt does not treat a warning as failing at warning and below")
+	}
+}
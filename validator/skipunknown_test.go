@@ -0,0 +1,19 @@
+package validator
+
+import "testing"
+
+func TestSkipUnknownKinds(t *testing.T) {
+	widget := "apiVersion: example.com/v1\nkind: Widget\nmetadata:\n  name: w\nspec: {size: 3}\n"
+	checkRules(t, Config{}, []ruleCase{
+		{"unknown kind", widget, codeKindUnsupported},
+	})
+	checkRules(t, Config{SkipUnknownKinds: true}, []ruleCase{
+		{"skipped", widget, codeUnknownKind},
+		// известные kind'ы проверяются как обычно
+		{"known kind", pod("", "  os: {name: freebsd}\n"), codeOSUnsupported},
+	})
+	res := validate(t, Config{SkipUnknownKinds: true}, widget)
+	if len(res.Issues) != 1 || res.Issues[0].Severity != SeverityWarning {
+		t.Errorf("skipped document: %q, want one warning", codes(res))
+	}
+}
@@ -0,0 +1,196 @@
+// statefulset.go
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var (
+	podManagementPolicies    = []string{"OrderedReady", "Parallel"}
+	updateStrategyTypes      = []string{"OnDelete", "RollingUpdate"}
+	persistentVolumeAccesses = []string{"ReadOnlyMany", "ReadWriteMany", "ReadWriteOnce", "ReadWriteOncePod"}
+	volumeModes              = []string{"Block", "Filesystem"}
+)
+
+// reDNSLabel — имя в DNS-1123 label: serviceName становится частью
+// DNS-имён подов.
+var reDNSLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+func validateStatefulSetSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeWorkloadSpecType)
+		return
+	}
+	validateControllerSpec(m, bag)
+
+	// serviceName: headless Service, через который поды получают имена
+	if sn, ok := m.get("serviceName"); !ok {
+		bag.add(nil, codeServiceNameRequired)
+	} else if !isScalarString(sn) || !reDNSLabel.MatchString(sn.Value) {
+		bag.add(sn, codeServiceNameFormat, sn.Value)
+	}
+
+	// podManagementPolicy (optional)
+	if p, ok := m.get("podManagementPolicy"); ok && (!isScalarString(p) || !contains(podManagementPolicies, p.Value)) {
+		unsupported(bag, p, codePodManagementPolicy, codePodManagementCase, podManagementPolicies)
+	}
+
+	// updateStrategy (optional)
+	if us, ok := m.get("updateStrategy"); ok {
+		validateStatefulSetUpdate(us, bag)
+	}
+
+	// volumeClaimTemplates (optional)
+	if vct, ok := m.get("volumeClaimTemplates"); ok {
+		validateClaimTemplates(vct, bag)
+	}
+}
+
+func validateStatefulSetUpdate(n *yaml.Node, bag *errBag) {
+	rm := validateUpdateStrategy(n, bag)
+	if rm == nil {
+		return
+	}
+	if p, ok := rm.get("partition"); ok {
+		if v, err := toInt(p.Value); !isScalarInt(p) || err != nil || v < 0 {
+			bag.add(p, codePartition)
+		}
+	}
+	if mu, ok := rm.get("maxUnavailable"); ok && !intOrPercent(mu) {
+		bag.add(mu, codeIntOrPercent, "maxUnavailable")
+	}
+}
+
+// validateUpdateStrategy — updateStrategy StatefulSet и DaemonSet: type
+// RollingUpdate или OnDelete. Возвращает rollingUpdate для проверок
+// конкретного kind'а; nil, если его нет или он с ошибкой.
+func validateUpdateStrategy(n *yaml.Node, bag *errBag) fields {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeUpdateStrategyType)
+		return nil
+	}
+	typ := "RollingUpdate"
+	if t, ok := m.get("type"); ok {
+		if !isScalarString(t) || !contains(updateStrategyTypes, t.Value) {
+			unsupported(bag, t, codeUpdateStrategyUnsupported, codeUpdateStrategyCase, updateStrategyTypes)
+			return nil
+		}
+		typ = t.Value
+	}
+	ru, ok := m.get("rollingUpdate")
+	if !ok {
+		return nil
+	}
+	if typ == "OnDelete" {
+		bag.add(ru, codeRollingUpdateOnDelete)
+		return nil
+	}
+	rm, node := getMap(ru)
+	if rm == nil {
+		bag.add(node, codeUpdateStrategyType)
+		return nil
+	}
+	return rm
+}
+
+// intOrPercent — неотрицательное число или процент.
+func intOrPercent(n *yaml.Node) bool {
+	if isScalarInt(n) {
+		v, err := toInt(n.Value)
+		return err == nil && v >= 0
+	}
+	return isScalarString(n) && reIntOrPercent.MatchString(n.Value)
+}
+
+func validateClaimTemplates(n *yaml.Node, bag *errBag) {
+	if n.Kind != yaml.SequenceNode {
+		bag.add(n, codeClaimTemplatesType)
+		return
+	}
+	seen := map[string]bool{}
+	for _, c := range n.Content {
+		m, node := getMap(c)
+		if m == nil {
+			bag.add(node, codeClaimTemplateType)
+			continue
+		}
+		// имя шаблона — имя тома в volumeMounts контейнеров
+		name := ""
+		if meta, ok := m.get("metadata"); ok {
+			if nm, ok := child(meta, "name"); ok && isScalarString(nm) {
+				name = nm.Value
+			}
+		}
+		switch {
+		case strings.TrimSpace(name) == "":
+			bag.add(c, codeClaimTemplateName)
+		case seen[name]:
+			bag.add(c, codeClaimTemplateDup, name)
+		}
+		seen[name] = true
+
+		spec, ok := m.get("spec")
+		if !ok {
+			bag.add(c, codeSpecRequired)
+			continue
+		}
+		validateClaimSpec(spec, bag)
+	}
+}
+
+// validateClaimSpec — spec PersistentVolumeClaim: accessModes, размер
+// в resources.requests.storage, storageClassName и volumeMode; с классами
+// хранилища в инвентаре — и их совместимость.
+func validateClaimSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeClaimSpecType)
+		return
+	}
+	am, ok := m.get("accessModes")
+	switch {
+	case !ok:
+		bag.add(n, codeAccessModesRequired)
+	case am.Kind != yaml.SequenceNode || len(am.Content) == 0:
+		bag.add(am, codeAccessModesType)
+	default:
+		for _, mode := range am.Content {
+			if !isScalarString(mode) || !contains(persistentVolumeAccesses, mode.Value) {
+				unsupported(bag, mode, codeAccessModeUnsupported, codeAccessModeCase, persistentVolumeAccesses)
+			}
+		}
+	}
+
+	var storage *yaml.Node
+	if res, ok := m.get("resources"); ok {
+		if req, ok := child(res, "requests"); ok {
+			storage, _ = child(req, "storage")
+		}
+	}
+	if storage == nil {
+		bag.add(n, codeStorageRequired)
+	} else if _, err := ParseQuantity(storage.Value); err != nil || storage.Kind != yaml.ScalarNode {
+		bag.add(storage, codeStorageFormat, storage.Value)
+	}
+
+	// storageClassName: "" — явно без класса, без поля — класс по умолчанию
+	if sc, ok := m.get("storageClassName"); ok {
+		switch {
+		case !isScalarString(sc):
+			bag.add(sc, codeStorageClassType)
+		case sc.Value != "" && !reDNSSubdomain.MatchString(sc.Value):
+			bag.add(sc, codeStorageClassFormat, sc.Value)
+		}
+	}
+	if vm, ok := m.get("volumeMode"); ok {
+		if !isScalarString(vm) || !contains(volumeModes, vm.Value) {
+			unsupported(bag, vm, codeVolumeModeUnsupported, codeVolumeModeCase, volumeModes)
+		}
+	}
+	validateClaimStorageClass(m, bag)
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// statefulSet — StatefulSet с селектором, шаблоном и строками spec.
+func statefulSet(spec string) string {
+	return "apiVersion: apps/v1\nkind: StatefulSet\nmetadata:\n  name: web\nspec:\n" + webSelector + webTemplate + spec
+}
+
+// claim — volumeClaimTemplates с одним шаблоном data и его spec.
+func claim(spec string) string {
+	return statefulSet("  serviceName: web\n  volumeClaimTemplates:\n    - metadata: {name: data}\n      spec: " + spec + "\n")
+}
+
+const claimSpec = "{accessModes: [ReadWriteOnce], resources: {requests: {storage: 1Gi}}}"
+
+func TestStatefulSet(t *testing.T) {
+	update := func(s string) string { return statefulSet("  serviceName: web\n  updateStrategy: " + s + "\n") }
+	templates := func(s string) string { return statefulSet("  serviceName: web\n  volumeClaimTemplates: " + s + "\n") }
+	data := "{metadata: {name: data}, spec: " + claimSpec + "}"
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", statefulSet("  serviceName: web\n  podManagementPolicy: Parallel\n"), ""},
+		{"serviceName missing", statefulSet(""), codeServiceNameRequired},
+		{"serviceName format", statefulSet("  serviceName: Web.Headless\n"), codeServiceNameFormat},
+		{"podManagementPolicy", statefulSet("  serviceName: web\n  podManagementPolicy: Random\n"), codePodManagementPolicy},
+		{"podManagementPolicy case", statefulSet("  serviceName: web\n  podManagementPolicy: parallel\n"), codePodManagementCase},
+		{"rolling update", update("{type: RollingUpdate, rollingUpdate: {partition: 2, maxUnavailable: 50%}}"), ""},
+		{"update not object", update("OnDelete"), codeUpdateStrategyType},
+		{"update unsupported", update("{type: Recreate}"), codeUpdateStrategyUnsupported},
+		{"update case", update("{type: ondelete}"), codeUpdateStrategyCase},
+		{"rollingUpdate with OnDelete", update("{type: OnDelete, rollingUpdate: {partition: 1}}"), codeRollingUpdateOnDelete},
+		{"partition negative", update("{rollingUpdate: {partition: -1}}"), codePartition},
+		{"maxUnavailable", update("{rollingUpdate: {maxUnavailable: some}}"), codeIntOrPercent},
+		{"claim templates", templates("[" + data + "]"), ""},
+		{"templates not array", templates(data), codeClaimTemplatesType},
+		{"template not object", templates("[data]"), codeClaimTemplateType},
+		{"template name", templates("[{spec: " + claimSpec + "}]"), codeClaimTemplateName},
+		{"template duplicate", templates("[" + data + ", " + data + "]"), codeClaimTemplateDup},
+		{"template spec missing", templates("[{metadata: {name: data}}]"), codeSpecRequired},
+	})
+}
+
+func TestClaimSpec(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", claim(claimSpec), ""},
+		{"not object", claim("data"), codeClaimSpecType},
+		{"accessModes missing", claim("{resources: {requests: {storage: 1Gi}}}"), codeAccessModesRequired},
+		{"accessModes empty", claim("{accessModes: [], resources: {requests: {storage: 1Gi}}}"), codeAccessModesType},
+		{"accessMode unsupported", claim(strings.Replace(claimSpec, "ReadWriteOnce", "ReadWriteAll", 1)), codeAccessModeUnsupported},
+		{"accessMode case", claim(strings.Replace(claimSpec, "ReadWriteOnce", "readwriteonce", 1)), codeAccessModeCase},
+		{"storage missing", claim("{accessModes: [ReadWriteOnce]}"), codeStorageRequired},
+		{"storage format", claim(strings.Replace(claimSpec, "1Gi", "1GB", 1)), codeStorageFormat},
+	})
+}
@@ -0,0 +1,113 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+var testStorageClasses = []StorageClass{
+	{Name: "standard", AccessModes: []string{"ReadWriteOnce", "ReadWriteOncePod"}, Default: true},
+	{Name: "nfs", AccessModes: []string{"ReadWriteOnce", "ReadOnlyMany", "ReadWriteMany"}},
+	{Name: "legacy"},
+}
+
+func TestClaimStorageClass(t *testing.T) {
+	pvc := func(spec string) string {
+		return "apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: data\nspec:\n" +
+			"  resources: {requests: {storage: 1Gi}}\n" + spec
+	}
+	sts := func(spec string) string {
+		return `apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+spec:
+  serviceName: db
+  selector: {matchLabels: {app: db}}
+  template:
+    metadata: {labels: {app: db}}
+    spec:
+      containers:
+        - name: db
+          image: registry.bigbrother.io/db:1.0
+          resources: {limits: {cpu: 100m, memory: 64Mi}, requests: {cpu: 100m, memory: 64Mi}}
+          volumeMounts: [{name: data, mountPath: /data}]
+  volumeClaimTemplates:
+    - metadata: {name: data}
+      spec:
+        resources: {requests: {storage: 1Gi}}
+` + spec
+	}
+	tests := []struct {
+		name string
+		doc  string
+		want string // "" — находок SCH нет
+	}{
+		{"supported mode", pvc("  storageClassName: nfs\n  accessModes: [ReadWriteMany]\n"), ""},
+		{"unsupported mode", pvc("  storageClassName: standard\n  accessModes: [ReadWriteOnce, ReadWriteMany]\n"), codeStorageClassAccessMode},
+		{"default class", pvc("  accessModes: [ReadOnlyMany]\n"), codeStorageClassAccessMode},
+		{"unknown class", pvc("  storageClassName: fast\n  accessModes: [ReadWriteOnce]\n"), codeStorageClassUnknown},
+		{"static volume", pvc("  storageClassName: \"\"\n  accessModes: [ReadWriteMany]\n"), ""},
+		{"class without modes", pvc("  storageClassName: legacy\n  accessModes: [ReadWriteMany]\n"), ""},
+		{"claim template", sts("        storageClassName: standard\n        accessModes: [ReadWriteMany]\n"), codeStorageClassAccessMode},
+		{"claim template default", sts("        accessModes: [ReadWriteOnce]\n"), ""},
+	}
+	v, err := New(Config{Cluster: ClusterInventory{StorageClasses: testStorageClasses}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		res, err := v.Validate([]byte(tt.doc))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		got := ""
+		for _, code := range strings.Fields(codes(res)) {
+			if strings.HasPrefix(code, "SCH") {
+				got += code
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s: SCH findings %q, want %q (all: %s)", tt.name, got, tt.want, codes(res))
+		}
+	}
+}
+
+// Без классов в инвентаре проверка молчит.
+func TestClaimStorageClassNoInventory(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := v.Validate([]byte("apiVersion: v1\nkind: PersistentVolumeClaim\nmetadata:\n  name: data\nspec:\n" +
+		"  storageClassName: fast\n  accessModes: [ReadWriteMany]\n  resources: {requests: {storage: 1Gi}}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := codes(res); got != "" {
+		t.Errorf("codes %q, want none", got)
+	}
+}
+
+func TestCheckStorageClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		classes []StorageClass
+		err     string
+	}{
+		{"valid", testStorageClasses, ""},
+		{"bad name", []StorageClass{{Name: "Fast_SSD"}}, "invalid name"},
+		{"duplicate", []StorageClass{{Name: "nfs"}, {Name: "nfs"}}, "listed twice"},
+		{"unknown mode", []StorageClass{{Name: "nfs", AccessModes: []string{"ReadWriteAll"}}}, "unknown access mode 'ReadWriteAll'"},
+		{"two defaults", []StorageClass{{Name: "a", Default: true}, {Name: "b", Default: true}}, "both default"},
+	}
+	for _, tt := range tests {
+		_, err := New(Config{Cluster: ClusterInventory{StorageClasses: tt.classes}})
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// thresholds.go
+package validator
+
+import (
+	"fmt"
+	"path"
+)
+
+// threshold — порог из Config.Thresholds: сколько находок правил codes
+// допустимо на весь набор файлов.
+type threshold struct {
+	key   string
+	max   int
+	codes map[string]bool
+}
+
+// compileThresholds разворачивает ключи порогов в коды: код, имя
+// проверки или маска кодов path.Match, например IMG*.
+func compileThresholds(in map[string]int) ([]threshold, error) {
+	byName := make(map[string]string, len(ruleNames))
+	for code, name := range ruleNames {
+		byName[name] = code
+	}
+	out := make([]threshold, 0, len(in))
+	for _, key := range sortedKeys(in) {
+		t := threshold{key: key, max: in[key], codes: map[string]bool{}}
+		if t.max < 0 {
+			return nil, fmt.Errorf("%s: must be non-negative, got %d", key, t.max)
+		}
+		if code, ok := byName[key]; ok {
+			t.codes[code] = true
+		}
+		for code := range ruleNames {
+			ok, err := path.Match(key, code)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", key, err)
+			}
+			if ok {
+				t.codes[code] = true
+			}
+		}
+		if len(t.codes) == 0 {
+			return nil, fmt.Errorf("unknown rule '%s'", key)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// Breach — превышенный порог: находок правила в наборе больше Max.
+type Breach struct {
+	Rule  string
+	Max   int
+	Count int
+}
+
+func (b Breach) String() string {
+	return fmt.Sprintf("%s: %d findings exceed threshold %d", b.Rule, b.Count, b.Max)
+}
+
+// Breaches сверяет находки всего набора с Config.Thresholds. Считаются
+// находки любой важности после Adjust: отключённые правила порог не
+// тратят. Так команда снижает долг постепенно — порог уменьшают по мере
+// исправлений, а новые находки сверх него валят прогон.
+func (v *Validator) Breaches(results []*Result) []Breach {
+	var out []Breach
+	for _, t := range v.rules.thresholds {
+		n := 0
+		for _, res := range results {
+			for _, is := range res.Issues {
+				if t.codes[is.Code] {
+					n++
+				}
+			}
+		}
+		if n > t.max {
+			out = append(out, Breach{Rule: t.key, Max: t.max, Count: n})
+		}
+	}
+	return out
+}
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBreaches(t *testing.T) {
+	v, err := New(Config{Thresholds: map[string]int{
+		"IMG*":           1,
+		"os-unsupported": 1,
+		"PRT003":         0,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	issue := func(code string) Issue { return Issue{Code: code} }
+	results := []*Result{
+		{Issues: []Issue{issue(CodeImageNotFound), issue(codeOSUnsupported)}},
+		{Issues: []Issue{issue(CodeImageVulnerable), issue(codeOSUnsupported)}},
+		{},
+	}
+	want := []Breach{{Rule: "IMG*", Max: 1, Count: 2}, {Rule: "os-unsupported", Max: 1, Count: 2}}
+	if got := v.Breaches(results); !reflect.DeepEqual(got, want) {
+		t.Errorf("breaches %+v, want %+v", got, want)
+	}
+	if got := want[0].String(); got != "IMG*: 2 findings exceed threshold 1" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestThresholdsConfig(t *testing.T) {
+	for name, th := range map[string]map[string]int{
+		"negative":     {"PRT003": -1},
+		"unknown rule": {"no-such-rule": 1},
+		"no match":     {"ZZZ*": 1},
+		"bad mask":     {"IMG[": 1},
+	} {
+		if _, err := New(Config{Thresholds: th}); err == nil {
+			t.Errorf("%s: config accepted", name)
+		}
+	}
+}
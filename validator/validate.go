@@ -0,0 +1,578 @@
+// validate.go
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- input detection ----------
+
+// isBinary — как git: NUL в первых 8000 байтах.
+func isBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// looksLikeManifest отсекает посторонние YAML-конфиги: у манифеста
+// есть хотя бы apiVersion или kind. Без обоих ключей валидация дала бы
+// только поток "is required".
+func looksLikeManifest(doc *yaml.Node) bool {
+	if doc.Kind != yaml.MappingNode {
+		return true // "root must be object" полезнее, чем пропуск
+	}
+	_, hasAPI := child(doc, "apiVersion")
+	_, hasKind := child(doc, "kind")
+	return hasAPI || hasKind
+}
+
+// ---------- helpers over yaml.Node ----------
+
+// fields — пары ключ/значение mapping-узла. Вместо map на каждый узел:
+// объекты в манифестах маленькие, линейный поиск дешевле аллокаций.
+type fields []*yaml.Node
+
+func (f fields) get(key string) (*yaml.Node, bool) {
+	for i := 0; i+1 < len(f); i += 2 {
+		if f[i].Value == key {
+			return f[i+1], true
+		}
+	}
+	return nil, false
+}
+
+// getMap возвращает nil, если узел не mapping; для пустого {} — пустые,
+// но не nil fields.
+func getMap(doc *yaml.Node) (fields, *yaml.Node) {
+	if doc.Kind != yaml.MappingNode {
+		return nil, doc
+	}
+	if doc.Content == nil {
+		return fields{}, doc
+	}
+	return fields(doc.Content), doc
+}
+
+func child(doc *yaml.Node, key string) (*yaml.Node, bool) {
+	if doc.Kind != yaml.MappingNode {
+		return nil, false
+	}
+	for i := 0; i < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == key {
+			return doc.Content[i+1], true
+		}
+	}
+	return nil, false
+}
+
+func isScalarString(n *yaml.Node) bool { return n.Kind == yaml.ScalarNode && (n.Tag == "!!str" || n.Tag == "") }
+func isScalarInt(n *yaml.Node) bool    { return n.Kind == yaml.ScalarNode && n.Tag == "!!int" }
+
+// ---------- validators ----------
+
+// supportedKinds — kind'ы, для которых есть валидатор, и их apiVersion;
+// enums.apiVersion дополняет список для каждого из них.
+var supportedKinds = map[string]string{"Pod": "v1", "Deployment": "apps/v1", "StatefulSet": "apps/v1", "DaemonSet": "apps/v1", "Job": "batch/v1", "CronJob": "batch/v1", "Service": "v1", "Ingress": "networking.k8s.io/v1", "PersistentVolumeClaim": "v1", "HorizontalPodAutoscaler": "autoscaling/v2", "NetworkPolicy": "networking.k8s.io/v1", "PodDisruptionBudget": "policy/v1", "ConfigMap": "v1", "Secret": "v1"}
+
+// unsupported — значение n не из списка allowed. Если оно отличается от
+// допустимого только регистром или пробелами по краям, это отдельная
+// находка caseCode с «did you mean» и правкой для --fix: такие опечатки
+// встречаются чаще всего. Текст сообщения в обоих случаях прежний,
+// различаются код и подсказка.
+func unsupported(bag *errBag, n *yaml.Node, code, caseCode string, allowed []string) {
+	if caseCode != "" {
+		for _, v := range allowed {
+			if strings.EqualFold(v, strings.TrimSpace(n.Value)) {
+				bag.add(n, caseCode, n.Value)
+				bag.help("did you mean '%s'?", v)
+				bag.fixValue(n, v)
+				return
+			}
+		}
+	}
+	bag.add(n, code, n.Value)
+	bag.help("allowed: %s", strings.Join(allowed, ", "))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func validateTopLevel(ctx *ValidationContext, bag *errBag) {
+	doc := ctx.Doc
+	m, node := getMap(doc)
+	if m == nil {
+		bag.add(node, codeRootType)
+		return
+	}
+
+	// apiVersion; для известного kind допустима только его группа
+	apiVersions := bag.rules.enums["apiVersion"]
+	kindValue := ""
+	if kind, ok := m.get("kind"); ok && isScalarString(kind) {
+		kindValue = kind.Value
+		if set, ok := bag.rules.kindAPIVersions[kind.Value]; ok {
+			apiVersions = set
+		}
+	}
+	api, ok := m.get("apiVersion")
+	if !ok {
+		bag.add(nil, codeAPIVersionRequired)
+	} else {
+		if !isScalarString(api) {
+			bag.add(api, codeAPIVersionType)
+		} else if !apiVersions.has(api.Value) {
+			unsupported(bag, api, codeAPIVersionUnsupported, codeAPIVersionCase, apiVersions.values())
+		}
+	}
+
+	// kind
+	kind, ok := m.get("kind")
+	if !ok {
+		bag.add(nil, codeKindRequired)
+	} else {
+		if !isScalarString(kind) {
+			bag.add(kind, codeKindType)
+		} else if _, ok := supportedKinds[kind.Value]; !ok {
+			unsupported(bag, kind, codeKindUnsupported, codeKindCase, sortedKeys(supportedKinds))
+		}
+	}
+
+	// metadata
+	meta, ok := m.get("metadata")
+	if !ok {
+		bag.add(nil, codeMetadataRequired)
+	} else {
+		validateObjectMeta(meta, bag)
+	}
+	for _, name := range bag.rules.packs {
+		knownPacks[name](ctx, bag)
+	}
+
+	// у ConfigMap и Secret нет spec: данные на верхнем уровне
+	switch kindValue {
+	case "ConfigMap":
+		validateConfigMap(m, bag)
+		return
+	case "Secret":
+		validateSecret(m, bag)
+		return
+	}
+
+	// spec
+	spec, ok := m.get("spec")
+	switch {
+	case !ok:
+		bag.add(nil, codeSpecRequired)
+	case kindValue == "Deployment":
+		validateDeploymentSpec(spec, bag)
+	case kindValue == "StatefulSet":
+		validateStatefulSetSpec(spec, bag)
+	case kindValue == "DaemonSet":
+		validateDaemonSetSpec(spec, bag)
+	case kindValue == "Job":
+		validateJobSpec(spec, bag)
+	case kindValue == "CronJob":
+		validateCronJobSpec(spec, bag)
+	case kindValue == "Service":
+		validateServiceSpec(spec, bag)
+	case kindValue == "Ingress":
+		validateIngressSpec(spec, bag)
+	case kindValue == "PersistentVolumeClaim":
+		validateClaimSpec(spec, bag)
+	case kindValue == "HorizontalPodAutoscaler":
+		validateHPASpec(spec, bag)
+	case kindValue == "NetworkPolicy":
+		validateNetworkPolicySpec(spec, bag)
+	case kindValue == "PodDisruptionBudget":
+		validatePDBSpec(spec, bag)
+	default:
+		validatePodSpec(spec, bag)
+	}
+}
+
+func validateObjectMeta(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeMetadataType)
+		return
+	}
+
+	// name (required, non-empty)
+	name, ok := m.get("name")
+	if !ok {
+		bag.add(nil, codeNameRequired)
+	} else if !isScalarString(name) {
+		bag.add(name, codeNameType)
+	} else if strings.TrimSpace(name.Value) == "" {
+		// пустая строка — считаем как отсутствие обязательного поля
+		bag.add(name, codeNameRequired)
+	}
+
+	// namespace (optional)
+	if ns, ok := m.get("namespace"); ok {
+		if !isScalarString(ns) {
+			bag.add(ns, codeNamespace)
+		}
+	}
+
+	// labels (optional)
+	if labels, ok := m.get("labels"); ok {
+		if labels.Kind != yaml.MappingNode {
+			bag.add(labels, codeLabelsType)
+		} else {
+			for i := 0; i < len(labels.Content); i += 2 {
+				k := labels.Content[i]
+				v := labels.Content[i+1]
+				if !isScalarString(k) || !isScalarString(v) {
+					bag.add(v, codeLabelsType)
+					break
+				}
+			}
+		}
+	}
+}
+
+func validatePodSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeSpecType)
+		return
+	}
+
+	// os (optional)
+	if osn, ok := m.get("os"); ok {
+		validatePodOS(osn, bag)
+	}
+
+	// priorityClassName (optional); наличие класса в кластере проверяет
+	// validatePriorityClass
+	if pc, ok := m.get("priorityClassName"); ok {
+		if !isScalarString(pc) {
+			bag.add(pc, codePriorityClassType)
+		} else if len(pc.Value) > maxNameLen || !reDNSSubdomain.MatchString(pc.Value) {
+			bag.add(pc, codePriorityClassFormat, pc.Value)
+		}
+	}
+
+	// имена контейнеров уникальны в поде вместе с initContainers
+	seen := map[string]struct{}{}
+	checkName := func(c *yaml.Node, name string) {
+		if name != "" {
+			if _, dup := seen[name]; dup {
+				bag.add(c, codeContainerNameDup, name)
+			}
+			seen[name] = struct{}{}
+		}
+	}
+
+	// initContainers (optional): те же правила, что у containers
+	if init, ok := m.get("initContainers"); ok {
+		if init.Kind != yaml.SequenceNode {
+			bag.add(init, codeInitContainersType)
+		} else {
+			for _, c := range init.Content {
+				checkName(c, validateContainer(c, bag))
+			}
+		}
+	}
+
+	// containers (required)
+	cont, ok := m.get("containers")
+	if !ok {
+		bag.add(nil, codeContainersRequired)
+	} else {
+		if cont.Kind != yaml.SequenceNode {
+			bag.add(cont, codeContainersType)
+		} else if len(cont.Content) == 0 {
+			bag.add(cont, codeContainersEmpty)
+		} else {
+			for _, c := range cont.Content {
+				checkName(c, validateContainer(c, bag))
+			}
+		}
+	}
+
+	// volumes (optional)
+	if vols, ok := m.get("volumes"); ok {
+		validateVolumes(vols, bag)
+	}
+}
+
+// Поддерживаем:
+// 1) os: "linux"|"windows"
+// 2) os: { name: "linux"|"windows" }
+// Список значений расширяется через enums.os в конфиге.
+func validatePodOS(n *yaml.Node, bag *errBag) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if !isScalarString(n) {
+			bag.add(n, codeOSType)
+			return
+		}
+		if !bag.rules.enums["os"].has(strings.ToLower(n.Value)) {
+			unsupported(bag, n, codeOSUnsupported, codeOSCase, bag.rules.enums["os"].values())
+		}
+	case yaml.MappingNode:
+		osName, ok := child(n, "name")
+		if !ok {
+			bag.add(nil, codeOSNameRequired)
+			return
+		}
+		if !isScalarString(osName) {
+			bag.add(osName, codeOSNameType)
+			return
+		}
+		if !bag.rules.enums["os"].has(strings.ToLower(osName.Value)) {
+			unsupported(bag, osName, codeOSUnsupported, codeOSCase, bag.rules.enums["os"].values())
+		}
+	default:
+		bag.add(n, codeOSType)
+	}
+}
+
+var reDNSSubdomain = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+func validateContainer(n *yaml.Node, bag *errBag) (nameOut string) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeContainerType)
+		return ""
+	}
+
+	// name
+	name, ok := m.get("name")
+	if !ok {
+		bag.add(nil, codeContainerNameReq)
+	} else {
+		if !isScalarString(name) {
+			bag.add(name, codeContainerNameType)
+		} else if strings.TrimSpace(name.Value) == "" {
+			// пустое имя — трактуем как отсутствие обязательного поля (ожидание автотеста)
+			bag.add(name, codeContainerNameReq)
+		} else if !bag.rules.containerNames.re.MatchString(name.Value) {
+			bag.add(name, codeContainerNameFormat, name.Value)
+			bag.help("expected %s", bag.rules.containerNames.desc)
+		}
+		nameOut = name.Value
+	}
+
+	// image
+	img, ok := m.get("image")
+	if !ok {
+		bag.add(nil, codeImageRequired)
+	} else if !isScalarString(img) {
+		bag.add(img, codeImageType)
+	} else if help := bag.rules.images.check(img.Value); help != "" {
+		bag.add(img, codeImageFormat, img.Value)
+		bag.help("%s", help)
+	} else {
+		bag.images = append(bag.images, Image{Line: img.Line, Column: img.Column, Ref: img.Value})
+	}
+
+	// ports
+	if ports, ok := m.get("ports"); ok {
+		if ports.Kind != yaml.SequenceNode {
+			bag.add(ports, codePortsType)
+		} else {
+			for _, p := range ports.Content {
+				validateContainerPort(p, bag)
+			}
+		}
+	}
+
+	// env
+	if env, ok := m.get("env"); ok {
+		validateEnv(env, bag)
+	}
+
+	// volumeMounts (optional)
+	if vm, ok := m.get("volumeMounts"); ok {
+		validateVolumeMounts(vm, bag)
+	}
+
+	// probes
+	if rp, ok := m.get("readinessProbe"); ok {
+		validateProbe(rp, bag, "readinessProbe")
+	}
+	if lp, ok := m.get("livenessProbe"); ok {
+		validateProbe(lp, bag, "livenessProbe")
+	}
+
+	// resources
+	res, ok := m.get("resources")
+	if !ok {
+		bag.add(nil, codeResourcesRequired)
+	} else {
+		validateResourceRequirements(res, bag)
+	}
+
+	return nameOut
+}
+
+func validateContainerPort(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codePortItemType)
+		return
+	}
+
+	// containerPort
+	cp, ok := m.get("containerPort")
+	if !ok {
+		bag.add(nil, codeContainerPortReq)
+	} else {
+		validatePort(cp, bag, "containerPort")
+	}
+
+	// hostPort (optional)
+	if hp, ok := m.get("hostPort"); ok {
+		validatePort(hp, bag, "hostPort")
+	}
+
+	// protocol
+	if proto, ok := m.get("protocol"); ok {
+		if !isScalarString(proto) {
+			bag.add(proto, codeProtocolType)
+		} else if !bag.rules.enums["protocol"].has(proto.Value) {
+			unsupported(bag, proto, codeProtocolUnsupported, codeProtocolCase, bag.rules.enums["protocol"].values())
+		}
+	}
+}
+
+func validateProbe(n *yaml.Node, bag *errBag, field string) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeProbeType, field)
+		return
+	}
+	// сравниваем с учётом значений по умолчанию: timeoutSeconds: 15 без
+	// periodSeconds — это 15 против 10
+	timeout, period := probeDefaults["timeoutSeconds"], probeDefaults["periodSeconds"]
+	at := n
+	if v, ok := m.get("timeoutSeconds"); ok && isScalarInt(v) {
+		timeout, _ = toInt(v.Value)
+		at = v
+	}
+	if v, ok := m.get("periodSeconds"); ok && isScalarInt(v) {
+		period, _ = toInt(v.Value)
+	}
+	if timeout > period {
+		bag.warn(at, codeProbeTimeout, timeout, period)
+	}
+	get, ok := m.get("httpGet")
+	if !ok {
+		bag.add(nil, codeHTTPGetRequired)
+		return
+	}
+	validateHTTPGet(get, bag)
+}
+
+func validateHTTPGet(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeHTTPGetType)
+		return
+	}
+
+	// path
+	p, ok := m.get("path")
+	if !ok {
+		bag.add(nil, codePathRequired)
+	} else if !isScalarString(p) {
+		bag.add(p, codePathType)
+	} else if !strings.HasPrefix(p.Value, "/") {
+		bag.add(p, codePathFormat, p.Value)
+	}
+
+	// port
+	pt, ok := m.get("port")
+	if !ok {
+		bag.add(nil, codeProbePortReq)
+	} else {
+		validatePort(pt, bag, "port")
+	}
+}
+
+func validatePort(n *yaml.Node, bag *errBag, field string) {
+	if !isScalarInt(n) {
+		bag.add(n, codePortNotInt, field)
+		return
+	}
+	val, err := toInt(n.Value)
+	switch {
+	case err != nil && strings.HasPrefix(n.Value, "-"), err == nil && val < 1:
+		bag.add(n, codePortNotPositive, field)
+	case err != nil, val > 65535:
+		bag.add(n, codePortTooLarge, field)
+	}
+}
+
+var reMem = regexp.MustCompile(`^\d+(Ki|Mi|Gi)$`)
+
+func validateResourceRequirements(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeResourcesType)
+		return
+	}
+	if lim, ok := m.get("limits"); ok {
+		validateResourceMap(lim, bag, "limits")
+	}
+	if req, ok := m.get("requests"); ok {
+		validateResourceMap(req, bag, "requests")
+	}
+}
+
+func validateResourceMap(n *yaml.Node, bag *errBag, field string) {
+	if n.Kind != yaml.MappingNode {
+		bag.add(n, codeResourceMap, field)
+		return
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		k := n.Content[i]
+		v := n.Content[i+1]
+		if !isScalarString(k) {
+			bag.add(v, codeResourceMap, field)
+			continue
+		}
+		switch k.Value {
+		case "cpu":
+			if !isScalarInt(v) {
+				bag.add(v, codeCPUType)
+			}
+		case "memory":
+			if !isScalarString(v) {
+				bag.add(v, codeMemoryType)
+			} else if !reMem.MatchString(v.Value) {
+				bag.add(v, codeMemoryFormat, v.Value)
+			}
+		default:
+			// лишние ключи игнорируем
+		}
+	}
+}
+
+// --------- small utils ----------
+
+// toInt понимает все формы !!int из YAML (0x1F, 0o17, 1_000).
+func toInt(s string) (int, error) {
+	x, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 0, 64)
+	if err != nil {
+		return 0, errors.New("not int")
+	}
+	return int(x), nil
+}
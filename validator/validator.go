@@ -0,0 +1,560 @@
+// validator.go
+
+// Package validator — ядро yamlvalid: проверка Kubernetes-манифестов без
+// обращения к файловой системе и сети, чтобы его можно было собирать в
+// WebAssembly и встраивать в другие программы. Чтение файлов, конфига и
+// онлайн-проверки живут в CLI.
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Severity: ошибки валят прогон, предупреждения и заметки только печатаются.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+var severityNames = map[string]Severity{"error": SeverityError, "warning": SeverityWarning, "info": SeverityInfo}
+
+func (s Severity) String() string {
+	for name, sev := range severityNames {
+		if sev == s {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+func (s Severity) MarshalJSON() ([]byte, error) { return json.Marshal(s.String()) }
+
+// ParseSeverity понимает error, warning и info.
+func ParseSeverity(s string) (Severity, error) {
+	if sev, ok := severityNames[s]; ok {
+		return sev, nil
+	}
+	return 0, fmt.Errorf("unknown severity '%s'", s)
+}
+
+// Issue — одна находка: код и аргументы шаблона, текст собирается через
+// Message/Render. Line == 0, если у находки нет строки (например,
+// отсутствующее обязательное поле); Column считается с 1.
+type Issue struct {
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Args     []any    `json:"args,omitempty"`
+	Fix      *Fix     `json:"fix,omitempty"`
+	// Document — номер документа в файле, начиная с 1; у находок первого
+	// документа и файла целиком не заполнен. По нему находку без строки
+	// можно отнести к документу.
+	Document int `json:"document,omitempty"`
+	// Item — номер элемента items в kind: List, начиная с 1.
+	Item int `json:"item,omitempty"`
+	// Help — подсказка, как исправить: допустимые значения, «did you
+	// mean». В плоский текстовый вывод не попадает, строки находок там
+	// неизменны.
+	Help string `json:"help,omitempty"`
+	// Fingerprint — отпечаток находки для yamlvalid suppress: файл, код и
+	// текст без строки. Заполняет CLI с --feedback-file.
+	Fingerprint string `json:"fingerprint,omitempty"`
+	// DocumentSHA256 — sha256 текста документа находки; у находок файла
+	// целиком и входов без исходного текста (Terraform) не заполнен.
+	DocumentSHA256 string `json:"documentSha256,omitempty"`
+}
+
+// Fix — правка исходника, которая убирает находку: текст Old, начиная со
+// строки Line и колонки Column, заменяется на New. Old сверяется перед
+// записью, так что правка по устаревшему результату файл не испортит.
+// Если Lines > 0, правка вместо этого удаляет Lines строк целиком, начиная
+// с Line, а Old — начало первой из них после отступа.
+type Fix struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	Lines  int    `json:"lines,omitempty"`
+}
+
+// Image — ссылка на образ, прошедшая проверку формата.
+type Image struct {
+	Line   int    `json:"line"`
+	Column int    `json:"column,omitempty"`
+	Ref    string `json:"ref"`
+}
+
+type Result struct {
+	Issues     []Issue     `json:"issues"`
+	Images     []Image     `json:"images,omitempty"`
+	Workloads  []Workload  `json:"workloads,omitempty"`
+	Quotas     []Quota     `json:"quotas,omitempty"`
+	Objects    []ObjectRef `json:"objects,omitempty"`
+	References []Reference `json:"references,omitempty"`
+	Facts      []Facts     `json:"facts,omitempty"`
+	// Documents — дайджесты проверенных документов файла.
+	Documents []DocumentDigest `json:"documents,omitempty"`
+	// Secrets — чувствительные значения входа (Secret, env с паролями)
+	// для Redact; наружу не сериализуются.
+	Secrets []string `json:"-"`
+}
+
+// Failed — есть ли среди находок ошибки.
+func (r *Result) Failed() bool { return r.FailedAt(SeverityError) }
+
+// FailedAt — есть ли находки важностью не ниже sev: FailedAt(SeverityWarning)
+// валит прогон и на предупреждениях.
+func (r *Result) FailedAt(sev Severity) bool {
+	for _, is := range r.Issues {
+		if is.Severity <= sev {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueError — находка в виде error, чтобы её можно было достать из
+// Result.Err через errors.As.
+type IssueError struct {
+	Issue Issue
+}
+
+func (e *IssueError) Error() string {
+	if e.Issue.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Issue.Line, e.Issue.Message())
+	}
+	return e.Issue.Message()
+}
+
+// Err собирает ошибки результата в одну через errors.Join, каждая —
+// *IssueError; nil, если ошибок нет. Предупреждения и заметки прогон не
+// валят и в error не попадают.
+func (r *Result) Err() error {
+	var errs []error
+	for _, is := range r.Issues {
+		if is.Severity == SeverityError {
+			errs = append(errs, &IssueError{Issue: is})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validator хранит правила, скомпилированные из Config; безопасен для
+// использования из нескольких горутин.
+type Validator struct {
+	rules *rules
+}
+
+func New(cfg Config) (*Validator, error) {
+	r, err := compileRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{rules: r}, nil
+}
+
+// Validate проверяет содержимое одного файла. Ошибка возвращается только
+// если YAML не разбирается; всё остальное — находки в Result.
+func (v *Validator) Validate(data []byte) (*Result, error) {
+	return v.ValidateFile("", data)
+}
+
+// ValidateToError — Validate для тех, кому нужен просто error: nil, если
+// ошибок нет, иначе ошибка разбора или Result.Err.
+func (v *Validator) ValidateToError(data []byte) error {
+	res, err := v.Validate(data)
+	if err != nil {
+		return err
+	}
+	return res.Err()
+}
+
+// Adjust применяет Config.Rules к находкам, которые добавили снаружи
+// ядра (онлайн-проверки, проверки по набору файлов). Повторный вызов
+// ничего не меняет.
+func (v *Validator) Adjust(res *Result) {
+	kept := res.Issues[:0]
+	for _, is := range res.Issues {
+		if s, ok := v.rules.settings[is.Code]; ok {
+			if s.off {
+				continue
+			}
+			is.Severity = s.sev
+		}
+		kept = append(kept, is)
+	}
+	res.Issues = kept
+}
+
+// ValidateFile — Validate с путём файла для правил, завязанных на его имя
+// и каталог (fileName, layout). Путь только сравнивается, сам файл не
+// читается.
+func (v *Validator) ValidateFile(path string, data []byte) (*Result, error) {
+	return v.validateFile(path, data, false)
+}
+
+// ValidateScanned — ValidateFile для файла, который нашёл обход каталога
+// или маска, а не назвал пользователь: бинарные файлы и посторонние YAML
+// без apiVersion и kind пропускаются с заметкой (Config.NonManifest).
+func (v *Validator) ValidateScanned(path string, data []byte) (*Result, error) {
+	return v.validateFile(path, data, true)
+}
+
+func (v *Validator) validateFile(path string, data []byte, scanned bool) (*Result, error) {
+	if scanned && isBinary(data) {
+		bag := &errBag{rules: v.rules}
+		bag.report(v.rules.nonManifest, nil, codeBinarySkipped)
+		return bag.result(), nil
+	}
+
+	docs, err := v.rules.engine.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	out := &errBag{rules: v.rules}
+	if len(docs) == 0 && !v.rules.emptyDocumentOff {
+		// пустой файл или одни комментарии: декодер не вернул ни одного документа
+		code := codeEmptyFile
+		if len(bytes.TrimSpace(data)) > 0 {
+			code = codeCommentsOnly
+		}
+		out.report(v.rules.emptyDocument, nil, code)
+	}
+	digests := documentDigests(data, docs)
+	v.validateDocuments(path, docs, digests, scanned, out)
+	res := out.result()
+	res.Documents = digests
+	return res, nil
+}
+
+// ValidateDocuments проверяет уже разобранные документы — для входов, где
+// манифесты вложены в другой формат (Terraform) и строки узлов указывают
+// на исходный файл. Файловые правила применяются ко всему набору.
+func (v *Validator) ValidateDocuments(path string, docs []*yaml.Node) *Result {
+	out := &errBag{rules: v.rules}
+	v.validateDocuments(path, docs, nil, false, out)
+	return out.result()
+}
+
+// scanned — файл найден обходом каталога: документы, не похожие на
+// манифест, пропускаются. digests[i] — дайджест docs[i], nil — без
+// исходного текста.
+func (v *Validator) validateDocuments(path string, docs []*yaml.Node, digests []DocumentDigest, scanned bool, out *errBag) {
+	// документы независимы: проверяем параллельно, а склеиваем в исходном
+	// порядке, чтобы вывод не зависел от планировщика
+	// если документов много, ядра уже заняты ими, и группы правил внутри
+	// документа идут последовательно — без лишних горутин
+	groupWorkers := len(ruleGroups)
+	if len(docs) > 1 {
+		groupWorkers = 1
+	}
+	bags := make([]*errBag, len(docs))
+	parallel(len(docs), runtime.GOMAXPROCS(0), func(i int) {
+		if isNullDocument(docs[i]) {
+			// завершающий --- после документов — просто разделитель
+			trailing := i > 0 && i == len(docs)-1
+			bags[i] = &errBag{rules: v.rules}
+			if !trailing && !v.rules.emptyDocumentOff {
+				bags[i].report(v.rules.emptyDocument, docs[i], codeEmptyDocument)
+			}
+			return
+		}
+		if isList(docs[i]) {
+			bags[i] = v.validateList(docs[i], scanned, groupWorkers)
+			return
+		}
+		bags[i] = v.validateDocument(docs[i], scanned, groupWorkers)
+	})
+	for i, b := range bags {
+		for j := range b.facts {
+			b.facts[j].Document = i + 1
+		}
+		for j := range b.list {
+			if i > 0 {
+				b.list[j].Document = i + 1
+			}
+			if digests != nil {
+				b.list[j].DocumentSHA256 = digests[i].SHA256
+			}
+		}
+		out.merge(b)
+	}
+	docs = expandLists(docs)
+	checkDocumentPolicy(docs, out)
+	if path != "" {
+		checkFileName(path, docs, out)
+		checkLayout(path, docs, out)
+	}
+}
+
+// checkDocumentPolicy — находки уровня файла: сколько в нём документов и
+// все ли обязательные kind'ы есть.
+func checkDocumentPolicy(docs []*yaml.Node, bag *errBag) {
+	policy := bag.rules.documents
+	count := 0
+	kinds := map[string]bool{}
+	for _, doc := range docs {
+		if isNullDocument(doc) {
+			continue
+		}
+		count++
+		if kind, ok := child(doc, "kind"); ok && isScalarString(kind) {
+			kinds[kind.Value] = true
+		}
+	}
+	if count < policy.Min {
+		bag.add(nil, codeTooFewDocuments, count, policy.Min)
+	}
+	if policy.Max > 0 && count > policy.Max {
+		bag.add(nil, codeTooManyDocuments, count, policy.Max)
+	}
+	for _, kind := range policy.RequireKinds {
+		if !kinds[kind] {
+			bag.add(nil, codeKindMissing, kind)
+		}
+	}
+}
+
+// ruleGroups — независимые друг от друга проходы по документу; на больших
+// объектах (дампы ConfigMap'ов) подсчёт размеров сравним со всем остальным.
+var ruleGroups = []func(ctx *ValidationContext, bag *errBag){
+	validateTopLevel,
+	validateSizeLimits,
+	validateImageArch,
+	validateScheduling,
+	validatePriorityClass,
+	collectWorkload,
+	validateAnnotationRules,
+	validateServerFields,
+	validateYAMLVersions,
+	collectSecrets,
+	validateVolumeRefs,
+	collectFacts,
+}
+
+func (v *Validator) validateDocument(doc *yaml.Node, scanned bool, groupWorkers int) *errBag {
+	bag := &errBag{rules: v.rules}
+	// документ не выбран фильтрами — как будто его нет во входе
+	if !v.rules.filter.empty() && !v.rules.filter.allows(doc) {
+		return bag
+	}
+	if scanned && !looksLikeManifest(doc) {
+		bag.report(v.rules.nonManifest, doc, codeNotManifest)
+		return bag
+	}
+	collectObject(doc, bag)
+	kind, _ := child(doc, "kind")
+	if kind != nil && kind.Value == "ResourceQuota" {
+		collectQuota(doc, bag)
+	}
+	if v.rules.skipUnknownKinds {
+		if kind != nil && isScalarString(kind) {
+			if _, ok := supportedKinds[kind.Value]; !ok {
+				bag.warn(kind, codeUnknownKind, kind.Value)
+				return bag
+			}
+		}
+	}
+
+	ctx := newValidationContext(doc, v.rules)
+	if groupWorkers <= 1 {
+		for _, group := range ruleGroups {
+			group(ctx, bag)
+		}
+		return bag
+	}
+	groups := make([]errBag, len(ruleGroups))
+	parallel(len(ruleGroups), groupWorkers, func(i int) {
+		groups[i].rules = v.rules
+		ruleGroups[i](ctx, &groups[i])
+	})
+	for i := range groups {
+		bag.merge(&groups[i])
+	}
+	return bag
+}
+
+// isNullDocument — пустой документ между разделителями или явный null.
+func isNullDocument(doc *yaml.Node) bool {
+	return doc.Kind == yaml.ScalarNode && doc.Tag == "!!null"
+}
+
+// WorkerPanic — паника проверки в рабочей горутине, переброшенная в
+// горутину вызывающего: recover в ней ловит панику любого правила, а
+// Stack сохраняет место, где она случилась.
+type WorkerPanic struct {
+	Value any
+	Stack []byte
+}
+
+func (p WorkerPanic) String() string { return fmt.Sprint(p.Value) }
+
+// parallel вызывает fn(0..n-1) не более чем в workers горутинах. Паника в
+// рабочей горутине не роняет процесс, а после завершения остальных
+// повторяется в вызывающей как WorkerPanic.
+func parallel(n, workers int, fn func(i int)) {
+	workers = min(n, workers)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	var caught atomic.Pointer[WorkerPanic]
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if v := recover(); v != nil {
+					// вложенный parallel уже обернул панику со своим стеком
+					p, ok := v.(WorkerPanic)
+					if !ok {
+						p = WorkerPanic{Value: v, Stack: debug.Stack()}
+					}
+					caught.CompareAndSwap(nil, &p)
+				}
+			}()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+	if p := caught.Load(); p != nil {
+		panic(*p)
+	}
+}
+
+// ---------- errBag ----------
+
+type errBag struct {
+	rules      *rules
+	list       []Issue
+	images     []Image
+	workloads  []Workload
+	quotas     []Quota
+	objects    []ObjectRef
+	references []Reference
+	secrets    []string
+	facts      []Facts
+	// dropped — последняя находка выключена настройкой rules
+	dropped bool
+}
+
+// add, warn и report привязывают находку к узлу at: его строке и колонке;
+// nil — находка без позиции.
+func (e *errBag) add(at *yaml.Node, code string, args ...any) {
+	e.report(SeverityError, at, code, args...)
+}
+
+func (e *errBag) warn(at *yaml.Node, code string, args ...any) {
+	e.report(SeverityWarning, at, code, args...)
+}
+
+func (e *errBag) report(sev Severity, at *yaml.Node, code string, args ...any) {
+	e.dropped = false
+	if s, ok := e.rules.settings[code]; ok {
+		if s.off {
+			e.dropped = true
+			return
+		}
+		sev = s.sev
+	}
+	is := Issue{Code: code, Severity: sev, Args: args}
+	if at != nil {
+		is.Line, is.Column = at.Line, at.Column
+	}
+	e.list = append(e.list, is)
+}
+
+// help добавляет подсказку к последней находке, если её не выключили
+// в Config.Rules.
+func (e *errBag) help(format string, args ...any) {
+	if len(e.list) > 0 && !e.dropped {
+		e.list[len(e.list)-1].Help = fmt.Sprintf(format, args...)
+	}
+}
+
+// fixValue прикрепляет к последней находке правку, заменяющую скаляр n
+// на value в том же стиле кавычек. Если исходный текст скаляра из узла не
+// восстановить (экранирование, многострочные), правки нет.
+func (e *errBag) fixValue(n *yaml.Node, value string) {
+	if len(e.list) == 0 || e.dropped {
+		return
+	}
+	var old, repl string
+	switch n.Style {
+	case 0:
+		old, repl = n.Value, value
+	case yaml.DoubleQuotedStyle:
+		if strings.ContainsAny(n.Value, "\\\"\n") {
+			return
+		}
+		old, repl = `"`+n.Value+`"`, `"`+value+`"`
+	case yaml.SingleQuotedStyle:
+		if strings.ContainsAny(n.Value, "'\n") {
+			return
+		}
+		old, repl = "'"+n.Value+"'", "'"+value+"'"
+	default:
+		return
+	}
+	e.list[len(e.list)-1].Fix = &Fix{Line: n.Line, Column: n.Column, Old: old, New: repl}
+}
+
+// fixDelete прикрепляет к последней находке правку, удаляющую пару
+// key: value блочного маппинга вместе со всеми строками значения.
+func (e *errBag) fixDelete(key, value *yaml.Node) {
+	if len(e.list) == 0 || e.dropped || key.Style != 0 {
+		return
+	}
+	e.list[len(e.list)-1].Fix = &Fix{
+		Line: key.Line, Column: key.Column, Old: key.Value + ":",
+		Lines: lastLine(value) - key.Line + 1,
+	}
+}
+
+// lastLine — последняя строка, на которой начинается какой-либо узел
+// поддерева.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	for _, c := range n.Content {
+		last = max(last, lastLine(c))
+	}
+	return last
+}
+
+func (e *errBag) merge(o *errBag) {
+	e.list = append(e.list, o.list...)
+	e.images = append(e.images, o.images...)
+	e.workloads = append(e.workloads, o.workloads...)
+	e.quotas = append(e.quotas, o.quotas...)
+	e.objects = append(e.objects, o.objects...)
+	e.references = append(e.references, o.references...)
+	e.secrets = append(e.secrets, o.secrets...)
+	e.facts = append(e.facts, o.facts...)
+}
+
+func (e *errBag) result() *Result {
+	return &Result{
+		Issues: e.list, Images: e.images, Workloads: e.workloads, Quotas: e.quotas,
+		Objects: e.objects, References: e.references, Secrets: e.secrets, Facts: e.facts,
+	}
+}
@@ -0,0 +1,282 @@
+// volumes.go
+package validator
+
+import (
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// volumeSources — источники тома в core/v1 Volume; у тома ровно один.
+// Том без источника API сервер дополняет emptyDir.
+var volumeSources = []string{
+	"awsElasticBlockStore", "azureDisk", "azureFile", "cephfs", "cinder", "configMap", "csi",
+	"downwardAPI", "emptyDir", "ephemeral", "fc", "flexVolume", "flocker", "gcePersistentDisk",
+	"gitRepo", "glusterfs", "hostPath", "image", "iscsi", "nfs", "persistentVolumeClaim",
+	"photonPersistentDisk", "portworxVolume", "projected", "quobyte", "rbd", "scaleIO", "secret",
+	"storageos", "vsphereVolume",
+}
+
+var (
+	emptyDirMedia = []string{"Memory"}
+	// пустой type у hostPath тоже допустим — без проверок
+	hostPathTypes = []string{"BlockDevice", "CharDevice", "Directory", "DirectoryOrCreate", "File", "FileOrCreate", "Socket"}
+)
+
+// validateVolumeMounts — volumeMounts контейнера: имя тома и абсолютный
+// mountPath; один путь в контейнере монтируется один раз. Есть ли том с
+// таким именем, проверяет validateVolumeRefs.
+func validateVolumeMounts(n *yaml.Node, bag *errBag) {
+	if n.Kind != yaml.SequenceNode {
+		bag.add(n, codeVolumeMountsType)
+		return
+	}
+	paths := map[string]bool{}
+	for _, vm := range n.Content {
+		m, node := getMap(vm)
+		if m == nil {
+			bag.add(node, codeVolumeMountType)
+			continue
+		}
+		name, ok := m.get("name")
+		switch {
+		case !ok || (isScalarString(name) && name.Value == ""):
+			bag.add(vm, codeVolumeMountName)
+		case !isScalarString(name):
+			bag.add(name, codeVolumeMountFieldType, "name")
+		}
+		mp, ok := m.get("mountPath")
+		switch {
+		case !ok || (isScalarString(mp) && mp.Value == ""):
+			bag.add(vm, codeMountPathRequired)
+		case !isScalarString(mp):
+			bag.add(mp, codeVolumeMountFieldType, "mountPath")
+		case !strings.HasPrefix(mp.Value, "/"):
+			bag.add(mp, codeMountPathAbsolute, mp.Value)
+		default:
+			// /data и /data/ — один и тот же каталог
+			p := strings.TrimRight(mp.Value, "/")
+			if paths[p] {
+				bag.add(mp, codeMountPathDup, mp.Value)
+			}
+			paths[p] = true
+		}
+	}
+}
+
+// validateVolumes — spec.volumes пода: элементы-объекты с уникальными
+// именами и одним источником.
+func validateVolumes(n *yaml.Node, bag *errBag) {
+	if n.Kind != yaml.SequenceNode {
+		bag.add(n, codeVolumesType)
+		return
+	}
+	seen := map[string]bool{}
+	for _, v := range n.Content {
+		m, node := getMap(v)
+		if m == nil {
+			bag.add(node, codeVolumeType)
+			continue
+		}
+		name, ok := m.get("name")
+		switch {
+		case !ok || (isScalarString(name) && name.Value == ""):
+			bag.add(v, codeVolumeNameRequired)
+		case !isScalarString(name):
+			bag.add(name, codeVolumeNameType)
+		case seen[name.Value]:
+			bag.add(name, codeVolumeNameDup, name.Value)
+		default:
+			seen[name.Value] = true
+		}
+		validateVolumeSource(m, v, bag)
+	}
+}
+
+// validateVolumeSource проверяет источник тома; поля источников, которые
+// здесь не разобраны, не проверяются.
+func validateVolumeSource(m fields, v *yaml.Node, bag *errBag) {
+	// источники в порядке манифеста: находка — на втором из них
+	var found []string
+	for i := 0; i+1 < len(v.Content); i += 2 {
+		if k := v.Content[i].Value; contains(volumeSources, k) {
+			found = append(found, k)
+		}
+	}
+	if len(found) > 1 {
+		second, _ := m.get(found[1])
+		bag.add(second, codeVolumeSources, strings.Join(found, ", "))
+		return
+	}
+	if len(found) == 0 {
+		return
+	}
+	source := found[0]
+	n, _ := m.get(source)
+	// emptyDir: без значения — то же, что emptyDir: {}
+	if n.Tag == "!!null" && source == "emptyDir" {
+		return
+	}
+	sm, node := getMap(n)
+	if sm == nil {
+		bag.add(node, codeVolumeSourceType, source)
+		return
+	}
+	switch source {
+	case "emptyDir":
+		if med, ok := sm.get("medium"); ok && !(isScalarString(med) && (med.Value == "" || contains(emptyDirMedia, med.Value))) {
+			unsupported(bag, med, codeVolumeMediumUnsupported, codeVolumeMediumCase, emptyDirMedia)
+		}
+		if sl, ok := sm.get("sizeLimit"); ok {
+			if _, err := ParseQuantity(sl.Value); err != nil || sl.Kind != yaml.ScalarNode {
+				bag.add(sl, codeVolumeSizeLimit, sl.Value)
+			}
+		}
+	case "configMap", "secret":
+		field := "name"
+		if source == "secret" {
+			field = "secretName"
+		}
+		volumeString(sm, n, source, field, true, bag)
+		volumeInt(sm, source, "defaultMode", bag)
+		volumeBool(sm, source, "optional", bag)
+		if items, ok := sm.get("items"); ok {
+			validateVolumeItems(items, source, bag)
+		}
+	case "hostPath":
+		if p := volumeString(sm, n, source, "path", true, bag); p != nil && !strings.HasPrefix(p.Value, "/") {
+			bag.add(p, codeHostPathRelative, p.Value)
+		}
+		if t, ok := sm.get("type"); ok && !(isScalarString(t) && (t.Value == "" || contains(hostPathTypes, t.Value))) {
+			unsupported(bag, t, codeHostPathTypeUnsupported, codeHostPathTypeCase, hostPathTypes)
+		}
+	case "persistentVolumeClaim":
+		volumeString(sm, n, source, "claimName", true, bag)
+		volumeBool(sm, source, "readOnly", bag)
+	}
+}
+
+// validateVolumeItems — items у configMap и secret: какой ключ в какой
+// файл тома положить. Путь относительный и не выходит за том.
+func validateVolumeItems(n *yaml.Node, source string, bag *errBag) {
+	if n.Kind != yaml.SequenceNode {
+		bag.add(n, codeVolumeItemsType, source)
+		return
+	}
+	for _, it := range n.Content {
+		m, node := getMap(it)
+		if m == nil {
+			bag.add(node, codeVolumeItemType, source)
+			continue
+		}
+		volumeString(m, it, source+".items", "key", true, bag)
+		if p := volumeString(m, it, source+".items", "path", true, bag); p != nil {
+			if strings.HasPrefix(p.Value, "/") || contains(strings.Split(p.Value, "/"), "..") {
+				bag.add(p, codeVolumeItemPath, source, p.Value)
+			}
+		}
+		volumeInt(m, source+".items", "mode", bag)
+	}
+}
+
+// volumeString проверяет строковое поле источника и возвращает его, если
+// оно непустая строка.
+func volumeString(m fields, src *yaml.Node, source, field string, required bool, bag *errBag) *yaml.Node {
+	v, ok := m.get(field)
+	switch {
+	case !ok:
+		if required {
+			bag.add(src, codeVolumeSourceField, source+"."+field)
+		}
+		return nil
+	case !isScalarString(v):
+		bag.add(v, codeVolumeSourceFieldType, source+"."+field, "string")
+		return nil
+	case v.Value == "":
+		if required {
+			bag.add(v, codeVolumeSourceField, source+"."+field)
+		}
+		return nil
+	}
+	return v
+}
+
+func volumeInt(m fields, source, field string, bag *errBag) {
+	if v, ok := m.get(field); ok && !isScalarInt(v) {
+		bag.add(v, codeVolumeSourceFieldType, source+"."+field, "int")
+	}
+}
+
+func volumeBool(m fields, source, field string, bag *errBag) {
+	if v, ok := m.get(field); ok && (v.Kind != yaml.ScalarNode || v.Tag != "!!bool") {
+		bag.add(v, codeVolumeSourceFieldType, source+"."+field, "boolean")
+	}
+}
+
+// validateVolumeRefs сверяет volumeMounts контейнеров с томами пода: том,
+// которого нет в spec.volumes, — под не стартует; том, который никто не
+// монтирует, — скорее всего, забытая правка. У StatefulSet томами служат
+// и volumeClaimTemplates.
+func validateVolumeRefs(ctx *ValidationContext, bag *errBag) {
+	if ctx.PodSpec == nil {
+		return
+	}
+	// volumes есть, но не список — об этом уже сказано, сверять не с чем
+	if vols, ok := child(ctx.PodSpec, "volumes"); ok && vols.Kind != yaml.SequenceNode {
+		return
+	}
+	defined := map[string]bool{}
+	for name := range ctx.Volumes {
+		defined[name] = true
+	}
+	if ctx.Kind == "StatefulSet" {
+		spec, _ := child(ctx.Doc, "spec")
+		var vct *yaml.Node
+		if spec != nil {
+			vct, _ = child(spec, "volumeClaimTemplates")
+		}
+		if vct != nil && vct.Kind == yaml.SequenceNode {
+			for _, t := range vct.Content {
+				meta, _ := child(t, "metadata")
+				if meta == nil {
+					continue
+				}
+				if name, _ := child(meta, "name"); name != nil && isScalarString(name) {
+					defined[name.Value] = true
+				}
+			}
+		}
+	}
+
+	used := map[string]bool{}
+	for _, c := range ctx.Containers {
+		for _, vm := range c.Mounts {
+			name, _ := child(vm, "name")
+			if name == nil || !isScalarString(name) || name.Value == "" {
+				continue
+			}
+			used[name.Value] = true
+			if !defined[name.Value] {
+				bag.add(name, codeVolumeUndefined, name.Value)
+			}
+		}
+		// блочные тома подключаются через volumeDevices
+		if devs, _ := child(c.Node, "volumeDevices"); devs != nil && devs.Kind == yaml.SequenceNode {
+			for _, d := range devs.Content {
+				if name, _ := child(d, "name"); name != nil && isScalarString(name) {
+					used[name.Value] = true
+				}
+			}
+		}
+	}
+
+	vols, _ := child(ctx.PodSpec, "volumes")
+	if vols == nil {
+		return
+	}
+	for _, v := range vols.Content {
+		if name, _ := child(v, "name"); name != nil && isScalarString(name) && name.Value != "" && !used[name.Value] {
+			bag.warn(name, codeVolumeUnused, name.Value)
+		}
+	}
+}
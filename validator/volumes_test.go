@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+)
+
+// mounts — под с volumeMounts контейнера и volumes пода (flow-списки).
+func mounts(volumeMounts, volumes string) string {
+	return pod("      volumeMounts: "+volumeMounts+"\n", "  volumes: "+volumes+"\n")
+}
+
+const dataVolume = "[{name: data, emptyDir: {}}]"
+
+func TestVolumeMounts(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", mounts("[{name: data, mountPath: /data}, {name: data, mountPath: /cache, subPath: cache}]", dataVolume), ""},
+		{"mounts not array", mounts("{name: data, mountPath: /data}", dataVolume), codeVolumeMountsType},
+		{"mount not object", mounts("[data]", dataVolume), codeVolumeMountType},
+		{"name missing", mounts("[{mountPath: /data}]", dataVolume), codeVolumeMountName},
+		{"name not string", mounts("[{name: [data], mountPath: /data}]", dataVolume), codeVolumeMountFieldType},
+		{"mountPath missing", mounts("[{name: data}]", dataVolume), codeMountPathRequired},
+		{"mountPath not string", mounts("[{name: data, mountPath: 1}]", dataVolume), codeVolumeMountFieldType},
+		{"mountPath relative", mounts("[{name: data, mountPath: data}]", dataVolume), codeMountPathAbsolute},
+		{"mountPath duplicate", mounts("[{name: data, mountPath: /data}, {name: data, mountPath: /data/}]", dataVolume), codeMountPathDup},
+		{"volume undefined", mounts("[{name: data, mountPath: /data}, {name: logs, mountPath: /logs}]", dataVolume), codeVolumeUndefined},
+		{"no volumes", pod("      volumeMounts: [{name: data, mountPath: /data}]\n", ""), codeVolumeUndefined},
+		{"volume unused", pod("", "  volumes: "+dataVolume+"\n"), codeVolumeUnused},
+		{"volume device", pod("      volumeDevices: [{name: data, devicePath: /dev/xvda}]\n", "  volumes: [{name: data, persistentVolumeClaim: {claimName: raw}}]\n"), ""},
+		{"claim template", strings.Replace(claim(claimSpec), "          resources:", "          volumeMounts: [{name: data, mountPath: /data}]\n          resources:", 1), ""},
+		{"volumes not array", mounts("[]", "{data: {}}"), codeVolumesType},
+		{"volume not object", mounts("[]", "[data]"), codeVolumeType},
+		{"volume name missing", mounts("[]", "[{emptyDir: {}}]"), codeVolumeNameRequired},
+		{"volume name not string", mounts("[]", "[{name: {a: b}, emptyDir: {}}]"), codeVolumeNameType},
+		{"volume name duplicate", mounts("[{name: data, mountPath: /data}]", "[{name: data, emptyDir: {}}, {name: data, emptyDir: {}}]"), codeVolumeNameDup},
+	})
+}
+
+// volume — под с томом data источника source, смонтированным в /data.
+func volume(source string) string {
+	return mounts("[{name: data, mountPath: /data}]", "[{name: data, "+source+"}]")
+}
+
+func TestVolumeSources(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"no source", mounts("[{name: data, mountPath: /data}]", "[{name: data}]"), ""},
+		{"emptyDir null", volume("emptyDir:"), ""},
+		{"emptyDir", volume("emptyDir: {medium: Memory, sizeLimit: 64Mi}"), ""},
+		{"configMap", volume("configMap: {name: app, defaultMode: 0o644, optional: true, items: [{key: app.yaml, path: conf/app.yaml, mode: 0o400}]}"), ""},
+		{"secret", volume("secret: {secretName: tls}"), ""},
+		{"hostPath", volume("hostPath: {path: /var/log, type: Directory}"), ""},
+		{"hostPath empty type", volume("hostPath: {path: /var/log, type: \"\"}"), ""},
+		{"persistentVolumeClaim", volume("persistentVolumeClaim: {claimName: data, readOnly: true}"), ""},
+		{"other source", volume("nfs: {server: nfs.local, path: /exports}"), ""},
+		{"two sources", volume("emptyDir: {}, hostPath: {path: /data}"), codeVolumeSources},
+		{"source not object", volume("configMap: app"), codeVolumeSourceType},
+		{"name missing", volume("configMap: {optional: true}"), codeVolumeSourceField},
+		{"secretName empty", volume("secret: {secretName: \"\"}"), codeVolumeSourceField},
+		{"claimName missing", volume("persistentVolumeClaim: {readOnly: true}"), codeVolumeSourceField},
+		{"name not string", volume("configMap: {name: [app]}"), codeVolumeSourceFieldType},
+		{"defaultMode not int", volume("secret: {secretName: tls, defaultMode: rw}"), codeVolumeSourceFieldType},
+		{"optional not bool", volume("configMap: {name: app, optional: \"no\"}"), codeVolumeSourceFieldType},
+		{"readOnly not bool", volume("persistentVolumeClaim: {claimName: data, readOnly: 1}"), codeVolumeSourceFieldType},
+		{"medium unsupported", volume("emptyDir: {medium: Disk}"), codeVolumeMediumUnsupported},
+		{"medium case", volume("emptyDir: {medium: memory}"), codeVolumeMediumCase},
+		{"sizeLimit", volume("emptyDir: {sizeLimit: 1 GB}"), codeVolumeSizeLimit},
+		{"hostPath relative", volume("hostPath: {path: var/log}"), codeHostPathRelative},
+		{"hostPath path missing", volume("hostPath: {type: Directory}"), codeVolumeSourceField},
+		{"hostPath type unsupported", volume("hostPath: {path: /var/log, type: Dir}"), codeHostPathTypeUnsupported},
+		{"hostPath type case", volume("hostPath: {path: /var/log, type: directory}"), codeHostPathTypeCase},
+		{"items not array", volume("configMap: {name: app, items: {key: a, path: a}}"), codeVolumeItemsType},
+		{"item not object", volume("secret: {secretName: tls, items: [tls.crt]}"), codeVolumeItemType},
+		{"item key missing", volume("configMap: {name: app, items: [{path: a}]}"), codeVolumeSourceField},
+		{"item path absolute", volume("configMap: {name: app, items: [{key: a, path: /etc/a}]}"), codeVolumeItemPath},
+		{"item path escapes", volume("secret: {secretName: tls, items: [{key: a, path: ../a}]}"), codeVolumeItemPath},
+		{"item mode not int", volume("configMap: {name: app, items: [{key: a, path: a, mode: rw}]}"), codeVolumeSourceFieldType},
+	})
+}
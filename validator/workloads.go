@@ -0,0 +1,355 @@
+// workloads.go
+package validator
+
+import (
+	"regexp"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// ---------- pod template ----------
+
+// templateKinds — контроллеры, у которых под задан шаблоном
+// spec.template (у CronJob — в jobTemplate).
+var templateKinds = map[string]bool{"Deployment": true, "StatefulSet": true, "DaemonSet": true, "Job": true, "CronJob": true}
+
+// podlessKinds — известные kind'ы без пода: правила про контейнеры и
+// планирование их не касаются.
+var podlessKinds = map[string]bool{"Service": true, "Ingress": true, "PersistentVolumeClaim": true, "HorizontalPodAutoscaler": true, "NetworkPolicy": true, "PodDisruptionBudget": true, "ConfigMap": true, "Secret": true}
+
+func kindOf(doc *yaml.Node) string {
+	if kind, _ := child(doc, "kind"); kind != nil && isScalarString(kind) {
+		return kind.Value
+	}
+	return ""
+}
+
+// controllerSpecOf — spec, в котором лежит шаблон пода: у CronJob это
+// spec.jobTemplate.spec, у остальных контроллеров — spec документа.
+func controllerSpecOf(doc *yaml.Node) *yaml.Node {
+	spec, _ := child(doc, "spec")
+	if spec == nil || kindOf(doc) != "CronJob" {
+		return spec
+	}
+	jt, _ := child(spec, "jobTemplate")
+	if jt == nil {
+		return nil
+	}
+	spec, _ = child(jt, "spec")
+	return spec
+}
+
+// podTemplateOf — шаблон пода у контроллеров (spec.template); nil для
+// Pod и kind'ов без шаблона.
+func podTemplateOf(doc *yaml.Node) *yaml.Node {
+	if !templateKinds[kindOf(doc)] {
+		return nil
+	}
+	spec := controllerSpecOf(doc)
+	if spec == nil {
+		return nil
+	}
+	tmpl, _ := child(spec, "template")
+	return tmpl
+}
+
+// podSpecOf — spec пода: у Pod это spec, у контроллеров —
+// spec.template.spec, у Service и других kind'ов без пода — nil.
+// Правила про контейнеры, планирование и requests смотрят сюда, а не в
+// spec документа.
+func podSpecOf(doc *yaml.Node) *yaml.Node {
+	kind := kindOf(doc)
+	switch {
+	case podlessKinds[kind]:
+		return nil
+	case templateKinds[kind]:
+		tmpl := podTemplateOf(doc)
+		if tmpl == nil {
+			return nil
+		}
+		spec, _ := child(tmpl, "spec")
+		return spec
+	}
+	spec, _ := child(doc, "spec")
+	return spec
+}
+
+// replicasOf — spec.replicas контроллера, 1 по умолчанию и для Pod.
+// У DaemonSet подов столько, сколько подходящих узлов, — считаем один;
+// у Job и CronJob одновременно работают parallelism подов.
+func replicasOf(doc *yaml.Node) int {
+	kind := kindOf(doc)
+	if podTemplateOf(doc) == nil || kind == "DaemonSet" {
+		return 1
+	}
+	field := "replicas"
+	if kind == "Job" || kind == "CronJob" {
+		field = "parallelism"
+	}
+	if r, ok := child(controllerSpecOf(doc), field); ok && isScalarInt(r) {
+		if n, err := toInt(r.Value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return 1
+}
+
+// validatePodTemplate проверяет spec.template и возвращает его метки для
+// сверки с селектором; nil, если шаблона нет или он не объект.
+func validatePodTemplate(m fields, bag *errBag) map[string]string {
+	tmpl, ok := m.get("template")
+	if !ok {
+		bag.add(nil, codeTemplateRequired)
+		return nil
+	}
+	tm, node := getMap(tmpl)
+	if tm == nil {
+		bag.add(node, codeTemplateType)
+		return nil
+	}
+	// у шаблона нет name: metadata — только метки и аннотации
+	if meta, ok := tm.get("metadata"); ok {
+		if meta.Kind != yaml.MappingNode {
+			bag.add(meta, codeMetadataType)
+		} else if labels, ok := child(meta, "labels"); ok && !isStringMap(labels) {
+			bag.add(labels, codeLabelsType)
+		}
+	}
+	spec, ok := tm.get("spec")
+	if !ok {
+		bag.add(nil, codeSpecRequired)
+	} else {
+		validatePodSpec(spec, bag)
+	}
+	return labelsOf(tmpl)
+}
+
+func isStringMap(n *yaml.Node) bool {
+	if n.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if !isScalarString(n.Content[i]) || !isScalarString(n.Content[i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ---------- replicas and selector ----------
+
+func validateReplicas(n *yaml.Node, bag *errBag) {
+	if !isScalarInt(n) {
+		bag.add(n, codeReplicasType)
+		return
+	}
+	if v, err := toInt(n.Value); err != nil || v < 0 {
+		bag.add(n, codeReplicasNegative)
+	}
+}
+
+// selectorOperators — операторы matchExpressions в metav1.LabelSelector.
+var selectorOperators = []string{"DoesNotExist", "Exists", "In", "NotIn"}
+
+// validateLabelSelector проверяет metav1.LabelSelector и переводит его в
+// labelSelector для сверки с метками шаблона; nil, если селектор с
+// ошибками — о несовпадении тогда не сообщаем.
+func validateLabelSelector(n *yaml.Node, bag *errBag) labelSelector {
+	sel, valid := checkLabelSelector(n, bag)
+	// пустой селектор в apps/v1 запрещён: он выбрал бы все поды неймспейса
+	if len(sel) == 0 && valid {
+		bag.add(n, codeSelectorEmpty)
+		return nil
+	}
+	if !valid {
+		return nil
+	}
+	return sel
+}
+
+// checkLabelSelector — проверка полей селектора без требования
+// непустоты: там, где {} означает «все поды», как в NetworkPolicy.
+func checkLabelSelector(n *yaml.Node, bag *errBag) (labelSelector, bool) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeSelectorType)
+		return nil, false
+	}
+	var sel labelSelector
+	valid := true
+	ml, hasLabels := m.get("matchLabels")
+	if hasLabels {
+		if !isStringMap(ml) {
+			bag.add(ml, codeMatchLabelsType)
+			valid = false
+		} else {
+			for i := 0; i+1 < len(ml.Content); i += 2 {
+				sel = append(sel, labelRequirement{key: ml.Content[i].Value, op: "=", values: []string{ml.Content[i+1].Value}})
+			}
+		}
+	}
+	exprs, hasExprs := m.get("matchExpressions")
+	if hasExprs {
+		if exprs.Kind != yaml.SequenceNode {
+			bag.add(exprs, codeMatchExpressionsType)
+			valid = false
+		} else {
+			for _, e := range exprs.Content {
+				req, ok := validateSelectorRequirement(e, bag)
+				valid = valid && ok
+				sel = append(sel, req)
+			}
+		}
+	}
+	return sel, valid
+}
+
+func validateSelectorRequirement(n *yaml.Node, bag *errBag) (labelRequirement, bool) {
+	var req labelRequirement
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeMatchExpressionType)
+		return req, false
+	}
+	key, ok := m.get("key")
+	if !ok || !isScalarString(key) || key.Value == "" {
+		bag.add(key, codeMatchExpressionKey)
+		return req, false
+	}
+	req.key = key.Value
+	op, ok := m.get("operator")
+	if !ok || !isScalarString(op) {
+		bag.add(op, codeMatchExpressionOpRequired)
+		return req, false
+	}
+	if !contains(selectorOperators, op.Value) {
+		unsupported(bag, op, codeMatchExpressionOp, "", selectorOperators)
+		return req, false
+	}
+	values, hasValues := m.get("values")
+	if hasValues {
+		if values.Kind != yaml.SequenceNode {
+			bag.add(values, codeMatchExpressionValues, op.Value)
+			return req, false
+		}
+		for _, v := range values.Content {
+			req.values = append(req.values, v.Value)
+		}
+	}
+	switch op.Value {
+	case "In", "NotIn":
+		if len(req.values) == 0 {
+			bag.add(op, codeMatchExpressionValues, op.Value)
+			return req, false
+		}
+		req.op = strings.ToLower(op.Value)
+	case "Exists", "DoesNotExist":
+		if len(req.values) > 0 {
+			bag.add(values, codeMatchExpressionValues, op.Value)
+			return req, false
+		}
+		req.op = map[string]string{"Exists": "exists", "DoesNotExist": "!exists"}[op.Value]
+	}
+	return req, true
+}
+
+// ---------- Deployment ----------
+
+var deploymentStrategies = []string{"Recreate", "RollingUpdate"}
+
+// reIntOrPercent — maxSurge и maxUnavailable: число или процент.
+var reIntOrPercent = regexp.MustCompile(`^[0-9]+%$`)
+
+// validateControllerSpec — общее у контроллеров apps/v1: replicas,
+// selector и template, причём селектор должен выбирать поды шаблона.
+func validateControllerSpec(m fields, bag *errBag) {
+	// replicas (optional)
+	if r, ok := m.get("replicas"); ok {
+		validateReplicas(r, bag)
+	}
+
+	// selector (required в apps/v1) и template
+	sel, hasSelector := m.get("selector")
+	var selector labelSelector
+	if !hasSelector {
+		bag.add(nil, codeSelectorRequired)
+	} else {
+		selector = validateLabelSelector(sel, bag)
+	}
+	labels := validatePodTemplate(m, bag)
+	if selector != nil && labels != nil && !selector.matches(labels) {
+		bag.add(sel, codeSelectorMismatch)
+	}
+}
+
+func validateDeploymentSpec(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeWorkloadSpecType)
+		return
+	}
+	validateControllerSpec(m, bag)
+
+	// strategy (optional)
+	if st, ok := m.get("strategy"); ok {
+		validateDeploymentStrategy(st, bag)
+	}
+}
+
+func validateDeploymentStrategy(n *yaml.Node, bag *errBag) {
+	m, node := getMap(n)
+	if m == nil {
+		bag.add(node, codeStrategyType)
+		return
+	}
+	typ := "RollingUpdate"
+	if t, ok := m.get("type"); ok {
+		if !isScalarString(t) || !contains(deploymentStrategies, t.Value) {
+			unsupported(bag, t, codeStrategyUnsupported, codeStrategyCase, deploymentStrategies)
+			return
+		}
+		typ = t.Value
+	}
+	ru, ok := m.get("rollingUpdate")
+	if !ok {
+		return
+	}
+	if typ == "Recreate" {
+		bag.add(ru, codeRollingUpdateRecreate)
+		return
+	}
+	rm, node := getMap(ru)
+	if rm == nil {
+		bag.add(node, codeStrategyType)
+		return
+	}
+	zero := 0
+	for _, field := range []string{"maxSurge", "maxUnavailable"} {
+		if v, ok := rm.get(field); ok && zeroIntOrPercent(v, field, bag) {
+			zero++
+		}
+	}
+	// оба нуля — выкат не может ни добавить под, ни убрать старый
+	if zero == 2 {
+		bag.add(ru, codeRollingUpdateZero)
+	}
+}
+
+// zeroIntOrPercent проверяет maxSurge или maxUnavailable и сообщает,
+// равно ли значение нулю.
+func zeroIntOrPercent(v *yaml.Node, field string, bag *errBag) bool {
+	switch {
+	case isScalarInt(v):
+		x, err := toInt(v.Value)
+		if err != nil || x < 0 {
+			bag.add(v, codeIntOrPercent, field)
+			return false
+		}
+		return x == 0
+	case isScalarString(v) && reIntOrPercent.MatchString(v.Value):
+		return strings.TrimLeft(strings.TrimSuffix(v.Value, "%"), "0") == ""
+	}
+	bag.add(v, codeIntOrPercent, field)
+	return false
+}
@@ -0,0 +1,69 @@
+package validator
+
+import "testing"
+
+// Части корректного Deployment: селектор и шаблон пода с меткой app: web.
+const (
+	webSelector = "  selector: {matchLabels: {app: web}}\n"
+	webTemplate = `  template:
+    metadata: {labels: {app: web}}
+    spec:
+      containers:
+        - name: web
+          image: registry.bigbrother.io/web:1.0
+          resources: {requests: {cpu: 1, memory: 64Mi}, limits: {cpu: 1, memory: 64Mi}}
+`
+)
+
+// deployment — Deployment со строками spec (отступ 2).
+func deployment(spec string) string {
+	return "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n" + spec
+}
+
+// expressions — селектор с matchExpressions exprs и шаблон.
+func expressions(exprs string) string {
+	return deployment("  selector:\n    matchExpressions: " + exprs + "\n" + webTemplate)
+}
+
+func TestWorkloadSpec(t *testing.T) {
+	checkRules(t, Config{}, []ruleCase{
+		{"valid", deployment("  replicas: 3\n" + webSelector + webTemplate), ""},
+		{"spec not object", "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec: [web]\n", codeWorkloadSpecType},
+		{"replicas not int", deployment("  replicas: \"3\"\n" + webSelector + webTemplate), codeReplicasType},
+		{"replicas negative", deployment("  replicas: -1\n" + webSelector + webTemplate), codeReplicasNegative},
+		{"selector missing", deployment(webTemplate), codeSelectorRequired},
+		{"selector not object", deployment("  selector: app=web\n" + webTemplate), codeSelectorType},
+		{"selector empty", deployment("  selector: {}\n" + webTemplate), codeSelectorEmpty},
+		{"matchLabels not strings", deployment("  selector: {matchLabels: [app]}\n" + webTemplate), codeMatchLabelsType},
+		{"matchExpressions not array", expressions("{key: app}"), codeMatchExpressionsType},
+		{"expression not object", expressions("[app]"), codeMatchExpressionType},
+		{"expression key", expressions("[{operator: Exists}]"), codeMatchExpressionKey},
+		{"expression operator missing", expressions("[{key: app}]"), codeMatchExpressionOpRequired},
+		{"expression operator", expressions("[{key: app, operator: Equals, values: [web]}]"), codeMatchExpressionOp},
+		{"In without values", expressions("[{key: app, operator: In}]"), codeMatchExpressionValues},
+		{"Exists with values", expressions("[{key: app, operator: Exists, values: [web]}]"), codeMatchExpressionValues},
+		{"expressions match", expressions("[{key: app, operator: In, values: [web, api]}]"), ""},
+		{"template missing", deployment(webSelector), codeTemplateRequired},
+		{"template not object", deployment(webSelector + "  template: web\n"), codeTemplateType},
+		{"selector mismatch", deployment("  selector: {matchLabels: {app: api}}\n" + webTemplate), codeSelectorMismatch},
+		{"expressions mismatch", expressions("[{key: app, operator: NotIn, values: [web]}]"), codeSelectorMismatch},
+	})
+}
+
+func TestDeploymentStrategy(t *testing.T) {
+	strategy := func(s string) string { return deployment(webSelector + webTemplate + "  strategy: " + s + "\n") }
+	checkRules(t, Config{}, []ruleCase{
+		{"recreate", strategy("{type: Recreate}"), ""},
+		{"rolling update", strategy("{type: RollingUpdate, rollingUpdate: {maxSurge: 25%, maxUnavailable: 0}}"), ""},
+		{"default type", strategy("{rollingUpdate: {maxSurge: 1}}"), ""},
+		{"not object", strategy("Recreate"), codeStrategyType},
+		{"unsupported", strategy("{type: BlueGreen}"), codeStrategyUnsupported},
+		{"case", strategy("{type: recreate}"), codeStrategyCase},
+		{"rollingUpdate with Recreate", strategy("{type: Recreate, rollingUpdate: {maxSurge: 1}}"), codeRollingUpdateRecreate},
+		{"rollingUpdate not object", strategy("{rollingUpdate: fast}"), codeStrategyType},
+		{"negative", strategy("{rollingUpdate: {maxSurge: -1}}"), codeIntOrPercent},
+		{"not percent", strategy("{rollingUpdate: {maxUnavailable: half}}"), codeIntOrPercent},
+		{"float", strategy("{rollingUpdate: {maxUnavailable: 0.5}}"), codeIntOrPercent},
+		{"both zero", strategy("{rollingUpdate: {maxSurge: 0, maxUnavailable: 0%}}"), codeRollingUpdateZero},
+	})
+}
@@ -0,0 +1,85 @@
+// yaml11.go
+package validator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// Kubernetes читает манифесты через sigs.k8s.io/yaml, то есть по правилам
+// YAML 1.1, а редакторы, линтеры и yaml.v3 — по YAML 1.2. Несколько видов
+// скаляров без кавычек эти версии понимают по-разному, и манифест,
+// который выглядит правильно, после apply содержит другое значение.
+
+// yaml11Bools — булевы YAML 1.1, которые в YAML 1.2 — строки.
+var yaml11Bools = map[string]bool{
+	"y": true, "Y": true, "yes": true, "Yes": true, "YES": true, "on": true, "On": true, "ON": true,
+	"n": false, "N": false, "no": false, "No": false, "NO": false, "off": false, "Off": false, "OFF": false,
+}
+
+// reSexagesimal — числа по основанию 60 из YAML 1.1: 1:30 — это 90.
+var reSexagesimal = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$`)
+
+// reOctal11 — восьмеричные YAML 1.1 с ведущим нулём: в YAML 1.2 0755 —
+// десятичное 755, восьмеричные пишутся 0o755.
+var reOctal11 = regexp.MustCompile(`^[-+]?0[0-7_]+$`)
+
+// reNumber11 — двоичные и числа с подчёркиваниями: в YAML 1.2 это строки.
+var reNumber11 = regexp.MustCompile(`^[-+]?(0b[01_]+|[0-9][0-9_]*_[0-9_]*(\.[0-9_]*)?)$`)
+
+// validateYAMLVersions предупреждает о скалярах без кавычек, которые
+// YAML 1.1 и YAML 1.2 читают по-разному. Подсказка объясняет оба
+// прочтения; правки нет: что имел в виду автор, знает только он.
+func validateYAMLVersions(ctx *ValidationContext, bag *errBag) {
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Kind == yaml.ScalarNode && n.Style == 0 {
+			checkYAML11Scalar(n, bag)
+		}
+		for _, c := range n.Content {
+			walk(c)
+		}
+	}
+	walk(ctx.Doc)
+}
+
+func checkYAML11Scalar(n *yaml.Node, bag *errBag) {
+	v := n.Value
+	switch {
+	case hasKey(yaml11Bools, v):
+		bag.warn(n, codeYAML11Bool, v, strconv.FormatBool(yaml11Bools[v]))
+		bag.help("Kubernetes (YAML 1.1) reads %s as boolean %t, YAML 1.2 tools as the string \"%s\"; write %t for a boolean or quote it for a string", v, yaml11Bools[v], v, yaml11Bools[v])
+	case reSexagesimal.MatchString(v):
+		bag.warn(n, codeYAML11Sexagesimal, v)
+		if x, ok := sexagesimal(v); ok {
+			bag.help("Kubernetes (YAML 1.1) reads %s as the base-60 number %s, YAML 1.2 tools as a string; quote it", v, x)
+		}
+	case reOctal11.MatchString(v) && strings.Trim(v, "+-0_") != "":
+		bag.warn(n, codeYAML11Number, v)
+		bag.help("Kubernetes (YAML 1.1) reads %s as an octal number, YAML 1.2 tools as decimal; write 0o%s or quote it", v, strings.TrimLeft(v, "+-0"))
+	case reNumber11.MatchString(v):
+		bag.warn(n, codeYAML11Number, v)
+		bag.help("Kubernetes (YAML 1.1) reads %s as a number, YAML 1.2 tools as a string; quote it or drop the underscores and 0b prefix", v)
+	}
+}
+
+// sexagesimal переводит 1:30 в 90 (и 1:30.5 в 90.5).
+func sexagesimal(v string) (string, bool) {
+	neg := strings.HasPrefix(v, "-")
+	v = strings.TrimLeft(strings.ReplaceAll(v, "_", ""), "+-")
+	var total float64
+	for _, part := range strings.Split(v, ":") {
+		x, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return "", false
+		}
+		total = total*60 + x
+	}
+	if neg {
+		total = -total
+	}
+	return strconv.FormatFloat(total, 'f', -1, 64), true
+}
@@ -0,0 +1,39 @@
+package validator
+
+import "testing"
+
+func TestYAMLVersions(t *testing.T) {
+	value := func(v string) string { return configMap("data:\n  key: " + v + "\n") }
+	checkRules(t, Config{}, []ruleCase{
+		{"plain string", value("hello"), ""},
+		{"quoted yes", value("\"yes\""), ""},
+		{"yes", value("yes"), codeYAML11Bool},
+		{"Off", value("Off"), codeYAML11Bool},
+		{"time", value("1:30"), codeYAML11Sexagesimal},
+		{"quoted time", value("'1:30'"), ""},
+		{"octal", value("0755"), codeYAML11Number},
+		{"binary", value("0b101"), codeYAML11Number},
+		{"underscores", value("1_000"), codeYAML11Number},
+	})
+}
+
+func TestYAMLVersionsHelp(t *testing.T) {
+	v, err := New(Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct{ value, code, help string }{
+		{"on", codeYAML11Bool, `Kubernetes (YAML 1.1) reads on as boolean true, YAML 1.2 tools as the string "on"; write true for a boolean or quote it for a string`},
+		{"-1:30.5", codeYAML11Sexagesimal, "Kubernetes (YAML 1.1) reads -1:30.5 as the base-60 number -90.5, YAML 1.2 tools as a string; quote it"},
+		{"0644", codeYAML11Number, "Kubernetes (YAML 1.1) reads 0644 as an octal number, YAML 1.2 tools as decimal; write 0o644 or quote it"},
+	}
+	for _, tt := range tests {
+		res, err := v.Validate([]byte(configMap("data:\n  key: " + tt.value + "\n")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if is := findIssue(res, tt.code); is == nil || is.Help != tt.help {
+			t.Errorf("%s: issue %+v, want %s with help %q", tt.value, is, tt.code, tt.help)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// vulns.go
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+// ---------- image scan results (--image-scan-results) ----------
+
+// Результаты внешнего сканера по одному образу на файл. Понимаем отчёт
+// trivy (`trivy image -f json`) и простой формат:
+//
+//	{"image": "registry.bigbrother.io/app:1.2", "vulnerabilities": [{"id": "CVE-…", "severity": "CRITICAL"}]}
+type scanReport struct {
+	Image           string      `json:"image"`
+	Vulnerabilities []scanVuln  `json:"vulnerabilities"`
+	ArtifactName    string      `json:"ArtifactName"`
+	Results         []trivyPart `json:"Results"`
+}
+
+type scanVuln struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+}
+
+type trivyPart struct {
+	Vulnerabilities []struct {
+		VulnerabilityID string `json:"VulnerabilityID"`
+		Severity        string `json:"Severity"`
+	} `json:"Vulnerabilities"`
+}
+
+// imageVulns — критические CVE по нормализованной ссылке на образ.
+type imageVulns map[imageRef][]string
+
+func loadScanReports(paths []string) (imageVulns, error) {
+	out := imageVulns{}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		var rep scanReport
+		if err := json.Unmarshal(data, &rep); err != nil {
+			return nil, fmt.Errorf("%s: %v", p, err)
+		}
+		image := rep.Image
+		vulns := rep.Vulnerabilities
+		if image == "" {
+			image = rep.ArtifactName
+			for _, part := range rep.Results {
+				for _, v := range part.Vulnerabilities {
+					vulns = append(vulns, scanVuln{ID: v.VulnerabilityID, Severity: v.Severity})
+				}
+			}
+		}
+		if image == "" {
+			return nil, fmt.Errorf("%s: report does not name the scanned image", p)
+		}
+		ref := parseImageRef(image)
+		for _, v := range vulns {
+			if strings.EqualFold(v.Severity, "critical") {
+				out[ref] = append(out[ref], v.ID)
+			}
+		}
+		if _, ok := out[ref]; !ok {
+			out[ref] = nil // образ просканирован и чист
+		}
+	}
+	return out, nil
+}
+
+// checkImageVulns сопоставляет образы из манифеста с отчётами сканера.
+func checkImageVulns(res *validator.Result, vulns imageVulns) {
+	for _, im := range res.Images {
+		ids := vulns[parseImageRef(im.Ref)]
+		if len(ids) == 0 {
+			continue
+		}
+		ids = append([]string(nil), ids...)
+		sort.Strings(ids)
+		addIssue(res, validator.SeverityError, im.Line, validator.CodeImageVulnerable, im.Ref, len(ids), strings.Join(ids, ", "))
+	}
+}
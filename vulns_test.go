@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forceofprophet/yandexgolang2/validator"
+)
+
+func TestImageVulns(t *testing.T) {
+	dir := t.TempDir()
+	reports := map[string]string{
+		"simple.json": `{"image": "registry.bigbrother.io/app:1.2", "vulnerabilities": [
+			{"id": "CVE-2024-2", "severity": "CRITICAL"}, {"id": "CVE-2024-1", "severity": "critical"},
+			{"id": "CVE-2024-3", "severity": "LOW"}]}`,
+		"trivy.json": `{"ArtifactName": "nginx:1.25", "Results": [{"Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2023-9", "Severity": "CRITICAL"}]}]}`,
+		"clean.json": `{"image": "registry.bigbrother.io/db:3.0", "vulnerabilities": []}`,
+	}
+	var paths []string
+	for name, body := range reports {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, p)
+	}
+	vulns, err := loadScanReports(paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		ref, want string // want — текст находки, "" — находки нет
+	}{
+		{"registry.bigbrother.io/app:1.2", "image 'registry.bigbrother.io/app:1.2' has 2 critical vulnerabilities: CVE-2024-1, CVE-2024-2"},
+		// docker.io/library/nginx — тот же образ, что nginx в отчёте trivy
+		{"docker.io/library/nginx:1.25", "image 'docker.io/library/nginx:1.25' has 1 critical vulnerabilities: CVE-2023-9"},
+		{"registry.bigbrother.io/db:3.0", ""},
+		{"registry.bigbrother.io/unscanned:1.0", ""},
+	}
+	for _, tt := range tests {
+		res := &validator.Result{Images: []validator.Image{{Line: 3, Ref: tt.ref}}}
+		checkImageVulns(res, vulns)
+		got := ""
+		for _, is := range res.Issues {
+			if is.Code != validator.CodeImageVulnerable || is.Line != 3 || is.Severity != validator.SeverityError {
+				t.Errorf("%s: finding %+v, want %s error on line 3", tt.ref, is, validator.CodeImageVulnerable)
+			}
+			got = is.Message()
+		}
+		if got != tt.want {
+			t.Errorf("%s: %q, want %q", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestScanReportWithoutImage(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(p, []byte(`{"vulnerabilities": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadScanReports([]string{p}); err == nil {
+		t.Error("report without image accepted")
+	}
+}